@@ -1,6 +1,7 @@
 package aggregation
 
 import (
+	"context"
 	"math"
 	"strconv"
 
@@ -20,7 +21,8 @@ func Round(input, agg float64) float64 {
 
 // returns (agg, dip, pip, error)
 func Get(
-	exchange model.Exchange,
+	ctx context.Context,
+	exchange model.MarketData,
 	market string,
 	dip, pip float64,
 	max, min float64,
@@ -29,7 +31,7 @@ func Get(
 ) (float64, float64, float64, error) {
 	var (
 		err    error
-		client interface{}
+		client model.Client
 		ticker float64
 		stats  *model.Stats // 24-hour statistics
 		avg    float64      // 24-hour average
@@ -40,29 +42,30 @@ func Get(
 		return 0, dip, pip, err
 	}
 
-	if ticker, err = exchange.GetTicker(client, market); err != nil {
+	if ticker, err = exchange.GetTicker(ctx, client, market); err != nil {
 		return 0, dip, pip, err
 	}
 
-	if stats, err = exchange.Get24h(client, market); err != nil {
+	if stats, err = exchange.Get24h(ctx, client, market); err != nil {
 		return 0, dip, pip, err
 	}
 
-	if avg, err = stats.Avg(exchange, sandbox); err != nil {
+	if avg, err = stats.Avg(ctx, exchange, sandbox); err != nil {
 		return 0, dip, pip, err
 	}
 
-	if prec, err = exchange.GetPricePrec(client, market); err != nil {
+	if prec, err = exchange.GetPricePrec(ctx, client, market); err != nil {
 		return 0, dip, pip, err
 	}
 
-	return GetEx(exchange, client, market, ticker, avg, dip, pip, max, min, dist, prec, top, strict)
+	return GetEx(ctx, exchange, client, market, ticker, avg, dip, pip, max, min, dist, prec, top, strict)
 }
 
 // returns (agg, dip, pip, error)
 func GetEx(
-	exchange model.Exchange,
-	client interface{},
+	ctx context.Context,
+	exchange model.MarketData,
+	client model.Client,
 	market string,
 	ticker float64,
 	avg float64,
@@ -84,12 +87,12 @@ func GetEx(
 		return b
 	}
 
-	if book, err = exchange.GetBook(client, market, model.BOOK_SIDE_BIDS); err != nil {
+	if book, err = exchange.GetBook(ctx, client, market, model.BOOK_SIDE_BIDS); err != nil {
 		return 0, dip, pip, err
 	}
 
 	for cnt := Max(top, 4); cnt >= Max(top, 2); cnt-- {
-		if out, err = get(exchange, client, market, ticker, avg, book, dip, pip, max, min, dist, prec, cnt); err == nil {
+		if out, err = get(ctx, exchange, client, market, ticker, avg, book, dip, pip, max, min, dist, prec, cnt); err == nil {
 			return out, dip, pip, err
 		}
 	}
@@ -101,7 +104,7 @@ func GetEx(
 		for y < 50 {
 			y++
 			for cnt := Max(top, 4); cnt >= Max(top, 2); cnt-- {
-				if out, err = get(exchange, client, market, ticker, avg, book, x, y, max, min, dist, prec, cnt); err == nil {
+				if out, err = get(ctx, exchange, client, market, ticker, avg, book, x, y, max, min, dist, prec, cnt); err == nil {
 					return out, x, y, err
 				}
 			}
@@ -110,7 +113,7 @@ func GetEx(
 		for x > 0 {
 			x--
 			for cnt := Max(top, 4); cnt >= Max(top, 2); cnt-- {
-				if out, err = get(exchange, client, market, ticker, avg, book, x, y, max, min, dist, prec, cnt); err == nil {
+				if out, err = get(ctx, exchange, client, market, ticker, avg, book, x, y, max, min, dist, prec, cnt); err == nil {
 					return out, x, y, err
 				}
 			}
@@ -119,7 +122,7 @@ func GetEx(
 		for y < 100 {
 			y++
 			for cnt := Max(top, 4); cnt >= Max(top, 2); cnt-- {
-				if out, err = get(exchange, client, market, ticker, avg, book, x, y, max, min, dist, prec, cnt); err == nil {
+				if out, err = get(ctx, exchange, client, market, ticker, avg, book, x, y, max, min, dist, prec, cnt); err == nil {
 					return out, x, y, err
 				}
 			}
@@ -134,8 +137,9 @@ func GetEx(
 }
 
 func get(
-	exchange model.Exchange,
-	client interface{},
+	ctx context.Context,
+	exchange model.MarketData,
+	client model.Client,
 	market string,
 	ticker float64,
 	avg float64,
@@ -174,7 +178,7 @@ func get(
 				return 0, ECannotFindSupports
 			}
 
-			book2, err := exchange.Aggregate(client, book1, market, agg)
+			book2, err := exchange.Aggregate(ctx, client, book1, market, agg)
 			if err != nil {
 				return 0, err
 			}
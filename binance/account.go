@@ -0,0 +1,18 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package binance
+
+import (
+	exchange "github.com/adshao/go-binance/v2"
+)
+
+// Get current account information, including the commission rates and the balances.
+func (self *Client) Account() (*exchange.Account, error) {
+	defer AfterRequest()
+	BeforeRequest(self, Method[ACCOUNT], Path[ACCOUNT], Weight[ACCOUNT])
+	out, err := self.inner.NewGetAccountService().Do(self.context())
+	if err != nil {
+		self.handleError(err)
+		return nil, err
+	}
+	return out, nil
+}
@@ -2,7 +2,6 @@
 package binance
 
 import (
-	"context"
 	"fmt"
 
 	exchange "github.com/adshao/go-binance/v2"
@@ -22,7 +21,7 @@ func (self *Client) Depth(symbol string, limit int) (*exchange.DepthResponse, er
 	} else {
 		BeforeRequest(self, Method[DEPT], fmt.Sprintf(Path[DEPT], symbol), 50)
 	}
-	dept, err := self.inner.NewDepthService().Symbol(symbol).Limit(limit).Do(context.Background())
+	dept, err := self.inner.NewDepthService().Symbol(symbol).Limit(limit).Do(self.context())
 	self.handleError(err)
 	return dept, err
 }
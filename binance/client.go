@@ -4,10 +4,12 @@ package binance
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"log"
 	"time"
 
 	exchange "github.com/adshao/go-binance/v2"
+	"github.com/svanas/nefertiti/clock"
+	"github.com/svanas/nefertiti/transport"
 )
 
 const (
@@ -18,8 +20,30 @@ const (
 	BASE_URL_US = "https://api.binance.us"
 )
 
+// the maximum number of orders (or trades) we get per request
+const PAGE_SIZE = 1000
+
 type Client struct {
 	inner *exchange.Client
+	ctx   context.Context // the context our requests run in, see WithContext
+}
+
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (self *Client) Exchange() string {
+	return "Binance"
+}
+
+// WithContext returns a copy of the client whose requests run in ctx
+func (self *Client) WithContext(ctx context.Context) *Client {
+	return &Client{inner: self.inner, ctx: ctx}
+}
+
+// context returns the context our requests run in: the one we got from WithContext, or else our root context
+func (self *Client) context() context.Context {
+	if self.ctx != nil {
+		return self.ctx
+	}
+	return transport.Context()
 }
 
 // Get all account orders; active, canceled, or filled.
@@ -31,7 +55,7 @@ func (self *Client) Orders(symbol string) ([]Order, error) {
 	)
 	defer AfterRequest()
 	BeforeRequest(self, Method[ALL_ORDERS], fmt.Sprintf(Path[ALL_ORDERS], symbol), Weight[ALL_ORDERS])
-	if orders, err = self.inner.NewListOrdersService().Symbol(symbol).Do(context.Background()); err != nil {
+	if orders, err = self.inner.NewListOrdersService().Symbol(symbol).Do(self.context()); err != nil {
 		self.handleError(err)
 		return nil, err
 	}
@@ -45,6 +69,42 @@ func (self *Client) Orders(symbol string) ([]Order, error) {
 	return output, nil
 }
 
+// Get all account orders, not just the most recent orders; active, canceled, or filled.
+func (self *Client) AllOrders(symbol string) ([]Order, error) {
+	var (
+		err    error
+		orders []*exchange.Order
+		output []Order
+	)
+	for {
+		if orders, err = func() ([]*exchange.Order, error) {
+			defer AfterRequest()
+			BeforeRequest(self, Method[ALL_ORDERS], fmt.Sprintf(Path[ALL_ORDERS], symbol), Weight[ALL_ORDERS])
+			service := self.inner.NewListOrdersService().Symbol(symbol).Limit(PAGE_SIZE)
+			if len(output) > 0 {
+				service.OrderID(output[len(output)-1].OrderID + 1)
+			} else {
+				service.OrderID(0)
+			}
+			return service.Do(self.context())
+		}(); err != nil {
+			self.handleError(err)
+			return nil, err
+		}
+		for _, unwrapped := range orders {
+			var wrapped *Order
+			if wrapped, err = wrap(unwrapped); err != nil {
+				return nil, err
+			}
+			output = append(output, *wrapped)
+		}
+		if len(orders) < PAGE_SIZE {
+			break
+		}
+	}
+	return output, nil
+}
+
 // Get all open orders without a symbol.
 func (self *Client) OpenOrders() ([]Order, error) {
 	var (
@@ -54,7 +114,7 @@ func (self *Client) OpenOrders() ([]Order, error) {
 	)
 	defer AfterRequest()
 	BeforeRequest(self, Method[OPEN_ORDERS_WITHOUT_SYMBOL], Path[OPEN_ORDERS_WITHOUT_SYMBOL], Weight[OPEN_ORDERS_WITHOUT_SYMBOL])
-	if orders, err = self.inner.NewListOpenOrdersService().Do(context.Background()); err != nil {
+	if orders, err = self.inner.NewListOpenOrdersService().Do(self.context()); err != nil {
 		self.handleError(err)
 		return nil, err
 	}
@@ -77,7 +137,7 @@ func (self *Client) OpenOrdersEx(symbol string) ([]Order, error) {
 	)
 	defer AfterRequest()
 	BeforeRequest(self, Method[OPEN_ORDERS_WITH_SYMBOL], fmt.Sprintf(Path[OPEN_ORDERS_WITH_SYMBOL], symbol), Weight[OPEN_ORDERS_WITH_SYMBOL])
-	if orders, err = self.inner.NewListOpenOrdersService().Symbol(symbol).Do(context.Background()); err != nil {
+	if orders, err = self.inner.NewListOpenOrdersService().Symbol(symbol).Do(self.context()); err != nil {
 		self.handleError(err)
 		return nil, err
 	}
@@ -91,11 +151,23 @@ func (self *Client) OpenOrdersEx(symbol string) ([]Order, error) {
 	return output, nil
 }
 
+// Check an order's status.
+func (self *Client) GetOrder(symbol string, orderID int64) (*Order, error) {
+	defer AfterRequest()
+	BeforeRequest(self, Method[GET_ORDER], fmt.Sprintf(Path[GET_ORDER], symbol), Weight[GET_ORDER])
+	order, err := self.inner.NewGetOrderService().Symbol(symbol).OrderID(orderID).Do(self.context())
+	if err != nil {
+		self.handleError(err)
+		return nil, err
+	}
+	return wrap(order)
+}
+
 // Cancel an active order.
 func (self *Client) CancelOrder(symbol string, orderID int64) error {
 	defer AfterRequest()
 	BeforeRequest(self, Method[CANCEL_ORDER], fmt.Sprintf(Path[CANCEL_ORDER], symbol), Weight[CANCEL_ORDER])
-	_, err := self.inner.NewCancelOrderService().Symbol(symbol).OrderID(orderID).Do(context.Background())
+	_, err := self.inner.NewCancelOrderService().Symbol(symbol).OrderID(orderID).Do(self.context())
 	self.handleError(err)
 	return err
 }
@@ -116,36 +188,37 @@ func (self *Client) handleError(err error) {
 	if ok {
 		if binanceError.Code == -1021 {
 			// Timestamp for this request is outside of the recvWindow.
-			if offset, err := self.inner.NewSetServerTimeService().Do(context.Background()); err == nil {
-				SERVER_TIME_OFFSET = offset
-				SERVER_TIME_UPDATE = time.Now()
-				self.inner.TimeOffset = SERVER_TIME_OFFSET
-			}
+			Clock.Invalidate()
+			self.SyncTime()
 		}
 	}
 }
 
-var (
-	SERVER_TIME_OFFSET int64     // offset between device time and server time
-	SERVER_TIME_UPDATE time.Time // the last time we asked for server time
-)
+// Clock keeps track of the offset between our clock and the Binance clock
+var Clock = clock.New("Binance")
+
+// SyncTime measures the offset between our clock and the Binance clock (unless we did so lately), and then applies it to our requests
+func (self *Client) SyncTime() {
+	if err := Clock.Sync(func() (time.Time, error) {
+		ms, err := self.inner.NewServerTimeService().Do(self.context())
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(0, ms*int64(time.Millisecond)), nil
+	}); err != nil {
+		log.Printf("[WARNING] %v\n", err)
+	}
+	self.inner.TimeOffset = -Clock.Offset().Milliseconds()
+}
 
 func New(baseURL, apiKey, apiSecret string) *Client {
 	client := exchange.NewClient(apiKey, apiSecret)
 
 	client.BaseURL = baseURL
-	client.HTTPClient = &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	if SERVER_TIME_OFFSET == 0 || time.Since(SERVER_TIME_UPDATE).Minutes() > 15 {
-		if offset, err := client.NewSetServerTimeService().Do(context.Background()); err == nil {
-			SERVER_TIME_OFFSET = offset
-			SERVER_TIME_UPDATE = time.Now()
-		}
-	}
+	client.HTTPClient = transport.New()
 
-	client.TimeOffset = SERVER_TIME_OFFSET
+	out := &Client{inner: client}
+	out.SyncTime()
 
-	return &Client{inner: client}
+	return out
 }
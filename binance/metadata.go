@@ -34,6 +34,11 @@ func NewClientOrderMetadata(metadata string) (string, error) {
 	return out, nil
 }
 
+// IsClientOrderID returns true if the clientOrderId was created by NewClientOrderID (or NewClientOrderMetadata)
+func IsClientOrderID(clientOrderId string) bool {
+	return strings.HasPrefix(clientOrderId, fmt.Sprintf("x-%s-", BROKER))
+}
+
 // get metadata from order.ClientOrderId
 func ParseClientOrderMetadata(order *Order) (string, error) {
 	const MIN_LEN = 3
@@ -194,6 +194,27 @@ func (self *Order) GetSize() float64 {
 	return 0
 }
 
+// GetFilled returns the ExecutedQuantity as float64
+func (self *Order) GetFilled() float64 {
+	out, err := strconv.ParseFloat(self.ExecutedQuantity, 64)
+	if err == nil {
+		return out
+	}
+	return 0
+}
+
+// GetAvgPrice divides the CummulativeQuoteQuantity by the ExecutedQuantity
+func (self *Order) GetAvgPrice() float64 {
+	filled := self.GetFilled()
+	if filled > 0 {
+		cq, err := strconv.ParseFloat(self.CummulativeQuoteQuantity, 64)
+		if err == nil {
+			return cq / filled
+		}
+	}
+	return 0
+}
+
 // GetStopPrice returns the StopPrice as float64
 func (self *Order) GetStopPrice() float64 {
 	out, err := strconv.ParseFloat(self.StopPrice, 64)
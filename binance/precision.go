@@ -2,7 +2,6 @@
 package binance
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -51,7 +50,7 @@ func getPrecs(client *Client) (Precs, error) {
 	defer AfterRequest()
 	BeforeRequest(client, Method[EXCHANGE_INFO], Path[EXCHANGE_INFO], Weight[EXCHANGE_INFO])
 
-	info, err := client.inner.NewExchangeInfoService().Do(context.Background())
+	info, err := client.inner.NewExchangeInfoService().Do(client.context())
 	if err != nil {
 		client.handleError(err)
 		return nil, err
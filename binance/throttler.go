@@ -1,7 +1,6 @@
 package binance
 
 import (
-	"context"
 	"time"
 
 	exchange "github.com/adshao/go-binance/v2"
@@ -43,7 +42,7 @@ func GetRequestsPerSecond(client *Client, weight int) (float64, error) {
 	var out float64 = 20
 
 	if requestsPerSecond == 0 {
-		info, err := client.inner.NewExchangeInfoService().Do(context.Background())
+		info, err := client.inner.NewExchangeInfoService().Do(client.context())
 		if err != nil {
 			client.handleError(err)
 			return out, err
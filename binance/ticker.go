@@ -2,7 +2,6 @@
 package binance
 
 import (
-	"context"
 	"fmt"
 
 	exchange "github.com/adshao/go-binance/v2"
@@ -16,7 +15,7 @@ func (self *Client) Ticker(symbol string) (*exchange.PriceChangeStats, error) {
 	)
 	defer AfterRequest()
 	BeforeRequest(self, Method[TICKER_24H_WITH_SYMBOL], fmt.Sprintf(Path[TICKER_24H_WITH_SYMBOL], symbol), Weight[TICKER_24H_WITH_SYMBOL])
-	if stats, err = self.inner.NewListPriceChangeStatsService().Symbol(symbol).Do(context.Background()); err != nil {
+	if stats, err = self.inner.NewListPriceChangeStatsService().Symbol(symbol).Do(self.context()); err != nil {
 		self.handleError(err)
 		return nil, err
 	}
@@ -25,3 +24,15 @@ func (self *Client) Ticker(symbol string) (*exchange.PriceChangeStats, error) {
 	}
 	return stats[0], nil
 }
+
+// Kline/candlestick bars for a symbol.
+func (self *Client) Klines(symbol, interval string) ([]*exchange.Kline, error) {
+	defer AfterRequest()
+	BeforeRequest(self, Method[KLINES], fmt.Sprintf(Path[KLINES], symbol, interval), Weight[KLINES])
+	out, err := self.inner.NewKlinesService().Symbol(symbol).Interval(interval).Limit(1000).Do(self.context())
+	if err != nil {
+		self.handleError(err)
+		return nil, err
+	}
+	return out, nil
+}
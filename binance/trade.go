@@ -0,0 +1,47 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package binance
+
+import (
+	"fmt"
+
+	exchange "github.com/adshao/go-binance/v2"
+)
+
+// Get the trades (aka fills) of our orders on a symbol. If startTime is not zero, returns the trades from then on.
+func (self *Client) Trades(symbol string, startTime int64) ([]*exchange.TradeV3, error) {
+	var out []*exchange.TradeV3
+	for {
+		page, err := func() ([]*exchange.TradeV3, error) {
+			defer AfterRequest()
+			BeforeRequest(self, Method[MY_TRADES], fmt.Sprintf(Path[MY_TRADES], symbol), Weight[MY_TRADES])
+			service := self.inner.NewListTradesService().Symbol(symbol).Limit(PAGE_SIZE)
+			if len(out) > 0 {
+				// fromId cannot be combined with startTime
+				service.FromID(out[len(out)-1].ID + 1)
+			} else if startTime > 0 {
+				service.StartTime(startTime)
+			} else {
+				service.FromID(0)
+			}
+			return service.Do(self.context())
+		}()
+		if err != nil {
+			self.handleError(err)
+			return nil, err
+		}
+		out = append(out, page...)
+		if len(page) < PAGE_SIZE {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Get the trades (aka fills) of one order.
+func (self *Client) OrderTrades(symbol string, orderID int64) ([]*exchange.TradeV3, error) {
+	defer AfterRequest()
+	BeforeRequest(self, Method[MY_TRADES], fmt.Sprintf(Path[MY_TRADES], symbol), Weight[MY_TRADES])
+	out, err := self.inner.NewListTradesService().Symbol(symbol).OrderId(orderID).Do(self.context())
+	self.handleError(err)
+	return out, err
+}
@@ -7,12 +7,16 @@ import (
 type Request int
 
 const (
-	ALL_ORDERS Request = iota
+	ACCOUNT Request = iota
+	ALL_ORDERS
 	CANCEL_ORDER
 	CREATE_OCO_ORDER
 	CREATE_ORDER
 	DEPT
 	EXCHANGE_INFO
+	GET_ORDER
+	KLINES
+	MY_TRADES
 	OPEN_ORDERS_WITH_SYMBOL
 	OPEN_ORDERS_WITHOUT_SYMBOL
 	TICKER_24H_WITH_SYMBOL
@@ -20,12 +24,16 @@ const (
 )
 
 var Weight = map[Request]int{
+	ACCOUNT:                    10,
 	ALL_ORDERS:                 10,
 	CANCEL_ORDER:               1,
 	CREATE_OCO_ORDER:           1,
 	CREATE_ORDER:               1,
 	DEPT:                       1,
 	EXCHANGE_INFO:              10,
+	GET_ORDER:                  2,
+	KLINES:                     1,
+	MY_TRADES:                  10,
 	OPEN_ORDERS_WITH_SYMBOL:    3,
 	OPEN_ORDERS_WITHOUT_SYMBOL: 40,
 	TICKER_24H_WITH_SYMBOL:     1,
@@ -33,12 +41,16 @@ var Weight = map[Request]int{
 }
 
 var Method = map[Request]string{
+	ACCOUNT:                    http.MethodGet,
 	ALL_ORDERS:                 http.MethodGet,
 	CANCEL_ORDER:               http.MethodDelete,
 	CREATE_OCO_ORDER:           http.MethodPost,
 	CREATE_ORDER:               http.MethodPost,
 	DEPT:                       http.MethodGet,
 	EXCHANGE_INFO:              http.MethodGet,
+	GET_ORDER:                  http.MethodGet,
+	KLINES:                     http.MethodGet,
+	MY_TRADES:                  http.MethodGet,
 	OPEN_ORDERS_WITH_SYMBOL:    http.MethodGet,
 	OPEN_ORDERS_WITHOUT_SYMBOL: http.MethodGet,
 	TICKER_24H_WITH_SYMBOL:     http.MethodGet,
@@ -46,12 +58,16 @@ var Method = map[Request]string{
 }
 
 var Path = map[Request]string{
+	ACCOUNT:                    "/api/v3/account",
 	ALL_ORDERS:                 "/api/v3/allOrders?symbol=%s",
 	CANCEL_ORDER:               "/api/v3/order?symbol=%s",
 	CREATE_OCO_ORDER:           "/api/v3/order/oco?symbol=%s&side=%s&quantity=%f&price=%f&stopPrice=%f",
 	CREATE_ORDER:               "/api/v3/order?symbol=%s&side=%s&type=%s",
 	DEPT:                       "/api/v3/depth?symbol=%s",
 	EXCHANGE_INFO:              "/api/v3/exchangeInfo",
+	GET_ORDER:                  "/api/v3/order?symbol=%s",
+	KLINES:                     "/api/v3/klines?symbol=%s&interval=%s",
+	MY_TRADES:                  "/api/v3/myTrades?symbol=%s",
 	OPEN_ORDERS_WITH_SYMBOL:    "/api/v3/openOrders?symbol=%s",
 	OPEN_ORDERS_WITHOUT_SYMBOL: "/api/v3/openOrders",
 	TICKER_24H_WITH_SYMBOL:     "/api/v3/ticker/24hr?symbol=%s",
@@ -0,0 +1,59 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/svanas/nefertiti/errors"
+)
+
+type Balance struct {
+	Currency  string
+	Total     float64
+	Available float64
+	Reserved  float64
+}
+
+// Balances returns the balances of every currency that we hold (or ever held)
+func (client *Client) Balances() ([]Balance, error) {
+	var err error
+
+	var body []byte
+	if body, err = client.post("/balance/", url.Values{}); err != nil {
+		return nil, err
+	}
+
+	// for example: {"btc_balance": "0.1", "btc_available": "0.1", "btc_reserved": "0", "btcusd_fee": "0.5"}
+	var resp map[string]interface{}
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	parse := func(key string) float64 {
+		switch value := resp[key].(type) {
+		case string:
+			out, _ := strconv.ParseFloat(value, 64)
+			return out
+		case float64:
+			return value
+		}
+		return 0
+	}
+
+	var out []Balance
+	for key := range resp {
+		if strings.HasSuffix(key, "_balance") {
+			curr := strings.TrimSuffix(key, "_balance")
+			out = append(out, Balance{
+				Currency:  strings.ToUpper(curr),
+				Total:     parse(key),
+				Available: parse(curr + "_available"),
+				Reserved:  parse(curr + "_reserved"),
+			})
+		}
+	}
+
+	return out, nil
+}
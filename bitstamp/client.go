@@ -1,6 +1,7 @@
 package bitstamp
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/transport"
 	"github.com/svanas/nefertiti/uuid"
 )
 
@@ -49,14 +51,24 @@ type Client struct {
 	httpClient *http.Client
 }
 
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (client *Client) Exchange() string {
+	return "Bitstamp"
+}
+
+// WithContext returns a copy of the client whose requests are cancelled once ctx is done
+func (client *Client) WithContext(ctx context.Context) *Client {
+	out := *client
+	out.httpClient = transport.WithContext(client.httpClient, ctx)
+	return &out
+}
+
 func New(apiKey, apiSecret string) *Client {
 	return &Client{
-		URL:    Endpoint,
-		Key:    apiKey,
-		Secret: apiSecret,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		URL:        Endpoint,
+		Key:        apiKey,
+		Secret:     apiSecret,
+		httpClient: transport.New(),
 	}
 }
 
@@ -0,0 +1,86 @@
+package bitstamp
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/empty"
+	"github.com/svanas/nefertiti/errors"
+)
+
+const (
+	ORDER_STATUS_OPEN     = "Open"
+	ORDER_STATUS_FINISHED = "Finished"
+	ORDER_STATUS_CANCELED = "Canceled"
+)
+
+type OrderStatus struct {
+	Id              json.Number   `json:"id"`
+	DateTime        string        `json:"datetime"`
+	Type            int           `json:"type,string"`
+	Status          string        `json:"status"`
+	Market          string        `json:"market"` // for example: BTC/USD
+	Transactions    []Transaction `json:"transactions"`
+	AmountRemaining float64       `json:"amount_remaining,string"`
+	ClientOrderId   string        `json:"client_order_id"`
+}
+
+func (order *OrderStatus) Side() string {
+	return (&Order{Type: order.Type}).Side()
+}
+
+// Base returns the (lower case) base currency, for example: btc
+func (order *OrderStatus) Base() string {
+	return strings.ToLower(strings.Split(order.Market, "/")[0])
+}
+
+// Quote returns the (lower case) quote currency, for example: usd
+func (order *OrderStatus) Quote() string {
+	if i := strings.Index(order.Market, "/"); i != -1 {
+		return strings.ToLower(order.Market[i+1:])
+	}
+	return ""
+}
+
+func (order *OrderStatus) GetDateTime() time.Time {
+	out, _ := time.Parse(TimeFormat, order.DateTime)
+	return out
+}
+
+// Filled returns the amount that has been filled, the average price of the fills, and the fees we paid (in the quote currency)
+func (order *OrderStatus) Filled() (filled, price, fee float64) {
+	var value float64
+	for _, transaction := range order.Transactions {
+		amount := math.Abs(empty.AsFloat64(transaction[order.Base()]))
+		filled += amount
+		value += amount * empty.AsFloat64(transaction["price"])
+		fee += empty.AsFloat64(transaction["fee"])
+	}
+	if filled > 0 {
+		price = value / filled
+	}
+	return
+}
+
+// OrderStatus returns the status of an order, including the transactions (aka fills) of the order.
+func (client *Client) OrderStatus(id string) (*OrderStatus, error) {
+	var err error
+
+	v := url.Values{}
+	v.Add("id", id)
+
+	var body []byte
+	if body, err = client.post("/order_status/", v); err != nil {
+		return nil, err
+	}
+
+	var out OrderStatus
+	if err = json.Unmarshal(body, &out); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	return &out, nil
+}
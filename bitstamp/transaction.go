@@ -98,6 +98,29 @@ func (transaction *Transaction) Side(client *Client) (string, error) {
 	}
 }
 
+// Fee returns the fee we paid, in the quote currency
+func (transaction *Transaction) Fee() float64 {
+	return empty.AsFloat64((*transaction)["fee"])
+}
+
+// Quote returns the quote currency of the market of this transaction
+func (transaction *Transaction) Quote(client *Client) string {
+	cached := true
+	for {
+		markets, _ := GetMarkets(client, cached)
+		for i := range markets {
+			price := (*transaction)[fmt.Sprintf("%s_%s", markets[i].Base, markets[i].Quote)]
+			if empty.AsString(price) != "" {
+				return markets[i].Quote
+			}
+		}
+		if !cached {
+			return ""
+		}
+		cached = false
+	}
+}
+
 func (transaction *Transaction) DateTime() time.Time {
 	dt := (*transaction)["datetime"]
 	var out time.Time
@@ -0,0 +1,8 @@
+package bittrex
+
+type Balance struct {
+	CurrencySymbol string  `json:"currencySymbol"`
+	Total          float64 `json:"total,string"`
+	Available      float64 `json:"available,string"`
+	UpdatedAt      string  `json:"updatedAt"`
+}
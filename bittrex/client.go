@@ -3,6 +3,7 @@ package bittrex
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/hex"
@@ -14,6 +15,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/svanas/nefertiti/transport"
 )
 
 const (
@@ -120,14 +123,24 @@ type Client struct {
 	httpClient *http.Client
 }
 
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (client *Client) Exchange() string {
+	return "Bittrex"
+}
+
+// WithContext returns a copy of the client whose requests are cancelled once ctx is done
+func (client *Client) WithContext(ctx context.Context) *Client {
+	out := *client
+	out.httpClient = transport.WithContext(client.httpClient, ctx)
+	return &out
+}
+
 func New(apiKey, apiSecret, appId string) *Client {
 	return &Client{
 		apiKey,
 		apiSecret,
 		appId,
-		&http.Client{
-			Timeout: 30 * time.Second,
-		},
+		transport.New(),
 	}
 }
 
@@ -243,6 +256,17 @@ func (client *Client) GetMarkets() (markets []Market, err error) {
 	return markets, err
 }
 
+func (client *Client) GetBalances() (balances []Balance, err error) {
+	var data []byte
+	if data, err = client.do("GET", "balances", nil, true); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &balances); err != nil {
+		return nil, err
+	}
+	return balances, err
+}
+
 func (client *Client) GetTicker(market string) (*Ticker, error) {
 	var (
 		err  error
@@ -57,12 +57,15 @@ const (
 	IOC
 	// This option allows orders to be placed which will be filled immediately and completely, or not at all.
 	FOK
+	// Like GTC, but the order will be cancelled if it would be filled immediately.
+	POST_ONLY_GTC
 )
 
 var TimeInForceString = map[TimeInForce]string{
-	GTC: "GOOD_TIL_CANCELLED",
-	IOC: "IMMEDIATE_OR_CANCEL",
-	FOK: "FILL_OR_KILL",
+	GTC:           "GOOD_TIL_CANCELLED",
+	IOC:           "IMMEDIATE_OR_CANCEL",
+	FOK:           "FILL_OR_KILL",
+	POST_ONLY_GTC: "POST_ONLY_GOOD_TIL_CANCELLED",
 }
 
 func (tif *TimeInForce) String() string {
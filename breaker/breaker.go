@@ -0,0 +1,128 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package breaker
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/notify"
+	"github.com/svanas/nefertiti/shutdown"
+)
+
+const (
+	DEFAULT_THRESHOLD = 5           // consecutive failures before the breaker opens
+	PROBE_INTERVAL    = time.Minute // time between attempts while the breaker is open
+)
+
+// Breaker pauses our loops when an exchange is down. It opens after a number of consecutive (retryable) failures,
+// and then lets one iteration through every PROBE_INTERVAL. It closes again after the first iteration that succeeds.
+type Breaker struct {
+	name      string
+	mutex     sync.Mutex
+	threshold int64
+	failures  int64
+	openedAt  time.Time // zero if the breaker is closed
+	probeAt   time.Time // the next time we let an iteration through
+}
+
+var (
+	breakers = make(map[string]*Breaker)
+	mutex    sync.Mutex
+)
+
+// threshold returns the --breaker=X arg. Defaults to 5 consecutive failures. Zero disables the breaker.
+func threshold() int64 {
+	if arg := flag.Get("breaker"); arg.Exists {
+		if out, err := arg.Int64(); err == nil && out >= 0 {
+			return out
+		}
+	}
+	return DEFAULT_THRESHOLD
+}
+
+// Get returns the breaker of an exchange
+func Get(name string) *Breaker {
+	mutex.Lock()
+	defer mutex.Unlock()
+	out, ok := breakers[name]
+	if !ok {
+		out = &Breaker{name: name, threshold: threshold()}
+		breakers[name] = out
+	}
+	return out
+}
+
+// IsOpen returns true if the breaker of an exchange is open
+func IsOpen(name string) bool {
+	mutex.Lock()
+	out, ok := breakers[name]
+	mutex.Unlock()
+	return ok && out.IsOpen()
+}
+
+func (self *Breaker) IsOpen() bool {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return !self.openedAt.IsZero()
+}
+
+// Wait blocks for as long as the breaker is open and our next probe isn't due yet. Returns false if we are asked to stop.
+func (self *Breaker) Wait() bool {
+	self.mutex.Lock()
+	var wait time.Duration
+	if !self.openedAt.IsZero() {
+		wait = time.Until(self.probeAt)
+	}
+	self.mutex.Unlock()
+	if wait > 0 {
+		return shutdown.Sleep(wait)
+	}
+	return !shutdown.Stopping()
+}
+
+// Done records the outcome of an iteration. Only the retryable errors count as a failure, and only a success tells us the
+// exchange is up again; any other error leaves the breaker open (or closed) until the next probe. We send one notification when the breaker opens,
+// and one when it closes again.
+func (self *Breaker) Done(err error, service model.Notify, level int64) {
+	var msg, title string
+
+	self.mutex.Lock()
+	if self.threshold == 0 {
+		self.mutex.Unlock()
+		return
+	}
+	if err == nil {
+		self.failures = 0
+		if !self.openedAt.IsZero() {
+			msg = fmt.Sprintf("%s has recovered after %v.", self.name, time.Since(self.openedAt).Round(time.Second))
+			title = self.name + " - INFO"
+			self.openedAt = time.Time{}
+			log.Println("[INFO] " + msg)
+		}
+	} else if errors.ClassIn(self.name, err).Retryable() {
+		self.failures++
+		self.probeAt = time.Now().Add(PROBE_INTERVAL)
+		if self.openedAt.IsZero() && self.failures >= self.threshold {
+			self.openedAt = time.Now()
+			msg = fmt.Sprintf("%s is degraded after %d consecutive errors. Last error: %v. Pausing until it recovers.", self.name, self.failures, err)
+			title = self.name + " - ERROR"
+			log.Println("[WARNING] " + msg)
+		}
+	} else if !self.openedAt.IsZero() {
+		// the probe has failed, so we wait for the next one (or else we would keep going without ever pausing)
+		self.probeAt = time.Now().Add(PROBE_INTERVAL)
+	}
+	self.mutex.Unlock()
+
+	// the user who got told that the exchange is degraded, wants to know when it has recovered
+	if msg != "" && service != nil && notify.CanSend(level, notify.ERROR) {
+		if err := service.SendMessage(msg, title, model.ALWAYS); err != nil {
+			log.Printf("[ERROR] %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+)
+
+func TestBreaker(t *testing.T) {
+	cb := &Breaker{name: "test", threshold: 3}
+	transient := errors.New("502 Bad Gateway")
+	other := errors.New("market BTC-EUR does not exist")
+
+	cb.Done(transient, nil, 0)
+	cb.Done(other, nil, 0)
+	cb.Done(transient, nil, 0)
+	if cb.IsOpen() {
+		t.Errorf("TestBreaker failed, got: open, want: closed after %d failures.", cb.threshold-1)
+	}
+
+	// a failure that is not retryable does not count, but it doesn't tell us the exchange is up either
+	cb.Done(transient, nil, 0)
+	if !cb.IsOpen() {
+		t.Errorf("TestBreaker failed, got: closed, want: open after %d retryable failures.", cb.threshold)
+	}
+
+	cb.Done(other, nil, 0)
+	if !cb.IsOpen() {
+		t.Errorf("TestBreaker failed, got: closed, want: open after a failure that is not retryable.")
+	}
+
+	cb.Done(nil, nil, 0)
+	if cb.IsOpen() {
+		t.Errorf("TestBreaker failed, got: open, want: closed after a success.")
+	}
+}
+
+func TestBreakerProbe(t *testing.T) {
+	cb := &Breaker{name: "test", threshold: 1}
+	cb.Done(errors.New("502 Bad Gateway"), nil, 0)
+
+	// the probe is due, and fails for a reason that is not retryable
+	cb.probeAt = time.Now()
+	cb.Done(errors.New("market BTC-EUR does not exist"), nil, 0)
+	if !cb.IsOpen() {
+		t.Fatalf("TestBreakerProbe failed, got: closed, want: open after a failure that is not retryable.")
+	}
+	if wait := time.Until(cb.probeAt); wait < PROBE_INTERVAL-time.Second {
+		t.Errorf("TestBreakerProbe failed, got: next probe in %v, want: next probe in %v.", wait, PROBE_INTERVAL)
+	}
+}
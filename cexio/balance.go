@@ -0,0 +1,49 @@
+package cexio
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+type Balance struct {
+	Currency  string
+	Available float64
+	Orders    float64 // reserved for our open orders
+}
+
+// Balances returns the balances of every currency
+func (client *Client) Balances() ([]Balance, error) {
+	var err error
+
+	var body []byte
+	if body, err = client.query("balance/", nil, true); err != nil {
+		return nil, err
+	}
+
+	// for example: {"timestamp": "1513177918", "username": "ud000000000", "BTC": {"available": "1.38", "orders": "0.00"}}
+	var resp map[string]json.RawMessage
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.New(err.Error() + ": " + string(body))
+	}
+
+	var out []Balance
+	for curr, raw := range resp {
+		var balance struct {
+			Available string `json:"available"`
+			Orders    string `json:"orders"`
+		}
+		if json.Unmarshal(raw, &balance) != nil {
+			continue // not a currency
+		}
+		available, _ := strconv.ParseFloat(balance.Available, 64)
+		orders, _ := strconv.ParseFloat(balance.Orders, 64)
+		out = append(out, Balance{
+			Currency:  curr,
+			Available: available,
+			Orders:    orders,
+		})
+	}
+
+	return out, nil
+}
@@ -1,6 +1,7 @@
 package cexio
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -13,6 +14,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/svanas/nefertiti/transport"
 )
 
 const (
@@ -47,15 +50,25 @@ type Client struct {
 	httpClient *http.Client
 }
 
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (client *Client) Exchange() string {
+	return "CEX.IO"
+}
+
+// WithContext returns a copy of the client whose requests are cancelled once ctx is done
+func (client *Client) WithContext(ctx context.Context) *Client {
+	out := *client
+	out.httpClient = transport.WithContext(client.httpClient, ctx)
+	return &out
+}
+
 func New(apiKey, apiSecret, userName string) *Client {
 	return &Client{
-		URL:      Endpoint,
-		Key:      apiKey,
-		Secret:   apiSecret,
-		UserName: userName,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		URL:        Endpoint,
+		Key:        apiKey,
+		Secret:     apiSecret,
+		UserName:   userName,
+		httpClient: transport.New(),
 	}
 }
 
@@ -327,6 +340,26 @@ func (client *Client) ArchivedOrdersAll() ([]Order, error) {
 	return result, nil
 }
 
+func (client *Client) GetOrder(id string) (*Order, error) {
+	var err error
+
+	var params = map[string]string{
+		"id": id,
+	}
+
+	var body []byte
+	if body, err = client.query("get_order/", params, true); err != nil {
+		return nil, err
+	}
+
+	var output Order
+	if err = json.Unmarshal(body, &output); err != nil {
+		return nil, errors.New(err.Error() + ": " + string(body))
+	}
+
+	return &output, nil
+}
+
 func (client *Client) CancelOrder(id string) error {
 	var err error
 
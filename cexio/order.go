@@ -1,6 +1,7 @@
 package cexio
 
 import (
+	"encoding/json"
 	"strconv"
 	"time"
 
@@ -43,6 +44,41 @@ type Order struct {
 	Pending float64     `json:"pending,string"`
 	Symbol1 string      `json:"symbol1"`
 	Symbol2 string      `json:"symbol2"`
+	Status  string      `json:"status,omitempty"` // a = active, d = done, c = cancelled, cd = cancelled but partially done
+	Remains interface{} `json:"remains,omitempty"`
+	Fee     float64     `json:"-"` // the fee we paid, in symbol2
+}
+
+const (
+	ORDER_STATUS_ACTIVE                   = "a"
+	ORDER_STATUS_DONE                     = "d"
+	ORDER_STATUS_CANCELLED                = "c"
+	ORDER_STATUS_CANCELLED_PARTIALLY_DONE = "cd"
+)
+
+func (order *Order) UnmarshalJSON(data []byte) error {
+	type Alias Order
+	if err := json.Unmarshal(data, (*Alias)(order)); err != nil {
+		return err
+	}
+	// the fee is in a key that includes symbol2, for example: "tfa:USD" (total fee amount)
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	order.Fee = empty.AsFloat64(raw["tfa:"+order.Symbol2])
+	if order.Fee == 0 {
+		order.Fee = empty.AsFloat64(raw["fa:"+order.Symbol2])
+	}
+	return nil
+}
+
+// GetRemains returns the amount that hasn't been filled yet
+func (order *Order) GetRemains() float64 {
+	if order.Remains == nil {
+		return order.Pending
+	}
+	return empty.AsFloat64(order.Remains)
 }
 
 func (order *Order) Side() Side {
@@ -0,0 +1,84 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package clock
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	RESYNC_INTERVAL = 15 * time.Minute // how often we measure the offset between our clock and the exchange clock
+	RETRY_INTERVAL  = time.Minute      // how long we wait before we measure again, if we could not reach the exchange
+	SKEW_THRESHOLD  = time.Second      // we warn the user if their clock is off by more than this
+)
+
+// ServerTime returns the time of an exchange server
+type ServerTime func() (time.Time, error)
+
+// Clock keeps track of the offset between our clock and the clock of an exchange, so that we can sign our requests
+// with a timestamp that the exchange agrees with.
+type Clock struct {
+	name     string
+	mutex    sync.Mutex
+	offset   time.Duration // server time minus local time
+	syncedAt time.Time     // zero if we need to measure (again) before our next request
+	syncing  bool
+}
+
+func New(name string) *Clock {
+	return &Clock{name: name}
+}
+
+// Now returns our time, adjusted to the exchange clock
+func (self *Clock) Now() time.Time {
+	return time.Now().Add(self.Offset())
+}
+
+// Offset returns the exchange time minus our time
+func (self *Clock) Offset() time.Duration {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.offset
+}
+
+// Invalidate makes us measure the offset again before our next request, for example: after the exchange rejected our timestamp
+func (self *Clock) Invalidate() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.syncedAt = time.Time{}
+}
+
+// Sync measures the offset between our clock and the exchange clock, unless we did so lately (or are doing so as we speak)
+func (self *Clock) Sync(serverTime ServerTime) error {
+	self.mutex.Lock()
+	if self.syncing || (!self.syncedAt.IsZero() && time.Since(self.syncedAt) < RESYNC_INTERVAL) {
+		self.mutex.Unlock()
+		return nil
+	}
+	self.syncing = true
+	self.mutex.Unlock()
+
+	// assume the server read its clock halfway through our request
+	before := time.Now()
+	server, err := serverTime()
+	after := time.Now()
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.syncing = false
+
+	if err != nil {
+		self.syncedAt = time.Now().Add(RETRY_INTERVAL - RESYNC_INTERVAL)
+		return err
+	}
+
+	self.offset = server.Sub(before.Add(after.Sub(before) / 2))
+	self.syncedAt = time.Now()
+
+	if self.offset > SKEW_THRESHOLD || self.offset < -SKEW_THRESHOLD {
+		log.Printf("[WARNING] your clock is %v off from the %s clock. Please sync your clock.\n", self.offset.Round(time.Millisecond), self.name)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSync(t *testing.T) {
+	clock := New("test")
+	calls := 0
+	serverTime := func() (time.Time, error) {
+		calls++
+		return time.Now().Add(5 * time.Second), nil
+	}
+
+	if err := clock.Sync(serverTime); err != nil {
+		t.Fatal(err)
+	}
+	if offset := clock.Offset(); offset < 4*time.Second || offset > 6*time.Second {
+		t.Errorf("TestSync failed, got: %v, want: 5s.", offset)
+	}
+
+	// we synced lately, so this should not ask for the server time
+	clock.Sync(serverTime)
+	if calls != 1 {
+		t.Errorf("TestSync failed, got: %d calls, want: 1 call.", calls)
+	}
+
+	// after the exchange rejected our timestamp, this should ask for the server time again
+	clock.Invalidate()
+	clock.Sync(serverTime)
+	if calls != 2 {
+		t.Errorf("TestSync failed, got: %d calls, want: 2 calls.", calls)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/svanas/nefertiti/exchanges"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -17,12 +18,14 @@ type (
 )
 
 func (c *AggCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	exchange, err := exchanges.GetExchange()
 	if err != nil {
 		return c.ReturnError(err)
 	}
 
-	market, err := model.GetMarket(exchange)
+	market, err := model.GetMarket(ctx, exchange)
 	if err != nil {
 		return c.ReturnError(err)
 	}
@@ -52,7 +55,7 @@ func (c *AggCommand) Run(args []string) int {
 		return c.ReturnError(err)
 	}
 
-	agg, _, _, err := aggregation.Get(exchange, market, dip, pip, max, min, int(dist), 2, flag.Strict(), flag.Sandbox())
+	agg, _, _, err := aggregation.Get(ctx, exchange, market, dip, pip, max, min, int(dist), 2, flag.Strict(), flag.Sandbox())
 	if err != nil {
 		return c.ReturnError(err)
 	}
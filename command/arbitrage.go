@@ -0,0 +1,408 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/notify"
+	"github.com/svanas/nefertiti/precision"
+	"github.com/svanas/nefertiti/shutdown"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	ArbitrageCommand struct {
+		*CommandMeta
+	}
+)
+
+// buy on one exchange, sell on another exchange
+type arbitrageSpread struct {
+	Symbol string   `json:"symbol"`
+	Buy    *bookTop `json:"buy"`
+	Sell   *bookTop `json:"sell"`
+	Spread float64  `json:"spread"` // percentage, before fees
+	Profit float64  `json:"profit"` // percentage, after fees
+}
+
+// an exchange we're scanning, together with its client and its symbols
+type arbitrageVenue struct {
+	exchange model.Exchange
+	client   model.Client
+	symbols  model.Symbols
+}
+
+// the trading fee (in %) we pay when we cross the book on a market: --fee overrides what the exchange tells us, and
+// we assume the default fee if we don't know the fees on this exchange.
+func (venue *arbitrageVenue) fee(ctx context.Context, market string, override *float64) float64 {
+	if override != nil {
+		return *override
+	}
+	if fees := model.GetFees(ctx, venue.exchange, venue.client, market); fees.Taker > 0 {
+		return fees.Taker * 100
+	}
+	return arbitrageFee
+}
+
+// the trading fee (in %) we pay per order if the exchange doesn't tell us
+const arbitrageFee = 0.1
+
+// returns the spreads that exceed the fees (per side, in %) plus the threshold (in %)
+func arbitrageScan(ctx context.Context, venues []arbitrageVenue, quote model.Assets, fee *float64, threshold float64, debug bool) []arbitrageSpread {
+	// what symbols are traded on at least two exchanges?
+	count := make(map[string]int)
+	for _, venue := range venues {
+		for symbol := range venue.symbols {
+			count[symbol]++
+		}
+	}
+	var symbols []string
+	for symbol, n := range count {
+		if n > 1 && quote.HasAsset(symbol[strings.Index(symbol, "/")+1:]) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+
+	var out []arbitrageSpread
+	for _, symbol := range symbols {
+		var quotes []*bookTop
+		for _, venue := range venues {
+			market, ok := venue.symbols[symbol]
+			if !ok {
+				continue
+			}
+			q, err := getBookTop(ctx, venue.exchange, venue.client, market)
+			if err != nil {
+				log.Printf("[ERROR] %v. Exchange: %s. Market: %s\n", err, venue.exchange.GetInfo().Name, market)
+				continue
+			}
+			quotes = append(quotes, q)
+		}
+
+		var buy, sell *bookTop
+		for _, q := range quotes {
+			if q.Ask > 0 && (buy == nil || q.Ask < buy.Ask) {
+				buy = q
+			}
+			if q.Bid > 0 && (sell == nil || q.Bid > sell.Bid) {
+				sell = q
+			}
+		}
+		if buy == nil || sell == nil || buy == sell {
+			continue
+		}
+
+		spread := (sell.Bid - buy.Ask) / buy.Ask * 100
+		profit := spread - arbitrageVenueByName(venues, buy.Exchange).fee(ctx, buy.Market, fee) - arbitrageVenueByName(venues, sell.Exchange).fee(ctx, sell.Market, fee)
+		if debug {
+			log.Printf("[DEBUG] %s: buy on %s at %g, sell on %s at %g. Spread: %.2f%%\n", symbol, buy.Exchange, buy.Ask, sell.Exchange, sell.Bid, spread)
+		}
+		if profit > threshold {
+			out = append(out, arbitrageSpread{
+				Symbol: symbol,
+				Buy:    buy,
+				Sell:   sell,
+				Spread: spread,
+				Profit: profit,
+			})
+		}
+	}
+
+	return out
+}
+
+func arbitrageVenueByName(venues []arbitrageVenue, name string) *arbitrageVenue {
+	for i := range venues {
+		if venues[i].exchange.GetInfo().Name == name {
+			return &venues[i]
+		}
+	}
+	return nil
+}
+
+// returns the available balance of an asset on an exchange
+func arbitrageAvailable(ctx context.Context, venue *arbitrageVenue, asset string) (float64, error) {
+	balances, err := venue.exchange.GetBalances(ctx, venue.client)
+	if err != nil {
+		return 0, err
+	}
+	if i := balances.IndexByAsset(asset); i > -1 {
+		return balances[i].Available, nil
+	}
+	return 0, nil
+}
+
+// buys on the cheap exchange, sells on the expensive exchange. size is in quote currency. we validate both legs before
+// we place either of them, and we unwind the buy if the sell fails.
+func arbitrageTrade(ctx context.Context, venues []arbitrageVenue, spread *arbitrageSpread, size float64, fee *float64) error {
+	var err error
+
+	buy := arbitrageVenueByName(venues, spread.Buy.Exchange)
+	sell := arbitrageVenueByName(venues, spread.Sell.Exchange)
+	if buy == nil || sell == nil {
+		return errors.Errorf("exchange %s or %s does not exist", spread.Buy.Exchange, spread.Sell.Exchange)
+	}
+
+	base := spread.Symbol[:strings.Index(spread.Symbol, "/")]
+	quote := spread.Symbol[strings.Index(spread.Symbol, "/")+1:]
+
+	var prec1, prec2 int
+	if prec1, err = buy.exchange.GetSizePrec(ctx, buy.client, spread.Buy.Market); err != nil {
+		return err
+	}
+	if prec2, err = sell.exchange.GetSizePrec(ctx, sell.client, spread.Sell.Market); err != nil {
+		return err
+	}
+
+	var price1, price2 int
+	if price1, err = buy.exchange.GetPricePrec(ctx, buy.client, spread.Buy.Market); err != nil {
+		return err
+	}
+	if price2, err = sell.exchange.GetPricePrec(ctx, sell.client, spread.Sell.Market); err != nil {
+		return err
+	}
+	ask := precision.Round(spread.Buy.Ask, price1)
+	bid := precision.Round(spread.Sell.Bid, price2)
+
+	// we cannot spend more quote than we have on the cheap exchange, and we cannot sell more base than we have on the expensive exchange
+	var available1, available2 float64
+	if available1, err = arbitrageAvailable(ctx, buy, quote); err != nil {
+		return errors.Errorf("%v. Exchange: %s", err, spread.Buy.Exchange)
+	}
+	if available2, err = arbitrageAvailable(ctx, sell, base); err != nil {
+		return errors.Errorf("%v. Exchange: %s", err, spread.Sell.Exchange)
+	}
+
+	// we cannot take more than the top of both books
+	qty := math.Min(size/ask, math.Min(spread.Buy.AskSize, spread.Sell.BidSize))
+	qty = math.Min(qty, math.Min(available1/(ask*(1+buy.fee(ctx, spread.Buy.Market, fee)/100)), available2))
+	qty = precision.Floor(qty, int(math.Min(float64(prec1), float64(prec2))))
+	if qty <= 0 {
+		return errors.Errorf("nothing to trade. Symbol: %s. Available: %g %s on %s, %g %s on %s", spread.Symbol, available1, quote, spread.Buy.Exchange, available2, base, spread.Sell.Exchange)
+	}
+
+	var oid []byte
+	if oid, _, err = buy.exchange.Order(ctx, buy.client, model.BUY, spread.Buy.Market, qty, ask, model.LIMIT, nil, ""); err != nil {
+		return errors.Errorf("%v. Exchange: %s", err, spread.Buy.Exchange)
+	}
+	if _, _, err = sell.exchange.Order(ctx, sell.client, model.SELL, spread.Sell.Market, qty, bid, model.LIMIT, nil, ""); err != nil {
+		return arbitrageUnwind(ctx, buy, spread.Buy.Market, string(oid), errors.Errorf("%v. Exchange: %s", err, spread.Sell.Exchange))
+	}
+
+	return nil
+}
+
+// cancels the buy leg of a trade that we could not sell, and sells back whatever got filled. returns the error that got
+// us here, together with the exposure we're left with.
+func arbitrageUnwind(ctx context.Context, venue *arbitrageVenue, market, id string, cause error) error {
+	name := venue.exchange.GetInfo().Name
+
+	if err := venue.exchange.CancelOrder(ctx, venue.client, market, id); err != nil {
+		log.Printf("[ERROR] %v. Exchange: %s. Market: %s. Order: %s\n", err, name, market, id)
+	}
+
+	order, err := venue.exchange.GetOrder(ctx, venue.client, market, id)
+	if err != nil {
+		return errors.Errorf("%v. Could not unwind buy order %s on %s: %v", cause, id, name, err)
+	}
+	if order.Filled <= 0 {
+		return errors.Errorf("%v. Cancelled buy order %s on %s", cause, id, name)
+	}
+
+	var top *bookTop
+	if top, err = getBookTop(ctx, venue.exchange, venue.client, market); err == nil {
+		_, _, err = venue.exchange.Order(ctx, venue.client, model.SELL, market, order.Filled, top.Bid, model.LIMIT, nil, "")
+	}
+	if err != nil {
+		return errors.Errorf("%v. Exposure: bought %g on %s (%s) that we could not sell back: %v", cause, order.Filled, name, market, err)
+	}
+
+	return errors.Errorf("%v. Sold %g back on %s (%s) at %g", cause, order.Filled, name, market, top.Bid)
+}
+
+func (c *ArbitrageCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	var (
+		err error
+		flg *flag.Flag
+	)
+
+	var all exchanges.Exchanges
+	if all, err = exchanges.GetExchanges(); err != nil {
+		return c.ReturnError(err)
+	}
+	if len(all) < 2 {
+		return c.ReturnError(errors.New("please specify at least two exchanges"))
+	}
+
+	// --quote=X,Y,Z
+	flg = flag.Get("quote")
+	if !flg.Exists {
+		return c.ReturnError(errors.New("missing argument: quote"))
+	}
+	quote := model.Assets(flg.Split())
+
+	// --fee=X (in %)
+	var fee *float64
+	flg = flag.Get("fee")
+	if flg.Exists {
+		var pct float64
+		if pct, err = flg.Float64(); err != nil || pct < 0 {
+			return c.ReturnError(errors.Errorf("fee %v is invalid", flg))
+		}
+		fee = &pct
+	}
+
+	// --threshold=X (in %)
+	var threshold float64 = 0.5
+	flg = flag.Get("threshold")
+	if flg.Exists {
+		if threshold, err = flg.Float64(); err != nil {
+			return c.ReturnError(errors.Errorf("threshold %v is invalid", flg))
+		}
+	}
+
+	// --trade --size=X (in quote currency)
+	trade := flag.Exists("trade")
+	var size float64
+	if trade {
+		flg = flag.Get("size")
+		if !flg.Exists {
+			return c.ReturnError(errors.New("missing argument: size"))
+		}
+		if size, err = flg.Float64(); err != nil || size <= 0 {
+			return c.ReturnError(errors.Errorf("size %v is invalid", flg))
+		}
+	}
+
+	var venues []arbitrageVenue
+	for _, exchange := range all {
+		venue := arbitrageVenue{exchange: exchange}
+		if trade {
+			if venue.client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
+				return c.ReturnError(err)
+			}
+		} else {
+			if venue.client, err = exchange.GetClient(model.BOOK, flag.Sandbox()); err != nil {
+				return c.ReturnError(err)
+			}
+		}
+		var markets []model.Market
+		if markets, err = exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split()); err != nil {
+			return c.ReturnError(err)
+		}
+		venue.symbols = model.NewSymbols(markets)
+		venues = append(venues, venue)
+	}
+
+	// --repeat=X (in minutes)
+	flg = flag.Get("repeat")
+	if !flg.Exists {
+		spreads := arbitrageScan(ctx, venues, quote, fee, threshold, flag.Debug())
+		if trade {
+			for i := range spreads {
+				if err = arbitrageTrade(ctx, venues, &spreads[i], size, fee); err != nil {
+					return c.ReturnError(err)
+				}
+			}
+		}
+		var out []byte
+		if out, err = json.Marshal(spreads); err != nil {
+			return c.ReturnError(err)
+		}
+		fmt.Println(string(out))
+		return 0
+	}
+
+	var repeat float64
+	if repeat, err = flg.Float64(); err != nil || repeat <= 0 {
+		return c.ReturnError(errors.Errorf("repeat %v is invalid", flg))
+	}
+
+	var service model.Notify = nil
+	if service, err = notify.New().Init(flag.Interactive(), true); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var level int64 = notify.LEVEL_DEFAULT
+	if level, err = notify.Level(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	log.Println("[INFO] Scanning for arbitrage...")
+	if err = c.ReturnSuccess(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	for {
+		for _, spread := range arbitrageScan(ctx, venues, quote, fee, threshold, flag.Debug()) {
+			msg := fmt.Sprintf("%s: buy on %s at %g, sell on %s at %g. Profit: %.2f%%", spread.Symbol, spread.Buy.Exchange, spread.Buy.Ask, spread.Sell.Exchange, spread.Sell.Bid, spread.Profit)
+			log.Println("[INFO] " + msg)
+			if service != nil && notify.CanSend(level, notify.INFO) {
+				if err = service.SendMessage(msg, "Arbitrage - INFO", model.ONCE_PER_MINUTE); err != nil {
+					log.Printf("[ERROR] %v\n", err)
+				}
+			}
+			if trade {
+				if err = arbitrageTrade(ctx, venues, &spread, size, fee); err != nil {
+					log.Printf("[ERROR] %v\n", err)
+					if service != nil && notify.CanSend(level, notify.ERROR) {
+						service.SendMessage(err.Error(), "Arbitrage - ERROR", model.ONCE_PER_MINUTE)
+					}
+				}
+			}
+		}
+		if !shutdown.Sleep(time.Duration(repeat * float64(time.Minute))) {
+			return 0
+		}
+	}
+}
+
+func (c *ArbitrageCommand) Help() string {
+	text := `
+Usage: ./nefertiti arbitrage [options]
+
+The arbitrage command looks for markets that trade at a different price on
+different exchanges. It reports the spreads that exceed the fees plus a
+threshold, and (optionally) trades them.
+
+Options:
+  --exchanges = two or more names, for example: Binance,GDAX,Kucoin
+  --quote     = currency that is used as the reference, for example: BTC or USDT
+  --fee       = trading fee (in %) that you pay per order. overrides the
+                taker fee that the exchange tells us.
+                (optional, defaults to the exchange's fee, or 0.1)
+  --threshold = minimum spread (in %) after fees.
+                (optional, defaults to 0.5)
+  --repeat    = if included, repeats the scan every X minutes.
+                (optional, defaults to false)
+  --trade     = if included, buys on the cheapest exchange and sells on the
+                most expensive exchange. you will need to hold the base asset
+                on every exchange. both orders are checked against your
+                balances before either is placed. if the sell fails, the
+                buy is cancelled and whatever got filled is sold back.
+                (optional, defaults to false)
+  --size      = amount (in quote currency) that you will want to trade.
+                required if --trade is included.
+  --api-key   = exchange=key pairs, for example: Binance=X,KuCoin=Y
+                (optional, you will be prompted for the missing keys)
+  --api-secret, --api-passphrase = same as --api-key
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *ArbitrageCommand) Synopsis() string {
+	return "Find (and trade) price differences between exchanges."
+}
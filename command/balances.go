@@ -0,0 +1,103 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	BalancesCommand struct {
+		*CommandMeta
+	}
+)
+
+func (c *BalancesCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	var err error
+
+	var all exchanges.Exchanges
+	if all, err = exchanges.GetExchanges(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var total model.Balances
+
+	tbl := table.NewWriter()
+	tbl.AppendHeader(table.Row{"Exchange", "Asset", "Total", "Available", "Locked"})
+	for _, exchange := range all {
+		var client model.Client
+		if client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
+			return c.ReturnError(err)
+		}
+		var balances model.Balances
+		if balances, err = exchange.GetBalances(ctx, client); err != nil {
+			if len(all) == 1 {
+				return c.ReturnError(err)
+			}
+			log.Printf("[WARN] %v. Exchange: %s\n", err, exchange.GetInfo().Name)
+			continue
+		}
+		balances.Sort()
+		for _, balance := range balances {
+			tbl.AppendRow(table.Row{
+				exchange.GetInfo().Name,
+				strings.ToUpper(balance.Asset),
+				fmt.Sprintf("%g", balance.Total),
+				fmt.Sprintf("%g", balance.Available),
+				fmt.Sprintf("%g", balance.Locked),
+			})
+			total.Add(balance)
+		}
+	}
+	fmt.Println(tbl.Render())
+
+	// aggregate the balances across exchanges
+	if len(all) > 1 {
+		total.Sort()
+		tbl = table.NewWriter()
+		tbl.SetTitle("Total")
+		tbl.AppendHeader(table.Row{"Asset", "Total", "Available", "Locked"})
+		for _, balance := range total {
+			tbl.AppendRow(table.Row{
+				strings.ToUpper(balance.Asset),
+				fmt.Sprintf("%g", balance.Total),
+				fmt.Sprintf("%g", balance.Available),
+				fmt.Sprintf("%g", balance.Locked),
+			})
+		}
+		fmt.Println(tbl.Render())
+	}
+
+	return 0
+}
+
+func (c *BalancesCommand) Help() string {
+	text := `
+Usage: ./nefertiti balances [options]
+
+The balances command reports what you hold per asset: the total, what is
+available, and what is locked in your open orders. When you specify more
+than one exchange, the balances are also aggregated across exchanges.
+
+Please note that Crypto.com does not support this command (yet).
+
+Options:
+  --exchange   = one or more names, for example: Binance,GDAX,Kucoin
+  --api-key    = exchange=key pairs, for example: Binance=X,KuCoin=Y
+                 (optional, you will be prompted for the missing keys)
+  --api-secret, --api-passphrase = same as --api-key
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *BalancesCommand) Synopsis() string {
+	return "Get your balances on one or more exchanges."
+}
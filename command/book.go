@@ -1,8 +1,10 @@
 package command
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/svanas/nefertiti/exchanges"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -18,7 +21,68 @@ type (
 	}
 )
 
+// the top of the order book of a market on an exchange
+type bookTop struct {
+	Exchange string  `json:"exchange"`
+	Market   string  `json:"market"`
+	Bid      float64 `json:"bid"`
+	BidSize  float64 `json:"bid_size"`
+	Ask      float64 `json:"ask"`
+	AskSize  float64 `json:"ask_size"`
+}
+
+// returns the price in between the highest bid and the lowest ask
+func (top *bookTop) Mid() float64 {
+	if top.Bid > 0 && top.Ask > 0 {
+		return (top.Bid + top.Ask) / 2
+	}
+	return 0
+}
+
+// returns the highest bid and the lowest ask
+func getBookTop(ctx context.Context, exchange model.Exchange, client model.Client, market string) (*bookTop, error) {
+	prec, err := exchange.GetPricePrec(ctx, client, market)
+	if err != nil {
+		return nil, err
+	}
+	// aggregate to the smallest tick size, so we don't lose any price levels
+	agg := math.Pow(10, -float64(prec))
+
+	out := &bookTop{
+		Exchange: exchange.GetInfo().Name,
+		Market:   market,
+	}
+
+	for _, side := range []model.BookSide{model.BOOK_SIDE_BIDS, model.BOOK_SIDE_ASKS} {
+		var book1 interface{}
+		if book1, err = exchange.GetBook(ctx, client, market, side); err != nil {
+			return nil, err
+		}
+		var book2 model.Book
+		if book2, err = exchange.Aggregate(ctx, client, book1, market, agg); err != nil {
+			return nil, err
+		}
+		for _, e := range book2 {
+			if side == model.BOOK_SIDE_BIDS {
+				if e.Price > out.Bid {
+					out.Bid = e.Price
+					out.BidSize = e.Size
+				}
+			} else {
+				if out.Ask == 0 || e.Price < out.Ask {
+					out.Ask = e.Price
+					out.AskSize = e.Size
+				}
+			}
+		}
+	}
+
+	return out, nil
+}
+
 func (c *BookCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	var (
 		err error
 		flg *flag.Flag
@@ -30,7 +94,7 @@ func (c *BookCommand) Run(args []string) int {
 	}
 
 	var market string
-	if market, err = model.GetMarket(exchange); err != nil {
+	if market, err = model.GetMarket(ctx, exchange); err != nil {
 		return c.ReturnError(err)
 	}
 
@@ -52,18 +116,18 @@ func (c *BookCommand) Run(args []string) int {
 		}
 	}
 
-	var client interface{}
+	var client model.Client
 	if client, err = exchange.GetClient(model.BOOK, flag.Sandbox()); err != nil {
 		return c.ReturnError(err)
 	}
 
 	var book1 interface{}
-	if book1, err = exchange.GetBook(client, market, side); err != nil {
+	if book1, err = exchange.GetBook(ctx, client, market, side); err != nil {
 		return c.ReturnError(err)
 	}
 
 	var book2 model.Book
-	if book2, err = exchange.Aggregate(client, book1, market, agg); err != nil {
+	if book2, err = exchange.Aggregate(ctx, client, book1, market, agg); err != nil {
 		return c.ReturnError(err)
 	}
 
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/svanas/nefertiti/aggregation"
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/exchanges"
 	"github.com/svanas/nefertiti/flag"
@@ -19,7 +21,9 @@ import (
 	"github.com/svanas/nefertiti/notify"
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
+	"github.com/svanas/nefertiti/shutdown"
 	"github.com/svanas/nefertiti/signals"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -28,6 +32,12 @@ type (
 	}
 )
 
+// channelError is an error that our signal channel returned, as opposed to the exchange. It tells us nothing about the
+// exchange, so it doesn't go to the breaker of that exchange.
+type channelError struct {
+	error
+}
+
 func report(err error,
 	market string,
 	channel model.Channel,
@@ -53,7 +63,8 @@ func report(err error,
 		log.Printf("[ERROR] %s", msg)
 	}
 
-	if service != nil {
+	// do not notify the user about every error while the exchange is down
+	if service != nil && !breaker.IsOpen(exchange.GetInfo().Name) {
 		err := service.SendMessage(msg, (exchange.GetInfo().Name + " - ERROR"), model.ONCE_PER_MINUTE)
 		if err != nil {
 			log.Printf("[ERROR] %v", err)
@@ -62,8 +73,9 @@ func report(err error,
 }
 
 func buyEvery(
+	ctx context.Context,
 	d time.Duration,
-	client interface{},
+	client model.Client,
 	exchange model.Exchange,
 	markets []string,
 	hold model.Markets,
@@ -84,16 +96,26 @@ func buyEvery(
 	sandbox bool,
 	debug bool,
 ) {
-	for range time.Tick(d) {
-		market, err := buy(client, exchange, markets, hold, agg, size, dip, pip, mult, dist, top, max, min, price, btcVolumeMin, deviation, service, strict, sandbox, false, debug)
-		if err != nil {
+	cb := breaker.Get(exchange.GetInfo().Name)
+	for shutdown.Sleep(d) && cb.Wait() {
+		// every run must be done before the next one is due, so a hung exchange cannot stall the loop
+		run, cancel := context.WithTimeout(ctx, d)
+		market, err := buy(run, client, exchange, markets, hold, agg, size, dip, pip, mult, dist, top, max, min, price, btcVolumeMin, deviation, service, strict, sandbox, false, debug)
+		cancel()
+		if err != nil && !transport.Done() {
 			report(err, market, nil, service, exchange)
 		}
+		level, lerr := notify.Level()
+		if lerr != nil {
+			level = notify.LEVEL_DEFAULT
+		}
+		cb.Done(err, service, level)
 	}
 }
 
 func buy(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	exchange model.Exchange,
 	markets []string,
 	hold model.Markets,
@@ -125,7 +147,7 @@ func buy(
 		enumerable []string       // the markets we enumerate/buy
 	)
 
-	if available, err = exchange.GetMarkets(!wildcard, sandbox, flag.Get("ignore").Split()); err != nil {
+	if available, err = exchange.GetMarkets(ctx, !wildcard, sandbox, flag.Get("ignore").Split()); err != nil {
 		return "", err
 	}
 
@@ -145,7 +167,7 @@ func buy(
 
 	for _, market := range enumerable {
 		// "algo" orders are stop-loss, take-profit, and OCO (aka one-cancels-the-other) orders
-		if hasAlgoOrder, _ := exchange.HasAlgoOrder(client, market); hasAlgoOrder {
+		if hasAlgoOrder, _ := exchange.HasAlgoOrder(ctx, client, market); hasAlgoOrder {
 			log.Printf("[INFO] Ignoring %s because you have at least one \"algo\" order open on this market.\n", market)
 			continue
 		}
@@ -157,11 +179,11 @@ func buy(
 			pricePrec int          // price precision
 		)
 
-		if ticker, err = exchange.GetTicker(client, market); err != nil {
+		if ticker, err = exchange.GetTicker(ctx, client, market); err != nil {
 			return market, err
 		}
 
-		if stats, err = exchange.Get24h(client, market); err != nil {
+		if stats, err = exchange.Get24h(ctx, client, market); err != nil {
 			return market, err
 		}
 
@@ -170,11 +192,11 @@ func buy(
 			continue
 		}
 
-		if avg, err = stats.Avg(exchange, sandbox); err != nil {
+		if avg, err = stats.Avg(ctx, exchange, sandbox); err != nil {
 			return market, err
 		}
 
-		if pricePrec, err = exchange.GetPricePrec(client, market); err != nil {
+		if pricePrec, err = exchange.GetPricePrec(ctx, client, market); err != nil {
 			return market, err
 		}
 
@@ -195,7 +217,7 @@ func buy(
 		// ignore supports where the price is higher than BUY order(s) that were (a) filled and (b) not been sold (yet)
 		if !test {
 			var opened model.Orders
-			if opened, err = exchange.GetOpened(client, market); err != nil {
+			if opened, err = exchange.GetOpened(ctx, client, market); err != nil {
 				return market, err
 			}
 			for _, order := range opened {
@@ -205,13 +227,13 @@ func buy(
 			}
 			// step 1: loop through the filled BUY orders
 			var closed model.Orders
-			if closed, err = exchange.GetClosed(client, market); err != nil {
+			if closed, err = exchange.GetClosed(ctx, client, market); err != nil {
 				return market, err
 			}
 			for _, fill := range closed {
 				if fill.Side == model.BUY {
 					// step 2: has this filled BUY order NOT been sold?
-					if opened.IndexByPrice(model.SELL, market, pricing.Multiply(fill.Price, mult, pricePrec)) > -1 {
+					if opened.IndexByPrice(model.SELL, market, pricing.Multiply(fill.Price, model.GetFees(ctx, exchange, client, market).Mult(mult), pricePrec)) > -1 {
 						if mmax == 0 || mmax >= fill.Price {
 							mmax = fill.Price
 						}
@@ -221,7 +243,7 @@ func buy(
 		}
 
 		if magg == 0 {
-			if magg, mdip, mpip, err = aggregation.GetEx(exchange, client, market, ticker, avg, dip, pip, mmax, min, int(dist), pricePrec, int(top), strict); err != nil {
+			if magg, mdip, mpip, err = aggregation.GetEx(ctx, exchange, client, market, ticker, avg, dip, pip, mmax, min, int(dist), pricePrec, int(top), strict); err != nil {
 				if errors.Is(err, aggregation.ECannotFindSupports) && (len(enumerable) > 1 || flag.Get("ignore").Contains("error")) {
 					report(err, market, nil, service, exchange)
 					continue
@@ -236,11 +258,11 @@ func buy(
 			book2 model.Book
 		)
 
-		if book1, err = exchange.GetBook(client, market, model.BOOK_SIDE_BIDS); err != nil {
+		if book1, err = exchange.GetBook(ctx, client, market, model.BOOK_SIDE_BIDS); err != nil {
 			return market, err
 		}
 
-		if book2, err = exchange.Aggregate(client, book1, market, magg); err != nil {
+		if book2, err = exchange.Aggregate(ctx, client, book1, market, magg); err != nil {
 			return market, err
 		}
 
@@ -310,7 +332,7 @@ func buy(
 		}
 
 		var sizePrec int
-		if sizePrec, err = exchange.GetSizePrec(client, market); err != nil {
+		if sizePrec, err = exchange.GetSizePrec(ctx, client, market); err != nil {
 			return market, err
 		}
 
@@ -359,7 +381,7 @@ func buy(
 
 		// cancel your open buy order(s), then place the top X buy orders
 		if !test {
-			err = exchange.Buy(client, true, market, calls, deviation, model.LIMIT)
+			err = exchange.Buy(ctx, client, true, market, calls, deviation, model.LIMIT)
 			if err != nil {
 				if len(enumerable) > 1 || flag.Get("ignore").Contains("error") {
 					report(err, market, nil, service, exchange)
@@ -386,9 +408,10 @@ func buy(
 }
 
 func buySignalsEvery(
+	ctx context.Context,
 	d time.Duration,
 	channel model.Channel,
-	client interface{},
+	client model.Client,
 	exchange model.Exchange,
 	quote model.Assets,
 	price float64,
@@ -398,21 +421,38 @@ func buySignalsEvery(
 	btcVolumeMin,
 	deviation float64,
 	service model.Notify,
+	journal *signals.Journal,
 	sandbox bool,
 	debug bool,
 ) {
 	var err error
-	for range time.Tick(d) {
-		calls, err = buySignals(channel, client, exchange, quote, price, valid, calls, min, btcVolumeMin, deviation, service, sandbox, false, debug)
-		if err != nil {
+	cb := breaker.Get(exchange.GetInfo().Name)
+	for shutdown.Sleep(d) && cb.Wait() {
+		// every run must be done before the next one is due, so a hung exchange or signal provider cannot stall the loop
+		run, cancel := context.WithTimeout(ctx, d)
+		calls, err = buySignals(run, channel, client, exchange, quote, price, valid, calls, min, btcVolumeMin, deviation, service, journal, sandbox, false, debug)
+		cancel()
+		level, lerr := notify.Level()
+		if lerr != nil {
+			level = notify.LEVEL_DEFAULT
+		}
+		if cerr, ok := err.(*channelError); ok {
+			if !transport.Done() {
+				logger.Error(channel.GetName(), cerr.error, level, service)
+			}
+			continue
+		}
+		if err != nil && !transport.Done() {
 			report(err, "", channel, service, exchange)
 		}
+		cb.Done(err, service, level)
 	}
 }
 
 func buySignals(
+	ctx context.Context,
 	channel model.Channel,
-	client interface{},
+	client model.Client,
 	exchange model.Exchange,
 	quote model.Assets,
 	price float64,
@@ -422,6 +462,7 @@ func buySignals(
 	btcVolumeMin,
 	deviation float64,
 	service model.Notify,
+	journal *signals.Journal,
 	sandbox bool,
 	test bool,
 	debug bool,
@@ -435,20 +476,32 @@ func buySignals(
 		return old, errors.New("missing argument: quote")
 	}
 
+	// resolve the outcome of the calls we have acted on before
+	if journal != nil {
+		if err = journal.Update(ctx, exchange, client); err != nil {
+			logger.Warn(err)
+		}
+		defer func() {
+			if err := journal.Save(); err != nil {
+				logger.Warn(err)
+			}
+		}()
+	}
+
 	var all []model.Market
-	if all, err = exchange.GetMarkets(true, sandbox, flag.Get("ignore").Split()); err != nil {
+	if all, err = exchange.GetMarkets(ctx, true, sandbox, flag.Get("ignore").Split()); err != nil {
 		return old, err
 	}
 
 	var markets []string
-	if markets, err = channel.GetMarkets(exchange, quote, btcVolumeMin, valid, sandbox, debug, flag.Get("ignore").Split()); err != nil {
-		return old, err
+	if markets, err = channel.GetMarkets(ctx, exchange, quote, btcVolumeMin, valid, sandbox, debug, flag.Get("ignore").Split()); err != nil {
+		return old, &channelError{err}
 	}
 
 	// --- BEGIN --- svanas 2018-12-06 --- allow for signals to buy new listings ---
 	for _, market := range markets {
 		if !model.HasMarket(all, market) {
-			if all, err = exchange.GetMarkets(false, sandbox, flag.Get("ignore").Split()); err != nil {
+			if all, err = exchange.GetMarkets(ctx, false, sandbox, flag.Get("ignore").Split()); err != nil {
 				return old, err
 			}
 			break
@@ -473,18 +526,18 @@ func buySignals(
 			}
 
 			var ticker float64
-			if ticker, err = exchange.GetTicker(client, market); err != nil {
+			if ticker, err = exchange.GetTicker(ctx, client, market); err != nil {
 				return old, err
 			}
 
 			var prec int
-			if prec, err = exchange.GetSizePrec(client, market); err != nil {
+			if prec, err = exchange.GetSizePrec(ctx, client, market); err != nil {
 				return old, err
 			}
 
 			var calls model.Calls
-			if calls, err = channel.GetCalls(exchange, market, sandbox, debug); err != nil {
-				return old, err
+			if calls, err = channel.GetCalls(ctx, exchange, market, sandbox, debug); err != nil {
+				return old, &channelError{err}
 			}
 
 			for i := range calls {
@@ -493,7 +546,7 @@ func buySignals(
 				if flag.Dca() {
 					hasOpenSell := 0
 					var opened model.Orders
-					if opened, err = exchange.GetOpened(client, market); err != nil {
+					if opened, err = exchange.GetOpened(ctx, client, market); err != nil {
 						return old, err
 					}
 					for _, order := range opened {
@@ -529,7 +582,7 @@ func buySignals(
 			if btcVolumeMin > 0 {
 				for i := range calls {
 					if !calls[i].Skip {
-						stats, err := exchange.Get24h(client, calls[i].Market)
+						stats, err := exchange.Get24h(ctx, client, calls[i].Market)
 						if err != nil {
 							return old, err
 						}
@@ -567,9 +620,26 @@ func buySignals(
 					}
 					// buy the signals (if we got any)
 					if calls.HasAnythingToDo() {
-						err = exchange.Buy(client, false, market, calls, deviation, channel.GetOrderType())
+						var attempted []int
+						for i := range calls {
+							if !calls[i].Skip {
+								attempted = append(attempted, i)
+							}
+						}
+						err = exchange.Buy(ctx, client, false, market, calls, deviation, channel.GetOrderType())
 						if err != nil {
 							report(err, market, channel, service, exchange)
+							for _, i := range attempted {
+								calls[i].Ignore(err.Error())
+							}
+						}
+					}
+					// record the signals we have acted on (or skipped) for the performance report
+					if journal != nil {
+						for i := range calls {
+							if err := journal.Record(ctx, channel, exchange, client, &calls[i], valid); err != nil {
+								logger.Warn(err)
+							}
 						}
 					}
 				}
@@ -592,7 +662,7 @@ func buySignals(
 				if data, err = json.Marshal(entry); err == nil {
 					log.Println("[CANCELLED] " + string(data))
 				}
-				if err = exchange.Cancel(client, entry.Market, model.BUY); err != nil {
+				if err = exchange.Cancel(ctx, client, entry.Market, model.BUY); err != nil {
 					return new, err
 				}
 			}
@@ -614,6 +684,8 @@ func buySignals(
 }
 
 func (c *BuyCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	var (
 		err error
 		flg *flag.Flag
@@ -623,10 +695,13 @@ func (c *BuyCommand) Run(args []string) int {
 	if exchange, err = exchanges.GetExchange(); err != nil {
 		return c.ReturnError(err)
 	}
+	if err = checkCancelOnExit(exchange); err != nil {
+		return c.ReturnError(err)
+	}
 
 	test := flag.Exists("test")
 
-	var client interface{}
+	var client model.Client
 	if client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
 		return c.ReturnError(err)
 	}
@@ -674,6 +749,14 @@ func (c *BuyCommand) Run(args []string) int {
 		if channel == nil {
 			return c.ReturnError(errors.Errorf("signals %v does not exist", flg))
 		}
+		if channel.GetOrderType() == model.MARKET && !exchange.Capabilities().MarketOrders {
+			return c.ReturnError(errors.Errorf("%s does not support market orders. Channel: %s", exchange.GetInfo().Name, channel.GetName()))
+		}
+		if check, ok := channel.(model.ChannelCheck); ok {
+			if err = check.Check(exchange); err != nil {
+				return c.ReturnError(err)
+			}
+		}
 		// --price=x
 		flg = flag.Get("price")
 		if !flg.Exists {
@@ -696,9 +779,19 @@ func (c *BuyCommand) Run(args []string) int {
 			}
 			duration2 = time.Duration(valid * float64(time.Hour))
 		}
+		// keep track of the calls we act on (or skip), and their eventual outcome
+		var journal *signals.Journal
+		if !test {
+			if journal, err = signals.LoadJournal(); err != nil {
+				return c.ReturnError(err)
+			}
+		}
 		// initial run starts here
 		var calls model.Calls
-		if calls, err = buySignals(channel, client, exchange, flag.Get("quote").Split(), price, duration2, nil, min, btcVolumeMin, deviation, service, flag.Sandbox(), test, flag.Debug()); err != nil {
+		if calls, err = buySignals(ctx, channel, client, exchange, flag.Get("quote").Split(), price, duration2, nil, min, btcVolumeMin, deviation, service, journal, flag.Sandbox(), test, flag.Debug()); err != nil {
+			if cerr, ok := err.(*channelError); ok {
+				err = cerr.error
+			}
 			if flag.Get("ignore").Contains("error") {
 				log.Printf("[ERROR] %v\n", err)
 			} else {
@@ -733,7 +826,17 @@ func (c *BuyCommand) Run(args []string) int {
 					if err = c.ReturnSuccess(); err != nil {
 						return c.ReturnError(err)
 					}
-					buySignalsEvery(duration1, channel, client, exchange, flag.Get("quote").Split(), price, duration2, calls, min, btcVolumeMin, deviation, service, flag.Sandbox(), flag.Debug())
+					onStop(fmt.Sprintf("listening to %s", channel.GetName()), exchange, service, func() error {
+						return cancelOnExit(ctx, exchange, client, nil)
+					})
+					if journal != nil {
+						shutdown.OnStop(func() {
+							if err := journal.Save(); err != nil {
+								logger.Warn(err)
+							}
+						})
+					}
+					buySignalsEvery(ctx, duration1, channel, client, exchange, flag.Get("quote").Split(), price, duration2, calls, min, btcVolumeMin, deviation, service, journal, flag.Sandbox(), flag.Debug())
 				}
 			}
 		}
@@ -741,7 +844,7 @@ func (c *BuyCommand) Run(args []string) int {
 	}
 
 	var all []model.Market
-	if all, err = exchange.GetMarkets(true, flag.Sandbox(), flag.Get("ignore").Split()); err != nil {
+	if all, err = exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split()); err != nil {
 		return c.ReturnError(err)
 	}
 
@@ -830,7 +933,7 @@ func (c *BuyCommand) Run(args []string) int {
 		return c.ReturnError(err)
 	}
 
-	if _, err = buy(client, exchange, splitted, hold, agg, size, dip, pip, mult, dist, top, max, min, price, btcVolumeMin, deviation, service, flag.Strict(), flag.Sandbox(), test, flag.Debug()); err != nil {
+	if _, err = buy(ctx, client, exchange, splitted, hold, agg, size, dip, pip, mult, dist, top, max, min, price, btcVolumeMin, deviation, service, flag.Strict(), flag.Sandbox(), test, flag.Debug()); err != nil {
 		if flag.Get("ignore").Contains("error") {
 			log.Printf("[ERROR] %v\n", err)
 		} else {
@@ -848,7 +951,10 @@ func (c *BuyCommand) Run(args []string) int {
 			if err = c.ReturnSuccess(); err != nil {
 				return c.ReturnError(err)
 			}
-			buyEvery(time.Duration(repeat*float64(time.Hour)), client, exchange, splitted, hold, agg, size, dip, pip, mult, dist, top, max, min, price, btcVolumeMin, deviation, service, flag.Strict(), flag.Sandbox(), flag.Debug())
+			onStop(fmt.Sprintf("buying %s", strings.Join(splitted, ",")), exchange, service, func() error {
+				return cancelOnExit(ctx, exchange, client, splitted)
+			})
+			buyEvery(ctx, time.Duration(repeat*float64(time.Hour)), client, exchange, splitted, hold, agg, size, dip, pip, mult, dist, top, max, min, price, btcVolumeMin, deviation, service, flag.Strict(), flag.Sandbox(), flag.Debug())
 		}
 	}
 
@@ -891,6 +997,24 @@ Options:
                (optional, defaults to false)
   --repeat   = if included, repeats this command every X hours.
                (optional, defaults to false)
+  --cancel-on-exit = if included, cancels the buy orders that were opened by
+               the bot when you stop the bot. (optional, defaults to false,
+               see the --bot-only column of the exchanges command)
+  --breaker  = number of consecutive network errors before the bot pauses
+               until the exchange recovers. (optional, defaults to 5, 0 = off)
+
+Network:
+  --rest-url = URL of the exchange REST API, for example: http://localhost:8080
+               (optional, defaults to the exchange)
+  --proxy    = http://, https:// or socks5:// proxy, for example:
+               socks5://127.0.0.1:1080 (optional, defaults to HTTPS_PROXY)
+
+  Each of these is either one URL, or a comma-separated list of exchange=URL
+  pairs, for example: --proxy=Binance=socks5://127.0.0.1:1080,Kucoin=...
+
+  --timeout  = deadline (in seconds) of every request, after which the request
+               is cancelled and (if it is safe) tried again (optional,
+               defaults to 30)
 
 Alternative Strategy:
   The trading bot can listen to signals (for example: Telegram bots) as an
@@ -915,6 +1039,34 @@ Alternative Strategy Options:
                (optional, defaults to 1 hour)
   --repeat   = if included, repeats this command every X hours.
                (optional, defaults to false)
+  --cancel-on-exit = if included, cancels the buy orders that were opened by
+               the bot when you stop the bot. (optional, defaults to false,
+               see the --bot-only column of the exchanges command)
+
+Bases Options:
+  --signals  = bases
+  --algo     = [original|day_trade|conservative|position] the base-cracking
+               algorithm. (optional, defaults to day_trade)
+  --dip      = percentage below the base that will kick the bot into action.
+               optional, defaults to the median drop of the market.
+  --success  = minimum percentage of the cracked bases that have recovered.
+               optional, defaults to 60 if --dip is included.
+
+Listings Options:
+  --signals  = listings
+  --age      = maximum time (in hours) since a market got listed for the bot
+               to still buy it. this applies to the markets that got listed
+               while the bot was down. (optional, defaults to 1 hour)
+
+Consensus Options:
+  --signals  = consensus
+  --channels = two or more providers, for example: listings,volume
+  --agree    = number of providers that must call the same market before the
+               bot buys it. optional, defaults to all of them.
+  --window   = time (in hours) in where the providers must agree.
+               (optional, defaults to 1 hour)
+  --merge    = [min|median] how to merge the prices of the providers.
+               (optional, defaults to min)
 `
 	return strings.TrimSpace(text)
 }
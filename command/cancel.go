@@ -1,12 +1,16 @@
 package command
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/exchanges"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -15,49 +19,163 @@ type (
 	}
 )
 
+// cancelFilter returns the filter in the --side, --min-price, --max-price, --older-than, --bot-only and --prefix args
+func cancelFilter() (*model.CancelFilter, error) {
+	var (
+		err error
+		out model.CancelFilter
+	)
+
+	if arg := flag.Get("side"); arg.Exists {
+		if out.Side = model.NewOrderSide(arg.String()); out.Side == model.ORDER_SIDE_NONE {
+			return nil, errors.Errorf("side %v is invalid", arg)
+		}
+	}
+
+	if arg := flag.Get("min-price"); arg.Exists {
+		if out.MinPrice, err = arg.Float64(); err != nil || out.MinPrice <= 0 {
+			return nil, errors.Errorf("min-price %v is invalid", arg)
+		}
+	}
+
+	if arg := flag.Get("max-price"); arg.Exists {
+		if out.MaxPrice, err = arg.Float64(); err != nil || out.MaxPrice <= 0 {
+			return nil, errors.Errorf("max-price %v is invalid", arg)
+		}
+	}
+
+	if out.MinPrice > 0 && out.MaxPrice > 0 && out.MinPrice > out.MaxPrice {
+		return nil, errors.Errorf("min-price %v is greater than max-price %v", flag.Get("min-price"), flag.Get("max-price"))
+	}
+
+	if arg := flag.Get("older-than"); arg.Exists {
+		if out.OlderThan, err = time.ParseDuration(arg.String()); err != nil || out.OlderThan <= 0 {
+			return nil, errors.Errorf("older-than %v is invalid", arg)
+		}
+	}
+
+	out.BotOnly = flag.Exists("bot-only")
+	out.Prefix = flag.Get("prefix").String()
+
+	if out == (model.CancelFilter{}) {
+		return nil, errors.New("missing argument: side")
+	}
+
+	return &out, nil
+}
+
 func (c *CancelCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	exchange, err := exchanges.GetExchange()
 	if err != nil {
 		return c.ReturnError(err)
 	}
 
-	market, err := model.GetMarket(exchange)
+	market, err := model.GetMarket(ctx, exchange)
 	if err != nil {
 		return c.ReturnError(err)
 	}
 
-	arg := flag.Get("side")
-	if !arg.Exists {
-		return c.ReturnError(errors.New("missing argument: side"))
-	}
-	side := model.NewOrderSide(arg.String())
-	if side == model.ORDER_SIDE_NONE {
-		return c.ReturnError(errors.Errorf("side %v is invalid", arg))
-	}
+	dryRun := flag.Exists("dry-run")
 
 	client, err := exchange.GetClient(model.PRIVATE, flag.Sandbox())
 	if err != nil {
 		return c.ReturnError(err)
 	}
 
-	if market != "all" {
-		if err = exchange.Cancel(client, market, side); err != nil {
-			return c.ReturnError(err)
+	tbl := table.NewWriter()
+	tbl.AppendHeader(table.Row{"ID", "Market", "Side", "Size", "Price", "Created", "Client Order ID"})
+	appendRow := func(order *model.Order) {
+		tbl.AppendRow(table.Row{
+			order.ID,
+			order.Market,
+			model.FormatOrderSide(order.Side),
+			order.Size,
+			order.Price,
+			order.CreatedAt.Format(time.RFC3339),
+			order.ClientOrderID,
+		})
+	}
+
+	// --id=X,Y,Z
+	if arg := flag.Get("id"); arg.Exists {
+		if market == "all" {
+			return c.ReturnError(errors.New("cannot cancel an order by ID on all markets"))
+		}
+		// validate every ID before we cancel any of them
+		ids := arg.Split()
+		var orders []*model.Order
+		for _, id := range ids {
+			var order *model.Order
+			if order, err = exchange.GetOrder(ctx, client, market, id); err != nil {
+				return c.ReturnError(err)
+			}
+			if order.Status.Closed() {
+				return c.ReturnError(errors.Errorf("order %s is %s", id, order.Status.String()))
+			}
+			orders = append(orders, order)
 		}
+		for i, order := range orders {
+			if !dryRun {
+				if err = exchange.CancelOrder(ctx, client, market, ids[i]); err != nil {
+					return c.ReturnError(err)
+				}
+			}
+			appendRow(order)
+		}
+		fmt.Println(tbl.Render())
 		return 0
 	}
 
-	markets, err := exchange.GetMarkets(true, flag.Sandbox(), flag.Get("ignore").Split())
+	filter, err := cancelFilter()
 	if err != nil {
 		return c.ReturnError(err)
 	}
+	if filter.BotOnly && !exchange.Capabilities().BotOrders {
+		return c.ReturnError(errors.Errorf("%s cannot tell which orders were opened by our bots", exchange.GetInfo().Name))
+	}
+
+	var markets []string
+	if market != "all" {
+		markets = append(markets, market)
+	} else {
+		all, err := exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split())
+		if err != nil {
+			return c.ReturnError(err)
+		}
+		for _, market := range all {
+			markets = append(markets, market.Name)
+		}
+	}
+
+	// cancel all buy (or sell) orders, the way we always did
+	if !dryRun && *filter == (model.CancelFilter{Side: filter.Side}) {
+		for _, market := range markets {
+			if err = exchange.Cancel(ctx, client, market, filter.Side); err != nil {
+				return c.ReturnError(err)
+			}
+		}
+		return 0
+	}
 
 	for _, market := range markets {
-		if err = exchange.Cancel(client, market.Name, side); err != nil {
+		var opened model.Orders
+		if opened, err = exchange.GetOpened(ctx, client, market); err != nil {
 			return c.ReturnError(err)
 		}
+		for _, order := range opened.Filter(filter, time.Now()) {
+			if !dryRun {
+				if err = exchange.CancelOrder(ctx, client, market, order.ID); err != nil {
+					return c.ReturnError(err)
+				}
+			}
+			appendRow(&order)
+		}
 	}
 
+	fmt.Println(tbl.Render())
+
 	return 0
 }
 
@@ -65,16 +183,29 @@ func (c *CancelCommand) Help() string {
 	text := `
 Usage: ./nefertiti cancel [options]
 
-The cancel command cancels all your buy or sell orders on a given market.
+The cancel command cancels your orders on a given market. You can cancel one
+or more orders by ID, or every order that matches your filter.
 
 Options:
-  --exchange = name
-  --market   = a valid market pair
-  --side     = [buy|sell]
+  --exchange   = name
+  --market     = a valid market pair, or "all"
+  --id         = one or more order IDs (optional)
+  --side       = [buy|sell] (optional)
+  --min-price  = only the orders priced at or above this price (optional)
+  --max-price  = only the orders priced at or below this price (optional)
+  --older-than = only the orders older than this, for example: 24h (optional)
+  --bot-only   = if included, only the orders that were opened by our bots
+                 (optional, see the --bot-only column of the exchanges command)
+  --prefix     = only the orders with a client order ID that starts with this
+                 prefix (optional)
+  --dry-run    = if included, lists the orders that would be cancelled, but
+                 does not cancel them (optional)
+
+Unless you include --id, you need to include at least one filter.
 `
 	return strings.TrimSpace(text)
 }
 
 func (c *CancelCommand) Synopsis() string {
-	return "Cancels all your buy or sell orders on a given market."
+	return "Cancels your orders by ID, or every order that matches a filter."
 }
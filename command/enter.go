@@ -0,0 +1,225 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	EnterCommand struct {
+		*CommandMeta
+	}
+)
+
+func enter(
+	ctx context.Context,
+	exchange model.Exchange,
+	market string,
+	startAtPrice float64,
+	stopAtPrice float64,
+	startWithSize float64,
+	stopWithSize float64,
+	test bool,
+) error {
+	steps, err := ladderSteps(startAtPrice, stopAtPrice, startWithSize, stopWithSize)
+	if err != nil {
+		return err
+	}
+
+	client, err := exchange.GetClient(func() model.Permission {
+		if test {
+			return model.PUBLIC
+		} else {
+			return model.PRIVATE
+		}
+	}(), false)
+	if err != nil {
+		return err
+	}
+
+	orders, err := func() (model.Orders, error) {
+		if test {
+			return nil, nil
+		} else {
+			return exchange.GetOpened(ctx, client, market)
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	hasLimitBuy := func(size float64, price float64) bool {
+		for _, order := range orders {
+			if order.Side == model.BUY && order.Size == size && order.Price == price {
+				return true
+			}
+		}
+		return false
+	}
+
+	markets, err := exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split())
+	if err != nil {
+		return err
+	}
+
+	quoteCurr, err := model.GetQuoteCurr(markets, market)
+	if err != nil {
+		return err
+	}
+
+	sizePrec, err := exchange.GetSizePrec(ctx, client, market)
+	if err != nil {
+		return err
+	}
+
+	pricePrec, err := exchange.GetPricePrec(ctx, client, market)
+	if err != nil {
+		return err
+	}
+
+	curve, err := ladderCurve()
+	if err != nil {
+		return err
+	}
+
+	// the rungs are ordered from the ticker outwards; we start with the rung furthest away from the ticker
+	rungs := model.Ladder(startAtPrice, stopAtPrice, startWithSize, stopWithSize, steps, curve, pricePrec, sizePrec)
+
+	var (
+		totalSize float64
+		totalCost float64
+	)
+
+	tbl := table.NewWriter()
+	tbl.AppendHeader(table.Row{"", "Price", "Size", "Cost"})
+
+	for i := len(rungs) - 1; i >= 0; i-- {
+		currSize := rungs[i].Size
+		currPrice := rungs[i].Price
+
+		if !test {
+			ticker, err := exchange.GetTicker(ctx, client, market)
+			if err != nil {
+				return err
+			}
+			if currPrice >= ticker {
+				break
+			}
+		}
+
+		totalSize += currSize
+		totalCost += currPrice * currSize
+
+		tbl.AppendRow(table.Row{"",
+			fmt.Sprintf("%.[2]*[1]f %[3]v", currPrice, pricePrec, quoteCurr),
+			fmt.Sprintf("%.[2]*[1]f", currSize, sizePrec),
+			fmt.Sprintf("%.[2]*[1]f %[3]v", (currPrice * currSize), pricePrec, quoteCurr),
+		})
+
+		if !test {
+			if !hasLimitBuy(currSize, currPrice) {
+				if _, _, err := exchange.Order(ctx, client, model.BUY, market, currSize, currPrice, model.LIMIT, nil, ""); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	tbl.AppendSeparator()
+	tbl.AppendRow(table.Row{"TOTAL", "",
+		fmt.Sprintf("%.[2]*[1]f", totalSize, sizePrec),
+		fmt.Sprintf("%.[2]*[1]f %[3]v", totalCost, pricePrec, quoteCurr),
+	})
+
+	fmt.Println(tbl.Render())
+
+	return nil
+}
+
+func (c *EnterCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	exchange, err := exchanges.GetExchange()
+	if err != nil {
+		return c.ReturnError(err)
+	}
+
+	market, err := model.GetMarket(ctx, exchange)
+	if err != nil {
+		return c.ReturnError(err)
+	}
+
+	startAtPrice, err := startAtPrice()
+	if err != nil {
+		return c.ReturnError(err)
+	}
+
+	stopAtPrice, err := stopAtPrice()
+	if err != nil {
+		return c.ReturnError(err)
+	}
+
+	// we start near the ticker (the highest price) and stop at the lowest price
+	if startAtPrice < stopAtPrice {
+		stopAtPrice, startAtPrice = startAtPrice, stopAtPrice
+	}
+
+	startWithSize, err := startWithSize()
+	if err != nil {
+		return c.ReturnError(err)
+	}
+
+	stopWithSize, err := stopWithSize()
+	if err != nil {
+		return c.ReturnError(err)
+	}
+
+	if startWithSize > stopWithSize {
+		stopWithSize, startWithSize = startWithSize, stopWithSize
+	}
+
+	if err = enter(ctx, exchange, market, startAtPrice, stopAtPrice, startWithSize, stopWithSize, !flag.Exists("not-a-drill")); err != nil {
+		return c.ReturnError(err)
+	}
+
+	return 0
+}
+
+func (c *EnterCommand) Help() string {
+	text := `
+Usage: ./nefertiti enter [options]
+
+The enter command opens a ladder of limit buy orders, from --start-at-price
+down to --stop-at-price. The size of the orders ramps from --start-with-size
+up to --stop-with-size, so the orders furthest away from the ticker are the
+biggest. Orders at (or above) the ticker price are skipped.
+
+By default, the command is a drill: it reports the orders it would open.
+Include --not-a-drill to actually open the orders.
+
+Options:
+  --exchange        = name, for example: Binance
+  --market          = a valid market pair
+  --start-at-price  = price of the highest buy order
+  --stop-at-price   = price of the lowest buy order
+  --start-with-size = size of the highest buy order
+  --stop-with-size  = size of the lowest buy order
+  --steps           = number of steps between the highest and the lowest
+                      buy order (optional)
+  --curve           = [linear|exponential|fibonacci] distance between the
+                      steps (optional, defaults to linear)
+  --not-a-drill     = if included, opens the orders (optional)
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *EnterCommand) Synopsis() string {
+	return "Open a ladder of buy orders."
+}
@@ -3,8 +3,13 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
 )
 
 type (
@@ -13,13 +18,62 @@ type (
 	}
 )
 
+func exchangesYesNo(value bool) string {
+	if value {
+		return "Y"
+	}
+	return "N"
+}
+
 func (c *ExchangesCommand) Run(args []string) int {
 	var err error
 
 	exchanges := exchanges.New()
 
+	// --format=[json|table]
+	format := "json"
+	flg := flag.Get("format")
+	if flg.Exists {
+		format = strings.ToLower(flg.String())
+		if format != "json" && format != "table" {
+			return c.ReturnError(errors.Errorf("format %v is invalid", flg))
+		}
+	}
+
+	if format == "table" {
+		tbl := table.NewWriter()
+		tbl.AppendHeader(table.Row{"Exchange", "Sandbox", "WebSocket", "Market", "Market By Quote", "Post-Only", "Stop-Loss", "--stoploss=Y", "OCO", "Trailing", "--bot-only"})
+		for _, exchange := range *exchanges {
+			caps := exchange.Capabilities()
+			tbl.AppendRow(table.Row{
+				exchange.GetInfo().Name,
+				exchangesYesNo(caps.Sandbox),
+				exchangesYesNo(caps.WebSocket),
+				exchangesYesNo(caps.MarketOrders),
+				exchangesYesNo(caps.MarketBuyByQuote),
+				exchangesYesNo(caps.PostOnly),
+				exchangesYesNo(caps.StopLoss),
+				exchangesYesNo(caps.StopLossStrategy),
+				exchangesYesNo(caps.OCO),
+				exchangesYesNo(caps.Trailing),
+				exchangesYesNo(caps.BotOrders),
+			})
+		}
+		fmt.Println(tbl.Render())
+		return 0
+	}
+
+	type info struct {
+		*model.ExchangeInfo
+		Capabilities *model.Capabilities `json:"capabilities"`
+	}
+	var infos []info
+	for _, exchange := range *exchanges {
+		infos = append(infos, info{exchange.GetInfo(), exchange.Capabilities()})
+	}
+
 	var out []byte
-	if out, err = json.Marshal(exchanges); err != nil {
+	if out, err = json.Marshal(infos); err != nil {
 		return c.ReturnError(err)
 	}
 
@@ -29,9 +83,17 @@ func (c *ExchangesCommand) Run(args []string) int {
 }
 
 func (c *ExchangesCommand) Help() string {
-	return "Usage: ./nefertiti exchanges"
+	text := `
+Usage: ./nefertiti exchanges [options]
+
+The exchanges command lists the supported exchanges, and what they support.
+
+Options:
+  --format = [json|table] (optional, defaults to json)
+`
+	return strings.TrimSpace(text)
 }
 
 func (c *ExchangesCommand) Synopsis() string {
-	return "Get a list of supported exchanges."
+	return "Get a list of supported exchanges, and what they support."
 }
@@ -1,15 +1,16 @@
 package command
 
 import (
+	"context"
 	"fmt"
-	"math"
+	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/exchanges"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
-	"github.com/svanas/nefertiti/precision"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -19,6 +20,7 @@ type (
 )
 
 func exit(
+	ctx context.Context,
 	exchange model.Exchange,
 	market string,
 	startAtPrice float64,
@@ -27,16 +29,10 @@ func exit(
 	stopWithSize float64,
 	test bool,
 ) error {
-	steps, err := steps()
+	steps, err := ladderSteps(startAtPrice, stopAtPrice, startWithSize, stopWithSize)
 	if err != nil {
 		return err
 	}
-	if steps < 1 {
-		steps = int64(math.Round((stopAtPrice - startAtPrice) / ((stopAtPrice * stopWithSize) - (startAtPrice * startWithSize))))
-		if steps < 1 {
-			return errors.New("Cannot open any orders. Please widen your arguments")
-		}
-	}
 
 	client, err := exchange.GetClient(func() model.Permission {
 		if test {
@@ -53,7 +49,7 @@ func exit(
 		if test {
 			return nil, nil
 		} else {
-			return exchange.GetOpened(client, market)
+			return exchange.GetOpened(ctx, client, market)
 		}
 	}()
 	if err != nil {
@@ -69,7 +65,7 @@ func exit(
 		return false
 	}
 
-	markets, err := exchange.GetMarkets(true, flag.Sandbox(), flag.Get("ignore").Split())
+	markets, err := exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split())
 	if err != nil {
 		return err
 	}
@@ -84,21 +80,23 @@ func exit(
 		return err
 	}
 
-	sizePrec, err := exchange.GetSizePrec(client, market)
+	sizePrec, err := exchange.GetSizePrec(ctx, client, market)
 	if err != nil {
 		return err
 	}
 
-	pricePrec, err := exchange.GetPricePrec(client, market)
+	pricePrec, err := exchange.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return err
 	}
 
-	sizeDeltaPerStep := precision.Round(((stopWithSize - startWithSize) / float64(steps)), sizePrec)
-	priceDeltaPerStep := precision.Round(((stopAtPrice - startAtPrice) / float64(steps)), pricePrec)
+	curve, err := ladderCurve()
+	if err != nil {
+		return err
+	}
 
-	currSize := stopWithSize
-	currPrice := stopAtPrice
+	// the rungs are ordered from the ticker outwards; we start with the rung furthest away from the ticker
+	rungs := model.Ladder(startAtPrice, stopAtPrice, startWithSize, stopWithSize, steps, curve, pricePrec, sizePrec)
 
 	var (
 		totalSize     float64
@@ -108,9 +106,12 @@ func exit(
 	tbl := table.NewWriter()
 	tbl.AppendHeader(table.Row{"", "Price", "Size", "Proceeds"})
 
-	for currPrice >= startAtPrice {
+	for i := len(rungs) - 1; i >= 0; i-- {
+		currSize := rungs[i].Size
+		currPrice := rungs[i].Price
+
 		if !test {
-			ticker, err := exchange.GetTicker(client, market)
+			ticker, err := exchange.GetTicker(ctx, client, market)
 			if err != nil {
 				return err
 			}
@@ -130,14 +131,11 @@ func exit(
 
 		if !test {
 			if !hasLimitSell(currSize, currPrice) {
-				if _, _, err := exchange.Order(client, model.SELL, market, currSize, currPrice, model.LIMIT, ""); err != nil {
+				if _, _, err := exchange.Order(ctx, client, model.SELL, market, currSize, currPrice, model.LIMIT, nil, ""); err != nil {
 					return err
 				}
 			}
 		}
-
-		currSize = precision.Round((currSize - sizeDeltaPerStep), sizePrec)
-		currPrice = precision.Round((currPrice - priceDeltaPerStep), pricePrec)
 	}
 
 	tbl.AppendSeparator()
@@ -226,12 +224,14 @@ func stopWithSize() (float64, error) {
 }
 
 func (c *ExitCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	exchange, err := exchanges.GetExchange()
 	if err != nil {
 		return c.ReturnError(err)
 	}
 
-	market, err := model.GetMarket(exchange)
+	market, err := model.GetMarket(ctx, exchange)
 	if err != nil {
 		return c.ReturnError(err)
 	}
@@ -264,7 +264,7 @@ func (c *ExitCommand) Run(args []string) int {
 		stopWithSize, startWithSize = startWithSize, stopWithSize
 	}
 
-	if err = exit(exchange, market, startAtPrice, stopAtPrice, startWithSize, stopWithSize, !flag.Exists("not-a-drill")); err != nil {
+	if err = exit(ctx, exchange, market, startAtPrice, stopAtPrice, startWithSize, stopWithSize, !flag.Exists("not-a-drill")); err != nil {
 		return c.ReturnError(err)
 	}
 
@@ -272,7 +272,31 @@ func (c *ExitCommand) Run(args []string) int {
 }
 
 func (c *ExitCommand) Help() string {
-	return "Usage: ./nefertiti exit"
+	text := `
+Usage: ./nefertiti exit [options]
+
+The exit command opens a ladder of limit sell orders, from --start-at-price
+up to --stop-at-price. The size of the orders ramps from --start-with-size
+up to --stop-with-size, so the orders furthest away from the ticker are the
+biggest. Orders at (or below) the ticker price are skipped.
+
+By default, the command is a drill: it reports the orders it would open.
+Include --not-a-drill to actually open the orders.
+
+Options:
+  --exchange        = name, for example: Binance
+  --market          = a valid market pair
+  --start-at-price  = price of the lowest sell order
+  --stop-at-price   = price of the highest sell order
+  --start-with-size = size of the lowest sell order
+  --stop-with-size  = size of the highest sell order
+  --steps           = number of steps between the lowest and the highest
+                      sell order (optional)
+  --curve           = [linear|exponential|fibonacci] distance between the
+                      steps (optional, defaults to linear)
+  --not-a-drill     = if included, opens the orders (optional)
+`
+	return strings.TrimSpace(text)
 }
 
 func (c *ExitCommand) Synopsis() string {
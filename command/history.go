@@ -0,0 +1,168 @@
+package command
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	HistoryCommand struct {
+		*CommandMeta
+	}
+)
+
+func historyWriteCSV(out io.Writer, fills model.Fills) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"Timestamp", "Market", "Side", "Price", "Size", "Fee", "Fee Asset", "ID", "Order ID", "Client Order ID"}); err != nil {
+		return err
+	}
+	for _, fill := range fills {
+		var fee string
+		if fill.FeeAsset != "" {
+			fee = taxFloat(fill.Fee)
+		}
+		if err := w.Write([]string{
+			fill.CreatedAt.UTC().Format(time.RFC3339),
+			fill.Market,
+			fill.Side.String(),
+			taxFloat(fill.Price),
+			taxFloat(fill.Size),
+			fee,
+			fill.FeeAsset,
+			fill.ID,
+			fill.OrderID,
+			fill.ClientOrderID,
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (c *HistoryCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	var (
+		err error
+		flg *flag.Flag
+	)
+
+	var exchange model.Exchange
+	if exchange, err = exchanges.GetExchange(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var client model.Client
+	if client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var all []model.Market
+	if all, err = exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split()); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var markets []model.Market
+	if markets, err = getMarkets(all); err != nil {
+		return c.ReturnError(err)
+	}
+
+	// --format=[json|csv]
+	format := "json"
+	flg = flag.Get("format")
+	if flg.Exists {
+		format = strings.ToLower(flg.String())
+		if format != "json" && format != "csv" {
+			return c.ReturnError(errors.Errorf("format %v is invalid", flg))
+		}
+	}
+
+	// --since=YYYY-MM-DD
+	var since time.Time
+	flg = flag.Get("since")
+	if flg.Exists {
+		if since, err = time.Parse("2006-01-02", flg.String()); err != nil {
+			return c.ReturnError(errors.Errorf("since %v is invalid", flg))
+		}
+	}
+
+	var fills model.Fills
+	for _, market := range markets {
+		var page model.Fills
+		if page, err = exchange.GetFills(ctx, client, market.Name, since); err != nil {
+			return c.ReturnError(err)
+		}
+		for _, fill := range page {
+			if !fill.CreatedAt.Before(since) {
+				fills = append(fills, fill)
+			}
+		}
+	}
+	fills.Sort()
+
+	// --output=file
+	var out io.Writer = os.Stdout
+	flg = flag.Get("output")
+	if flg.Exists {
+		var file *os.File
+		if file, err = os.Create(flg.String()); err != nil {
+			return c.ReturnError(errors.Wrap(err, 1))
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if format == "csv" {
+		if err = historyWriteCSV(out, fills); err != nil {
+			return c.ReturnError(errors.Wrap(err, 1))
+		}
+		return 0
+	}
+
+	var data []byte
+	if data, err = json.Marshal(fills); err != nil {
+		return c.ReturnError(err)
+	}
+	fmt.Fprintln(out, string(data))
+
+	return 0
+}
+
+func (c *HistoryCommand) Help() string {
+	text := `
+Usage: ./nefertiti history [options]
+
+The history command downloads the fills (aka trades) of your orders, and
+writes them to JSON or CSV, oldest fill first.
+
+Please note that some exchanges do not report the fees you paid. Some other
+exchanges only report the fills of the last couple of days or weeks.
+
+Options:
+  --exchange = name
+  --market   = one or more market pairs, or "all"
+  --quote    = if --market=all, then only the markets with this quote
+               currency, for example: BTC or USDT (optional)
+  --format   = [json|csv] (optional, defaults to json)
+  --since    = YYYY-MM-DD, only the fills from this date (optional, KuCoin
+               keeps no more than one year of fills)
+  --output   = name of the file (optional, defaults to the console)
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *HistoryCommand) Synopsis() string {
+	return "Export your trade history to JSON or CSV."
+}
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"math"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+)
+
+func ladderCurve() (model.LadderCurve, error) {
+	arg := flag.Get("curve")
+	if !arg.Exists {
+		return model.LADDER_LINEAR, nil
+	}
+	out, err := model.NewLadderCurve(arg.String())
+	if err != nil {
+		return out, errors.Errorf("curve %v is invalid", arg)
+	}
+	return out, nil
+}
+
+// returns the --steps argument, or (if omitted) computes the number of steps from the price range
+func ladderSteps(nearPrice, farPrice, nearSize, farSize float64) (int64, error) {
+	out, err := steps()
+	if err != nil {
+		return out, err
+	}
+	if out < 1 {
+		out = int64(math.Round(math.Abs((farPrice - nearPrice) / ((farPrice * farSize) - (nearPrice * nearSize)))))
+		if out < 1 {
+			return out, errors.New("Cannot open any orders. Please widen your arguments")
+		}
+	}
+	return out, nil
+}
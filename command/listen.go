@@ -16,6 +16,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/shutdown"
 )
 
 type (
@@ -263,7 +264,7 @@ func post(resp http.ResponseWriter, req *http.Request) {
 	chStdError := make(chan string)
 	go func() {
 		err := cmd.Run()
-		if err == nil {
+		if err == nil || stopped(err) {
 			// this will happen after a DELETE, and that is why we empty the channel above
 			chCallback <- port
 		} else {
@@ -291,6 +292,12 @@ func post(resp http.ResponseWriter, req *http.Request) {
 	json.NewEncoder(resp).Encode(getPong(req))
 }
 
+// stopped returns true if the cmd exited because we asked it to stop
+func stopped(err error) bool {
+	exitErr, ok := err.(*exec.ExitError)
+	return ok && exitErr.ExitCode() == shutdown.EXIT_STOPPED
+}
+
 // POST 127.0.0.1:[port]/callback
 
 func callback(resp http.ResponseWriter, req *http.Request) {
@@ -0,0 +1,365 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package command
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/breaker"
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/notify"
+	"github.com/svanas/nefertiti/precision"
+	"github.com/svanas/nefertiti/shutdown"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	MakeCommand struct {
+		*CommandMeta
+	}
+)
+
+// the settings of the market maker
+type maker struct {
+	exchange  model.Exchange
+	client    model.Client
+	market    string
+	size      float64 // order size, in base currency
+	spread    float64 // distance (in %) between the mid price and our quotes
+	skew      float64 // how much (0..1) the inventory shifts our quotes
+	requote   float64 // how much (in %) the mid price must move before we re-quote
+	inventory float64 // base currency we held when we started
+	max       float64 // maximum base currency we will want to hold
+	started   time.Time
+	quotes    []string // the IDs of the orders we have opened, and that might still be open
+}
+
+// returns the base currency we hold: what we held when we started, plus what we bought, minus what we sold
+func (self *maker) getInventory(ctx context.Context) (float64, error) {
+	closed, err := self.exchange.GetClosed(ctx, self.client, self.market)
+	if err != nil {
+		return 0, err
+	}
+	out := self.inventory
+	for _, order := range closed {
+		// a quote we cancelled before it got (partially) filled didn't change what we hold
+		if order.Filled <= 0 || order.CreatedAt.Before(self.started) {
+			continue
+		}
+		if order.Side == model.BUY {
+			out = out + order.Filled
+		} else if order.Side == model.SELL {
+			out = out - order.Filled
+		}
+	}
+	return out, nil
+}
+
+// returns our bid and our ask around the mid price. when we are long, both quotes are lowered (so we are more likely to sell) and vice versa.
+// a zero bid means we should not buy, a zero ask means we should not sell.
+func (self *maker) getQuotes(mid, inventory float64, prec int) (bid, ask float64) {
+	half := mid * self.spread / 100
+	// inventory ratio, -1 (nothing) .. 0 (halfway) .. +1 (max)
+	ratio := 0.0
+	if self.max > 0 {
+		ratio = math.Max(-1, math.Min(1, (2*inventory/self.max)-1))
+	}
+	shift := ratio * self.skew * half
+
+	bid = precision.Floor(mid-half-shift, prec)
+	ask = precision.Ceil(mid+half-shift, prec)
+
+	// enforce the max inventory
+	if self.max > 0 && inventory+self.size > self.max {
+		bid = 0
+	}
+	// we cannot sell what we don't have
+	if inventory < self.size {
+		ask = 0
+	}
+
+	return bid, ask
+}
+
+// cancels the quotes we have opened (that are still open), leaving the other orders on this market alone
+func (self *maker) cancel(ctx context.Context) error {
+	if len(self.quotes) == 0 {
+		return nil
+	}
+	opened, err := self.exchange.GetOpened(ctx, self.client, self.market)
+	if err != nil {
+		return err
+	}
+	ours := func(order model.Order) bool {
+		for _, id := range self.quotes {
+			// some exchanges hand us the client order ID when we open an order
+			if id == order.ID || (order.ClientOrderID != "" && id == order.ClientOrderID) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, order := range opened {
+		if ours(order) {
+			if err = self.exchange.CancelOrder(ctx, self.client, self.market, order.ID); err != nil {
+				return err
+			}
+		}
+	}
+	self.quotes = nil
+	return nil
+}
+
+// cancels our quotes, and then opens new quotes
+func (self *maker) quote(ctx context.Context, mid, inventory float64) error {
+	var err error
+
+	var prec int
+	if prec, err = self.exchange.GetPricePrec(ctx, self.client, self.market); err != nil {
+		return err
+	}
+
+	bid, ask := self.getQuotes(mid, inventory, prec)
+
+	if err = self.cancel(ctx); err != nil {
+		return err
+	}
+
+	for _, order := range []struct {
+		side  model.OrderSide
+		price float64
+	}{{model.BUY, bid}, {model.SELL, ask}} {
+		if order.price > 0 {
+			var oid []byte
+			if oid, _, err = self.exchange.Order(ctx, self.client, order.side, self.market, self.size, order.price, model.LIMIT, nil, ""); err != nil {
+				return err
+			}
+			if oid != nil {
+				self.quotes = append(self.quotes, string(oid))
+			}
+		}
+	}
+
+	log.Printf("[INFO] Quoting %s. Mid: %g. Bid: %g. Ask: %g. Inventory: %g\n", self.market, mid, bid, ask, inventory)
+
+	return nil
+}
+
+func (c *MakeCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	var (
+		err error
+		flg *flag.Flag
+	)
+
+	mm := maker{started: time.Now()}
+
+	if mm.exchange, err = exchanges.GetExchange(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	if mm.market, err = model.GetMarket(ctx, mm.exchange); err != nil {
+		return c.ReturnError(err)
+	}
+	if mm.market == "all" {
+		return c.ReturnError(errors.Errorf("market %s is invalid", mm.market))
+	}
+
+	if mm.client, err = mm.exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var sizePrec int
+	if sizePrec, err = mm.exchange.GetSizePrec(ctx, mm.client, mm.market); err != nil {
+		return c.ReturnError(err)
+	}
+
+	// --size=X (in base currency)
+	flg = flag.Get("size")
+	if !flg.Exists {
+		return c.ReturnError(errors.New("missing argument: size"))
+	}
+	if mm.size, err = flg.Float64(); err != nil || mm.size <= 0 {
+		return c.ReturnError(errors.Errorf("size %v is invalid", flg))
+	}
+	mm.size = precision.Round(mm.size, sizePrec)
+
+	// --spread=X (in %)
+	mm.spread = 0.5
+	flg = flag.Get("spread")
+	if flg.Exists {
+		if mm.spread, err = flg.Float64(); err != nil || mm.spread <= 0 {
+			return c.ReturnError(errors.Errorf("spread %v is invalid", flg))
+		}
+	}
+
+	// --skew=[0..1]
+	mm.skew = 0.5
+	flg = flag.Get("skew")
+	if flg.Exists {
+		if mm.skew, err = flg.Float64(); err != nil || mm.skew < 0 || mm.skew > 1 {
+			return c.ReturnError(errors.Errorf("skew %v is not in the 0..1 range", flg))
+		}
+	}
+
+	// --requote=X (in %)
+	mm.requote = mm.spread / 2
+	flg = flag.Get("requote")
+	if flg.Exists {
+		if mm.requote, err = flg.Float64(); err != nil || mm.requote < 0 {
+			return c.ReturnError(errors.Errorf("requote %v is invalid", flg))
+		}
+	}
+
+	// --inventory=X (in base currency)
+	flg = flag.Get("inventory")
+	if flg.Exists {
+		if mm.inventory, err = flg.Float64(); err != nil || mm.inventory < 0 {
+			return c.ReturnError(errors.Errorf("inventory %v is invalid", flg))
+		}
+	}
+
+	// --max=X (in base currency)
+	flg = flag.Get("max")
+	if !flg.Exists {
+		return c.ReturnError(errors.New("missing argument: max"))
+	}
+	if mm.max, err = flg.Float64(); err != nil || mm.max < mm.size {
+		return c.ReturnError(errors.Errorf("max %v is invalid", flg))
+	}
+
+	// --repeat=X (in minutes)
+	var repeat float64 = 1
+	flg = flag.Get("repeat")
+	if flg.Exists {
+		if repeat, err = flg.Float64(); err != nil || repeat <= 0 {
+			return c.ReturnError(errors.Errorf("repeat %v is invalid", flg))
+		}
+	}
+
+	var service model.Notify = nil
+	if service, err = notify.New().Init(flag.Interactive(), true); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var level int64 = notify.LEVEL_DEFAULT
+	if level, err = notify.Level(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	msg := fmt.Sprintf("Making %s...", mm.market)
+	log.Println("[INFO] " + msg)
+	if service != nil && notify.CanSend(level, notify.INFO) {
+		service.SendMessage(msg, (mm.exchange.GetInfo().Name + " - INFO"), model.ALWAYS)
+	}
+
+	if err = c.ReturnSuccess(); err != nil {
+		return c.ReturnError(err)
+	}
+	// the bot knows its own quotes, so --cancel-on-exit works on every exchange
+	onStop(fmt.Sprintf("making %s", mm.market), mm.exchange, service, func() error {
+		return mm.cancel(ctx)
+	})
+
+	var (
+		last float64 // the mid price we last quoted around
+		held float64 // the inventory we last quoted with
+	)
+	cb := breaker.Get(mm.exchange.GetInfo().Name)
+	for cb.Wait() {
+		if err = func() error {
+			var top *bookTop
+			if top, err = getBookTop(ctx, mm.exchange, mm.client, mm.market); err != nil {
+				return err
+			}
+			mid := top.Mid()
+			if mid == 0 {
+				return errors.Errorf("order book is empty. Market: %s", mm.market)
+			}
+			var inventory float64
+			if inventory, err = mm.getInventory(ctx); err != nil {
+				return err
+			}
+			// re-quote when (a) the mid price has moved, or (b) one of our quotes got filled
+			if last == 0 || (math.Abs(mid-last)/last*100) >= mm.requote || inventory != held {
+				if err = mm.quote(ctx, mid, inventory); err != nil {
+					return err
+				}
+				last = mid
+				held = inventory
+			}
+			return nil
+		}(); err != nil && !transport.Done() {
+			log.Printf("[ERROR] %v\n", err)
+			if service != nil && notify.CanSend(level, notify.ERROR) && !cb.IsOpen() {
+				service.SendMessage(err.Error(), (mm.exchange.GetInfo().Name + " - ERROR"), model.ONCE_PER_MINUTE)
+			}
+		}
+		cb.Done(err, service, level)
+		if !shutdown.Sleep(time.Duration(repeat * float64(time.Minute))) {
+			break
+		}
+	}
+
+	return 0
+}
+
+func (c *MakeCommand) Help() string {
+	text := `
+Usage: ./nefertiti make [options]
+
+The make command keeps a limit buy order and a limit sell order around the
+mid price of a market. The bot will re-quote when the mid price moves, or
+when one of the orders gets filled. When you hold more of the base asset,
+both orders are moved down (and vice versa).
+
+Please note that the bot only cancels the orders it opened itself. Other
+orders on this market are left alone.
+
+Options:
+  --exchange  = name, for example: Bittrex
+  --market    = a valid market pair.
+  --size      = amount (in base currency) of every order.
+  --max       = maximum amount (in base currency) that you will want to hold.
+  --inventory = amount (in base currency) that you hold when the bot starts.
+                (optional, defaults to 0)
+  --spread    = distance (in %) between the mid price and the orders.
+                (optional, defaults to 0.5)
+  --skew      = [0..1] how much the inventory moves the orders.
+                (optional, defaults to 0.5)
+  --requote   = percentage the mid price must move before the bot re-quotes.
+                (optional, defaults to half of --spread)
+  --repeat    = time (in minutes) between iterations.
+                (optional, defaults to 1 minute)
+  --cancel-on-exit = if included, cancels the orders that were opened by the
+                bot when you stop the bot. (optional, defaults to false)
+  --breaker   = number of consecutive network errors before the bot pauses
+                until the exchange recovers. (optional, defaults to 5, 0 = off)
+
+Network:
+  --rest-url = URL of the exchange REST API, for example: http://localhost:8080
+               (optional, defaults to the exchange)
+  --proxy    = http://, https:// or socks5:// proxy, for example:
+               socks5://127.0.0.1:1080 (optional, defaults to HTTPS_PROXY)
+
+  Each of these is either one URL, or a comma-separated list of exchange=URL
+  pairs, for example: --proxy=Binance=socks5://127.0.0.1:1080,Kucoin=...
+
+  --timeout  = deadline (in seconds) of every request, after which the request
+               is cancelled and (if it is safe) tried again (optional,
+               defaults to 30)
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *MakeCommand) Synopsis() string {
+	return "Keeps a buy order and a sell order around the mid price of a market."
+}
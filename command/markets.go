@@ -7,6 +7,7 @@ import (
 
 	"github.com/svanas/nefertiti/exchanges"
 	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -16,12 +17,14 @@ type (
 )
 
 func (c *MarketsCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	exchange, err := exchanges.GetExchange()
 	if err != nil {
 		return c.ReturnError(err)
 	}
 
-	markets, err := exchange.GetMarkets(true, flag.Sandbox(), flag.Get("ignore").Split())
+	markets, err := exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split())
 	if err != nil {
 		return c.ReturnError(err)
 	}
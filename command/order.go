@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/exchanges"
@@ -10,6 +11,7 @@ import (
 	"github.com/svanas/nefertiti/model"
 	"github.com/svanas/nefertiti/multiplier"
 	"github.com/svanas/nefertiti/pricing"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -19,6 +21,8 @@ type (
 )
 
 func (c *OrderCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	var (
 		err error
 		flg *flag.Flag
@@ -48,7 +52,7 @@ func (c *OrderCommand) Run(args []string) int {
 	}
 
 	var market string
-	if market, err = model.GetMarket(exchange); err != nil {
+	if market, err = model.GetMarket(ctx, exchange); err != nil {
 		return c.ReturnError(err)
 	}
 
@@ -67,11 +71,51 @@ func (c *OrderCommand) Run(args []string) int {
 		if price, err = flg.Float64(); err != nil {
 			return c.ReturnError(errors.Errorf("price %v is invalid", flg))
 		}
-	} else if kind == model.LIMIT {
+	} else if kind != model.MARKET {
 		return c.ReturnError(errors.New("missing argument: price"))
 	}
 
-	var client interface{}
+	var opts model.OrderOptions
+
+	// --stop-price=X
+	flg = flag.Get("stop-price")
+	if flg.Exists {
+		if opts.StopPrice, err = flg.Float64(); err != nil {
+			return c.ReturnError(errors.Errorf("stop-price %v is invalid", flg))
+		}
+	}
+
+	// --time-in-force=[gtc|ioc|fok|gtt]
+	flg = flag.Get("time-in-force")
+	if flg.Exists {
+		if opts.TimeInForce, err = model.NewTimeInForce(strings.ToLower(flg.String())); err != nil {
+			return c.ReturnError(errors.Errorf("time-in-force %v is invalid", flg))
+		}
+	}
+
+	// --expire-at=YYYY-MM-DDTHH:MM:SSZ
+	flg = flag.Get("expire-at")
+	if flg.Exists {
+		if opts.ExpireAt, err = time.Parse(time.RFC3339, flg.String()); err != nil {
+			return c.ReturnError(errors.Errorf("expire-at %v is invalid", flg))
+		}
+	}
+
+	opts.PostOnly = flag.Exists("post-only")
+
+	if err = opts.Validate(kind); err != nil {
+		return c.ReturnError(err)
+	}
+
+	caps := exchange.Capabilities()
+	if kind == model.MARKET && !caps.MarketOrders {
+		return c.ReturnError(errors.Errorf("%s does not support market orders", exchange.GetInfo().Name))
+	}
+	if opts.PostOnly && !caps.PostOnly {
+		return c.ReturnError(errors.Errorf("%s does not support post-only orders", exchange.GetInfo().Name))
+	}
+
+	var client model.Client
 	if client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
 		return c.ReturnError(err)
 	}
@@ -81,7 +125,7 @@ func (c *OrderCommand) Run(args []string) int {
 		return c.ReturnError(err)
 	} else if mult != 1.0 {
 		var prec int
-		if prec, err = exchange.GetPricePrec(client, market); err != nil {
+		if prec, err = exchange.GetPricePrec(ctx, client, market); err != nil {
 			return c.ReturnError(err)
 		} else {
 			price = pricing.Multiply(price, mult, prec)
@@ -93,12 +137,13 @@ func (c *OrderCommand) Run(args []string) int {
 		raw []byte
 	)
 	if oid, raw, err = exchange.Order(
+		ctx,
 		client,
 		side,
 		market,
 		size,
 		price,
-		kind, "",
+		kind, &opts, "",
 	); err != nil {
 		return c.ReturnError(err)
 	}
@@ -119,13 +164,23 @@ Usage: ./nefertiti order [options]
 The order command places an order with the specified exchange.
 
 Options:
-  --exchange = name
-  --side     = [buy|sell]
-  --type     = [limit|market]
-  --market   = a valid market pair
-  --size     = amount of cryptocurrency to buy or sell
-  --price    = price per unit (optional, not needed for market orders)
-  --mult     = vector to multiply price with (optional, defaults to 1.0)
+  --exchange      = name
+  --side          = [buy|sell]
+  --type          = [limit|market|stop-limit] (optional, defaults to limit)
+  --market        = a valid market pair
+  --size          = amount of cryptocurrency to buy or sell
+  --price         = price per unit (optional, not needed for market orders)
+  --mult          = vector to multiply price with (optional, defaults to 1.0)
+  --stop-price    = price that triggers a stop-limit order (optional, not
+                    needed for other orders)
+  --time-in-force = [gtc|ioc|fok|gtt] (optional, defaults to gtc)
+  --expire-at     = YYYY-MM-DDTHH:MM:SSZ, the time a gtt order gets cancelled
+                    (optional, not needed for other orders)
+  --post-only     = if included, the order gets cancelled if it would be
+                    filled immediately (optional)
+
+Not every exchange supports every order type or option. The order command
+returns an error if the exchange doesn't support the order.
 `
 	return strings.TrimSpace(text)
 }
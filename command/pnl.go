@@ -0,0 +1,268 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	PnLCommand struct {
+		*CommandMeta
+	}
+)
+
+type PnLPeriod int
+
+const (
+	PNL_PERIOD_DAY PnLPeriod = iota
+	PNL_PERIOD_WEEK
+	PNL_PERIOD_MONTH
+	PNL_PERIOD_YEAR
+)
+
+var PnLPeriodString = map[PnLPeriod]string{
+	PNL_PERIOD_DAY:   "day",
+	PNL_PERIOD_WEEK:  "week",
+	PNL_PERIOD_MONTH: "month",
+	PNL_PERIOD_YEAR:  "year",
+}
+
+func (period *PnLPeriod) String() string {
+	return PnLPeriodString[*period]
+}
+
+func NewPnLPeriod(data string) (PnLPeriod, error) {
+	for period := range PnLPeriodString {
+		if period.String() == data {
+			return period, nil
+		}
+	}
+	return PNL_PERIOD_MONTH, errors.Errorf("%s does not exist", data)
+}
+
+// returns the period a point in time falls into, for example: 2021-12 is December 2021
+func (period PnLPeriod) Format(t time.Time) string {
+	switch period {
+	case PNL_PERIOD_DAY:
+		return t.Format("2006-01-02")
+	case PNL_PERIOD_WEEK:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PNL_PERIOD_YEAR:
+		return t.Format("2006")
+	}
+	return t.Format("2006-01")
+}
+
+// returns the --market=X,Y,Z markets, or the --market=all markets (optionally with --quote=Q)
+func getMarkets(all []model.Market) ([]model.Market, error) {
+	flg := flag.Get("market")
+	if !flg.Exists {
+		return nil, errors.New("missing argument: market")
+	}
+	var out []model.Market
+	if flg.String() == "all" {
+		quote := model.Assets(flag.Get("quote").Split())
+		for _, market := range all {
+			if quote.IsEmpty() || quote.HasAsset(market.Quote) {
+				out = append(out, market)
+			}
+		}
+	} else {
+		for _, name := range flg.Split() {
+			i := model.IndexByMarket(all, name)
+			if i == -1 {
+				return nil, errors.Errorf("market %s does not exist", name)
+			}
+			out = append(out, all[i])
+		}
+	}
+	return out, nil
+}
+
+// the profit and loss of one market
+type pnlMarket struct {
+	market     model.Market
+	trades     model.Trades
+	open       model.Lots
+	fees       model.Fees
+	ticker     float64
+	realized   float64
+	unrealized float64
+}
+
+func (c *PnLCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	var (
+		err error
+		flg *flag.Flag
+	)
+
+	var exchange model.Exchange
+	if exchange, err = exchanges.GetExchange(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var client model.Client
+	if client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var all []model.Market
+	if all, err = exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split()); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var markets []model.Market
+	if markets, err = getMarkets(all); err != nil {
+		return c.ReturnError(err)
+	}
+
+	// --period=[day|week|month|year]
+	period := PNL_PERIOD_MONTH
+	flg = flag.Get("period")
+	if flg.Exists {
+		if period, err = NewPnLPeriod(flg.String()); err != nil {
+			return c.ReturnError(errors.Errorf("period %v is invalid", flg))
+		}
+	}
+
+	var report []pnlMarket
+	for _, market := range markets {
+		var closed model.Orders
+		if closed, err = exchange.GetClosed(ctx, client, market.Name); err != nil {
+			return c.ReturnError(err)
+		}
+		if len(closed) == 0 {
+			continue
+		}
+		entry := pnlMarket{
+			market: market,
+			fees:   model.GetFees(ctx, exchange, client, market.Name),
+		}
+		entry.trades, entry.open = model.PairOrders(closed)
+		for _, trade := range entry.trades {
+			entry.realized += trade.PnL(entry.fees)
+		}
+		if len(entry.open) > 0 {
+			if entry.ticker, err = exchange.GetTicker(ctx, client, market.Name); err != nil {
+				return c.ReturnError(err)
+			}
+			for _, lot := range entry.open {
+				entry.unrealized += lot.PnL(entry.ticker, entry.fees)
+			}
+		}
+		report = append(report, entry)
+	}
+
+	// per market
+	tbl := table.NewWriter()
+	tbl.SetTitle("Per Market")
+	tbl.AppendHeader(table.Row{"Market", "Quote", "Trades", "Realized", "Open Size", "Ticker", "Unrealized"})
+	for _, entry := range report {
+		var size float64
+		for _, lot := range entry.open {
+			size += lot.Size
+		}
+		tbl.AppendRow(table.Row{
+			entry.market.Name,
+			entry.market.Quote,
+			len(entry.trades),
+			fmt.Sprintf("%.8f", entry.realized),
+			fmt.Sprintf("%g", size),
+			fmt.Sprintf("%g", entry.ticker),
+			fmt.Sprintf("%.8f", entry.unrealized),
+		})
+	}
+	fmt.Println(tbl.Render())
+
+	// per quote
+	type total struct {
+		realized   float64
+		unrealized float64
+	}
+	quotes := make(map[string]*total)
+	var keys []string
+	for _, entry := range report {
+		quote := strings.ToUpper(entry.market.Quote)
+		if _, ok := quotes[quote]; !ok {
+			quotes[quote] = &total{}
+			keys = append(keys, quote)
+		}
+		quotes[quote].realized += entry.realized
+		quotes[quote].unrealized += entry.unrealized
+	}
+	sort.Strings(keys)
+	tbl = table.NewWriter()
+	tbl.SetTitle("Per Quote")
+	tbl.AppendHeader(table.Row{"Quote", "Realized", "Unrealized", "Total"})
+	for _, quote := range keys {
+		tbl.AppendRow(table.Row{
+			quote,
+			fmt.Sprintf("%.8f", quotes[quote].realized),
+			fmt.Sprintf("%.8f", quotes[quote].unrealized),
+			fmt.Sprintf("%.8f", quotes[quote].realized+quotes[quote].unrealized),
+		})
+	}
+	fmt.Println(tbl.Render())
+
+	// per period
+	periods := make(map[string]float64)
+	keys = nil
+	for _, entry := range report {
+		for _, trade := range entry.trades {
+			key := period.Format(trade.SoldAt) + " " + strings.ToUpper(entry.market.Quote)
+			if _, ok := periods[key]; !ok {
+				keys = append(keys, key)
+			}
+			periods[key] += trade.PnL(entry.fees)
+		}
+	}
+	sort.Strings(keys)
+	tbl = table.NewWriter()
+	title := period.String()
+	tbl.SetTitle("Per " + strings.ToUpper(title[:1]) + title[1:])
+	tbl.AppendHeader(table.Row{"Period", "Quote", "Realized"})
+	for _, key := range keys {
+		split := strings.Split(key, " ")
+		tbl.AppendRow(table.Row{split[0], split[1], fmt.Sprintf("%.8f", periods[key])})
+	}
+	fmt.Println(tbl.Render())
+
+	return 0
+}
+
+func (c *PnLCommand) Help() string {
+	text := `
+Usage: ./nefertiti pnl [options]
+
+The pnl command pairs your filled buy orders with the sell orders that sold
+them, and then reports your realized profit and loss per market, per quote
+currency and per period. The buy orders that haven't been sold (yet) are
+valued at the current ticker price (aka unrealized profit and loss).
+
+Profit and loss are after fees, and in quote currency.
+
+Options:
+  --exchange = name
+  --market   = one or more market pairs, or "all"
+  --quote    = if --market=all, then only the markets with this quote
+               currency, for example: BTC or USDT (optional)
+  --period   = [day|week|month|year] (optional, defaults to month)
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *PnLCommand) Synopsis() string {
+	return "Report your realized and unrealized profit and loss."
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	PortfolioCommand struct {
+		*CommandMeta
+	}
+)
+
+func (c *PortfolioCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	var err error
+
+	var all exchanges.Exchanges
+	if all, err = exchanges.GetExchanges(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	// --reference=X
+	reference := model.USD
+	flg := flag.Get("reference")
+	if flg.Exists {
+		reference = strings.ToUpper(flg.String())
+	}
+
+	var (
+		total  float64
+		assets model.Balances // the value per asset (in reference currency) across exchanges
+	)
+
+	tbl := table.NewWriter()
+	tbl.SetTitle("Portfolio in " + reference)
+	tbl.AppendHeader(table.Row{"Exchange", "Asset", "Total", "Price", "Value"})
+	for _, exchange := range all {
+		var client model.Client
+		if client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
+			return c.ReturnError(err)
+		}
+		var markets []model.Market
+		if markets, err = exchange.GetMarkets(ctx, true, flag.Sandbox(), nil); err != nil {
+			return c.ReturnError(err)
+		}
+		var balances model.Balances
+		if balances, err = exchange.GetBalances(ctx, client); err != nil {
+			if len(all) == 1 {
+				return c.ReturnError(err)
+			}
+			log.Printf("[WARN] %v. Exchange: %s\n", err, exchange.GetInfo().Name)
+			continue
+		}
+		balances.Sort()
+		prices := model.NewPrices(ctx, exchange, client, markets)
+		for _, balance := range balances {
+			var price float64
+			if price, err = prices.Rate(balance.Asset, reference); err != nil {
+				log.Printf("[WARN] %v. Exchange: %s\n", err, exchange.GetInfo().Name)
+				tbl.AppendRow(table.Row{exchange.GetInfo().Name, strings.ToUpper(balance.Asset), fmt.Sprintf("%g", balance.Total), "?", "?"})
+				continue
+			}
+			value := balance.Total * price
+			total += value
+			assets.Add(model.Balance{Asset: balance.Asset, Total: value})
+			tbl.AppendRow(table.Row{
+				exchange.GetInfo().Name,
+				strings.ToUpper(balance.Asset),
+				fmt.Sprintf("%g", balance.Total),
+				fmt.Sprintf("%g", price),
+				fmt.Sprintf("%.2f", value),
+			})
+		}
+	}
+	tbl.AppendFooter(table.Row{"", "", "", "Total", fmt.Sprintf("%.2f", total)})
+	fmt.Println(tbl.Render())
+
+	// aggregate the value per asset across exchanges
+	if len(all) > 1 {
+		assets.Sort()
+		tbl = table.NewWriter()
+		tbl.SetTitle("Per Asset")
+		tbl.AppendHeader(table.Row{"Asset", "Value", "%"})
+		for _, asset := range assets {
+			var pct float64
+			if total > 0 {
+				pct = asset.Total / total * 100
+			}
+			tbl.AppendRow(table.Row{strings.ToUpper(asset.Asset), fmt.Sprintf("%.2f", asset.Total), fmt.Sprintf("%.2f", pct)})
+		}
+		fmt.Println(tbl.Render())
+	}
+
+	return 0
+}
+
+func (c *PortfolioCommand) Help() string {
+	text := `
+Usage: ./nefertiti portfolio [options]
+
+The portfolio command values your balances in a reference currency. Assets
+are converted via the markets on the same exchange, for example: ETH is
+converted to USD via ETH/BTC and then BTC/USD. When an exchange doesn't have
+a USD market, USD stablecoins (such as USDT) are valued at one USD.
+
+Options:
+  --exchange  = one or more names, for example: Binance,GDAX,Kucoin
+  --reference = currency that is used as the reference, for example: USD,
+                EUR, BTC or USDT (optional, defaults to USD)
+  --api-key   = exchange=key pairs, for example: Binance=X,KuCoin=Y
+                (optional, you will be prompted for the missing keys)
+  --api-secret, --api-passphrase = same as --api-key
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *PortfolioCommand) Synopsis() string {
+	return "Get the value of your balances in a reference currency."
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/svanas/nefertiti/exchanges"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -16,17 +17,19 @@ type (
 )
 
 func (c *QuoteCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	exchange, err := exchanges.GetExchange()
 	if err != nil {
 		return c.ReturnError(err)
 	}
 
-	markets, err := exchange.GetMarkets(true, flag.Sandbox(), flag.Get("ignore").Split())
+	markets, err := exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split())
 	if err != nil {
 		return c.ReturnError(err)
 	}
 
-	market, err := model.GetMarket(exchange)
+	market, err := model.GetMarket(ctx, exchange)
 	if err != nil {
 		return c.ReturnError(err)
 	}
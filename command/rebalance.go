@@ -0,0 +1,234 @@
+package command
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/precision"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	RebalanceCommand struct {
+		*CommandMeta
+	}
+)
+
+func (c *RebalanceCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	var (
+		err error
+		flg *flag.Flag
+	)
+
+	var exchange model.Exchange
+	if exchange, err = exchanges.GetExchange(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	// --weights=BTC:40,ETH:30,USDT:30
+	flg = flag.Get("weights")
+	if !flg.Exists {
+		return c.ReturnError(errors.New("missing argument: weights"))
+	}
+	var weights model.Weights
+	if weights, err = model.ParseWeights(flg.Split()); err != nil {
+		return c.ReturnError(err)
+	}
+
+	// --quote=X (the asset we trade against, defaults to the first fiat or stablecoin in the weights)
+	var quote string
+	flg = flag.Get("quote")
+	if flg.Exists {
+		quote = strings.ToUpper(flg.String())
+	} else {
+		for _, elem := range flag.Get("weights").Split() {
+			asset := strings.ToUpper(strings.Split(elem, ":")[0])
+			if model.Fiat(asset) || model.Pegged(asset) {
+				quote = asset
+				break
+			}
+		}
+		if quote == "" {
+			return c.ReturnError(errors.New("missing argument: quote"))
+		}
+	}
+	if _, ok := weights[quote]; !ok {
+		return c.ReturnError(errors.Errorf("quote %s does not have a weight", quote))
+	}
+
+	// --drift=X (in %)
+	var drift float64 = 5
+	flg = flag.Get("drift")
+	if flg.Exists {
+		if drift, err = flg.Float64(); err != nil || drift < 0 {
+			return c.ReturnError(errors.Errorf("drift %v is invalid", flg))
+		}
+	}
+
+	// --offset=X (in %)
+	var offset float64
+	flg = flag.Get("offset")
+	if flg.Exists {
+		if offset, err = flg.Float64(); err != nil || offset < 0 {
+			return c.ReturnError(errors.Errorf("offset %v is invalid", flg))
+		}
+	}
+
+	test := !flag.Exists("not-a-drill")
+
+	var client model.Client
+	if client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var markets []model.Market
+	if markets, err = exchange.GetMarkets(ctx, true, flag.Sandbox(), nil); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var balances model.Balances
+	if balances, err = exchange.GetBalances(ctx, client); err != nil {
+		return c.ReturnError(err)
+	}
+
+	// value every asset in the quote currency. we can only trade what isn't locked in an open order.
+	prices := model.NewPrices(ctx, exchange, client, markets)
+	values := make(map[string]float64)
+	for asset := range weights {
+		i := balances.IndexByAsset(asset)
+		if i == -1 {
+			continue
+		}
+		if values[asset], err = prices.Convert(balances[i].Available, asset, quote); err != nil {
+			return c.ReturnError(err)
+		}
+	}
+
+	// the sells are limit orders that won't fill right away, so the buys cannot spend more than the quote we have now
+	var budget float64
+	if i := balances.IndexByAsset(quote); i != -1 {
+		budget = balances[i].Available
+	}
+
+	type order struct {
+		side   model.OrderSide
+		market string
+		size   float64
+		price  float64
+	}
+	var orders []order
+
+	tbl := table.NewWriter()
+	tbl.AppendHeader(table.Row{"Asset", "Value", "Actual %", "Target %", "Side", "Market", "Size", "Price"})
+	for _, entry := range model.GetDrifts(values, weights) {
+		row := table.Row{
+			entry.Asset,
+			fmt.Sprintf("%.2f %s", entry.Value, quote),
+			fmt.Sprintf("%.2f", entry.Actual),
+			fmt.Sprintf("%.2f", entry.Weight),
+		}
+		if entry.Asset == quote || !entry.Exceeds(drift) {
+			tbl.AppendRow(row)
+			continue
+		}
+
+		market := exchange.FormatMarket(entry.Asset, quote)
+		if !model.HasMarket(markets, market) {
+			return c.ReturnError(errors.Errorf("market %s does not exist", market))
+		}
+
+		var ticker float64
+		if ticker, err = exchange.GetTicker(ctx, client, market); err != nil {
+			return c.ReturnError(err)
+		}
+
+		var sizePrec, pricePrec int
+		if sizePrec, err = exchange.GetSizePrec(ctx, client, market); err != nil {
+			return c.ReturnError(err)
+		}
+		if pricePrec, err = exchange.GetPricePrec(ctx, client, market); err != nil {
+			return c.ReturnError(err)
+		}
+
+		side := model.BUY
+		price := precision.Floor(ticker*(1-offset/100), pricePrec)
+		if entry.Delta() < 0 {
+			side = model.SELL
+			price = precision.Ceil(ticker*(1+offset/100), pricePrec)
+		}
+		size := precision.Floor(math.Abs(entry.Delta())/price, sizePrec)
+		if side == model.BUY {
+			size = math.Min(size, precision.Floor(budget/price, sizePrec))
+			budget -= size * price
+		}
+		if size <= 0 {
+			tbl.AppendRow(row)
+			continue
+		}
+
+		tbl.AppendRow(append(row,
+			model.FormatOrderSide(side),
+			market,
+			fmt.Sprintf("%.[2]*[1]f", size, sizePrec),
+			fmt.Sprintf("%.[2]*[1]f", price, pricePrec),
+		))
+
+		orders = append(orders, order{side: side, market: market, size: size, price: price})
+	}
+
+	fmt.Println(tbl.Render())
+
+	if !test {
+		for i, order := range orders {
+			if _, _, err = exchange.Order(ctx, client, order.side, order.market, order.size, order.price, model.LIMIT, nil, ""); err != nil {
+				return c.ReturnError(errors.Errorf("%v. Opened %d of %d orders", err, i, len(orders)))
+			}
+		}
+	}
+
+	return 0
+}
+
+func (c *RebalanceCommand) Help() string {
+	text := `
+Usage: ./nefertiti rebalance [options]
+
+The rebalance command compares your balances with your target weights, and
+then buys (or sells) the assets that have drifted too far from their target.
+Every asset is traded against the quote currency, with a limit order near
+the ticker price. Assets without a weight are ignored.
+
+The command looks at your available balances only: what is locked in your
+open orders is left alone. The buys are capped by the quote currency you
+have available now, so you might want to run the command again after your
+sells have filled.
+
+By default, the command is a drill: it reports the orders it would open.
+Include --not-a-drill to actually open the orders.
+
+Options:
+  --exchange    = name, for example: Binance
+  --weights     = target weights, for example: BTC:40,ETH:30,USDT:30
+  --quote       = asset that the other assets are traded against, for example:
+                  USDT (optional, defaults to the first fiat currency or
+                  stablecoin in the weights)
+  --drift       = how far (in %) an asset may drift from its target weight
+                  before it gets rebalanced (optional, defaults to 5)
+  --offset      = distance (in %) between the ticker price and the orders
+                  (optional, defaults to 0)
+  --not-a-drill = if included, opens the orders (optional)
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *RebalanceCommand) Synopsis() string {
+	return "Buy and sell to get your balances back to their target weights."
+}
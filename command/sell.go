@@ -10,6 +10,7 @@ import (
 	"github.com/svanas/nefertiti/model"
 	"github.com/svanas/nefertiti/multiplier"
 	"github.com/svanas/nefertiti/notify"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -19,6 +20,8 @@ type (
 )
 
 func (c *SellCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	var err error
 
 	var exchange model.Exchange
@@ -30,13 +33,16 @@ func (c *SellCommand) Run(args []string) int {
 	if strategy, err = model.GetStrategy(); err != nil {
 		return c.ReturnError(err)
 	}
+	if strategy == model.STRATEGY_STOP_LOSS && !exchange.Capabilities().StopLossStrategy {
+		return c.ReturnError(fmt.Errorf("%s does not support --stoploss=Y", exchange.GetInfo().Name))
+	}
 
 	if _, err = multiplier.Get(multiplier.FIVE_PERCENT); err != nil {
 		return c.ReturnError(err)
 	}
 
 	var all []model.Market
-	if all, err = exchange.GetMarkets(true, flag.Sandbox(), flag.Get("ignore").Split()); err != nil {
+	if all, err = exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split()); err != nil {
 		return c.ReturnError(err)
 	}
 
@@ -68,6 +74,7 @@ func (c *SellCommand) Run(args []string) int {
 		if err != nil {
 			return err
 		}
+		onStop(fmt.Sprintf("listening to %s", exchange.GetInfo().Name), exchange, service, nil)
 		msg := fmt.Sprintf("Listening to %s...", exchange.GetInfo().Name)
 		log.Println("[INFO] " + msg)
 		if service != nil {
@@ -81,7 +88,7 @@ func (c *SellCommand) Run(args []string) int {
 		return nil
 	}
 
-	if err = exchange.Sell(strategy, hold, earn, flag.Sandbox(), flag.Exists("tweet"), flag.Debug(), success); err != nil {
+	if err = exchange.Sell(ctx, strategy, hold, earn, flag.Sandbox(), flag.Exists("tweet"), flag.Debug(), success); err != nil {
 		return c.ReturnError(err)
 	}
 
@@ -99,16 +106,44 @@ Options:
   --sandbox  = [Y|N] (optional)
   --stoploss = [Y|N] (optional)
   --notify   = [0|1|2|3] (see below)
-  --mult     = multiplier, for example: 1.05 (aka 5 percent, optional)
+  --mult     = multiplier, for example: 1.05 (aka 5 percent after fees, optional)
   --hold     = name of the market not to sell, for example: BTC-EUR (optional)
   --earn     = name of the market where you want to sell only enough of the
                base asset at "mult" to break even; hold the rest (optional)
+  --breaker  = number of consecutive network errors before the bot pauses
+               until the exchange recovers (optional, defaults to 5, 0 = off)
+
+Network:
+  --rest-url = URL of the exchange REST API, for example: http://localhost:8080
+               (optional, defaults to the exchange)
+  --ws-url   = URL of the exchange websocket feed, for example:
+               ws://localhost:8081 (optional, defaults to the exchange)
+  --proxy    = http://, https:// or socks5:// proxy, for example:
+               socks5://127.0.0.1:1080 (optional, defaults to HTTPS_PROXY)
+
+  Each of these is either one URL, or a comma-separated list of exchange=URL
+  pairs, for example: --proxy=Binance=socks5://127.0.0.1:1080,Kucoin=...
+
+  --timeout  = deadline (in seconds) of every request, after which the request
+               is cancelled and (if it is safe) tried again (optional,
+               defaults to 30)
+
+Fees:
+  --maker-fee    = fee (in %) you pay on limit orders, for example: 0.1
+                   (optional, defaults to what the exchange tells us)
+  --taker-fee    = fee (in %) you pay on market orders, for example: 0.1
+                   (optional, defaults to what the exchange tells us)
+  --fee-discount = discount (in %) you get on your fees, for example: 25 if
+                   you pay your fees in BNB on Binance (optional)
 
 Notify:
   0 = nothing, ever
   1 = errors only
   2 = errors + filled orders (default)
   3 = everything (including opened and cancelled orders)
+
+  --reference = if included, the filled order notifications include the value
+                of the order in this currency, for example: USD (optional)
 `
 	return strings.TrimSpace(text)
 }
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/signals"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	SignalsReportCommand struct {
+		*CommandMeta
+	}
+)
+
+func (c *SignalsReportCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	journal, err := signals.LoadJournal()
+	if err != nil {
+		return c.ReturnError(err)
+	}
+
+	// if we have an arg named --exchange, then resolve the outcome of the pending calls first
+	if flag.Exists("exchange") {
+		exchange, err := exchanges.GetExchange()
+		if err != nil {
+			return c.ReturnError(err)
+		}
+		client, err := exchange.GetClient(model.PRIVATE, flag.Sandbox())
+		if err != nil {
+			return c.ReturnError(err)
+		}
+		if err = journal.Update(ctx, exchange, client); err != nil {
+			return c.ReturnError(err)
+		}
+		if err = journal.Save(); err != nil {
+			return c.ReturnError(err)
+		}
+	}
+
+	tbl := table.NewWriter()
+	tbl.AppendHeader(table.Row{"Channel", "Calls", "Skipped", "Open", "Filled", "Target", "Stopped", "Expired", "Hit Rate", "Avg Return", "Time-to-Target"})
+
+	for _, score := range journal.Score() {
+		tbl.AppendRow(table.Row{
+			score.Channel,
+			score.Calls,
+			score.Skipped,
+			score.Open,
+			score.Filled,
+			score.Target,
+			score.Stopped,
+			score.Expired,
+			fmt.Sprintf("%.2f%%", score.HitRate*100),
+			fmt.Sprintf("%.2f%%", score.AvgReturn*100),
+			score.TimeToTarget.Round(time.Minute).String(),
+		})
+	}
+
+	fmt.Println(tbl.Render())
+
+	return 0
+}
+
+func (c *SignalsReportCommand) Help() string {
+	text := `
+Usage: ./nefertiti signals report [options]
+
+The signals report command shows the hit rate, average return and time-to-target
+of every signal channel that the buy command has acted on.
+
+Options:
+  --exchange = name (optional, if included then the outcome of the pending calls
+               is looked up on this exchange before the report is shown)
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *SignalsReportCommand) Synopsis() string {
+	return "Report the performance of the signal channels."
+}
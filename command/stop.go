@@ -0,0 +1,76 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/notify"
+	"github.com/svanas/nefertiti/shutdown"
+)
+
+// onStop registers what we do after an orderly stop: (a) cancel our orders if --cancel-on-exit is included, and then (b) say we stopped doing what we did
+func onStop(what string, exchange model.Exchange, service model.Notify, cancel func() error) {
+	// the hooks run in reverse order, so we register the notification first
+	shutdown.OnStop(func() {
+		msg := fmt.Sprintf("Stopped %s.", what)
+		log.Println("[INFO] " + msg)
+		if service != nil {
+			level, err := notify.Level()
+			if err == nil && notify.CanSend(level, notify.INFO) {
+				if err = service.SendMessage(msg, (exchange.GetInfo().Name + " - INFO"), model.ALWAYS); err != nil {
+					log.Printf("[ERROR] %v\n", err)
+				}
+			}
+		}
+	})
+	if cancel != nil && flag.Exists("cancel-on-exit") {
+		shutdown.OnStop(func() {
+			if err := cancel(); err != nil {
+				log.Printf("[ERROR] %v\n", err)
+			}
+		})
+	}
+}
+
+// checkCancelOnExit returns an error if --cancel-on-exit is included, but the exchange cannot tell our orders
+func checkCancelOnExit(exchange model.Exchange) error {
+	if flag.Exists("cancel-on-exit") && !exchange.Capabilities().BotOrders {
+		return errors.Errorf("%s cannot tell which orders were opened by our bots. Please remove --cancel-on-exit", exchange.GetInfo().Name)
+	}
+	return nil
+}
+
+// cancelOnExit cancels the buy orders that were opened by our bots
+func cancelOnExit(ctx context.Context, exchange model.Exchange, client model.Client, markets []string) error {
+	if len(markets) == 0 || (len(markets) == 1 && markets[0] == "all") {
+		all, err := exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split())
+		if err != nil {
+			return err
+		}
+		markets = nil
+		for _, market := range all {
+			markets = append(markets, market.Name)
+		}
+	}
+
+	filter := &model.CancelFilter{Side: model.BUY, BotOnly: true}
+	for _, market := range markets {
+		opened, err := exchange.GetOpened(ctx, client, market)
+		if err != nil {
+			return err
+		}
+		for _, order := range opened.Filter(filter, time.Now()) {
+			if err = exchange.CancelOrder(ctx, client, market, order.ID); err != nil {
+				return err
+			}
+			log.Printf("[INFO] Cancelled order %s. Market: %s. Price: %g\n", order.ID, market, order.Price)
+		}
+	}
+
+	return nil
+}
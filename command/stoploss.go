@@ -8,6 +8,7 @@ import (
 	"github.com/svanas/nefertiti/exchanges"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type (
@@ -17,6 +18,8 @@ type (
 )
 
 func (c *StopLossCommand) Run(args []string) int {
+	ctx := transport.Context()
+
 	var (
 		err error
 		flg *flag.Flag
@@ -26,18 +29,21 @@ func (c *StopLossCommand) Run(args []string) int {
 	if exchange, err = exchanges.GetExchange(); err != nil {
 		return c.ReturnError(err)
 	}
+	if !exchange.Capabilities().StopLoss {
+		return c.ReturnError(errors.Errorf("%s does not support stop-loss orders", exchange.GetInfo().Name))
+	}
 
 	var kind model.OrderType = model.LIMIT
 	flg = flag.Get("type")
 	if flg.Exists {
 		kind = model.NewOrderType(flg.String())
-		if kind == model.ORDER_TYPE_NONE {
+		if kind != model.LIMIT && kind != model.MARKET {
 			return c.ReturnError(errors.Errorf("type %v is invalid", flg))
 		}
 	}
 
 	var market string
-	if market, err = model.GetMarket(exchange); err != nil {
+	if market, err = model.GetMarket(ctx, exchange); err != nil {
 		return c.ReturnError(err)
 	}
 
@@ -60,13 +66,14 @@ func (c *StopLossCommand) Run(args []string) int {
 		return c.ReturnError(errors.New("missing argument: price"))
 	}
 
-	var client interface{}
+	var client model.Client
 	if client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
 		return c.ReturnError(err)
 	}
 
 	var out []byte
 	if out, err = exchange.StopLoss(
+		ctx,
 		client,
 		market,
 		size,
@@ -0,0 +1,435 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package command
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/exchanges"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
+)
+
+type (
+	TaxCommand struct {
+		*CommandMeta
+	}
+)
+
+type TaxFormat int
+
+const (
+	TAX_FORMAT_GENERIC TaxFormat = iota
+	TAX_FORMAT_KOINLY
+	TAX_FORMAT_COINTRACKER
+)
+
+var TaxFormatString = map[TaxFormat]string{
+	TAX_FORMAT_GENERIC:     "generic",
+	TAX_FORMAT_KOINLY:      "koinly",
+	TAX_FORMAT_COINTRACKER: "cointracker",
+}
+
+func (format *TaxFormat) String() string {
+	return TaxFormatString[*format]
+}
+
+func NewTaxFormat(data string) (TaxFormat, error) {
+	for format := range TaxFormatString {
+		if format.String() == strings.ToLower(data) {
+			return format, nil
+		}
+	}
+	return TAX_FORMAT_GENERIC, errors.Errorf("%s does not exist", data)
+}
+
+// the currencies we value your trades in if you don't include the --fiat arg, in order of preference
+var taxFiats = []string{"USD", "EUR", "GBP", "USDT", "USDC", "BUSD"}
+
+// returns true if one of the markets is quoted in the currency
+func taxQuoted(markets []model.Market, curr string) bool {
+	for _, market := range markets {
+		if strings.EqualFold(market.Quote, curr) {
+			return true
+		}
+	}
+	return false
+}
+
+// values an asset in fiat currency, at a point in time
+type taxFiat struct {
+	ctx      context.Context // the context we get the candles and the tickers in
+	exchange model.MarketData
+	client   model.Client
+	markets  []model.Market
+	fiat     string
+	candles  map[string]model.Candles
+	prices   *model.Prices
+}
+
+// returns the fiat value of one unit of the asset, or zero if we don't know
+func (self *taxFiat) rate(asset string, t time.Time) float64 {
+	return self.rateIn(asset, self.fiat, t)
+}
+
+// returns the value of one unit of the asset in another asset (at the close of the day), or zero if we don't know
+func (self *taxFiat) rateIn(asset, to string, t time.Time) float64 {
+	if strings.EqualFold(asset, to) {
+		return 1
+	}
+	market := self.exchange.FormatMarket(asset, to)
+	candles, ok := self.candles[market]
+	if !ok {
+		if model.HasMarket(self.markets, market) {
+			var err error
+			if candles, err = self.exchange.GetCandles(self.ctx, self.client, market, model.CANDLE_1D); err != nil {
+				log.Printf("[WARN] %v. Market: %s\n", err, market)
+			}
+		}
+		self.candles[market] = candles
+	}
+	if candle := candles.At(t); candle != nil {
+		return candle.Close
+	}
+	return 0
+}
+
+// returns the fills, with the fees that were paid in a third asset (for example: BNB) converted into the quote currency.
+// we value the fee at the close of the day. if we cannot, then we value the fee at the current price.
+func (self *taxFiat) convertFees(market model.Market, fills model.Fills) model.Fills {
+	out := make(model.Fills, len(fills))
+	copy(out, fills)
+	for i := range out {
+		fill := &out[i]
+		if fill.Fee == 0 || fill.FeeAsset == "" || strings.EqualFold(fill.FeeAsset, market.Base) || strings.EqualFold(fill.FeeAsset, market.Quote) {
+			continue
+		}
+		rate := self.rateIn(fill.FeeAsset, market.Quote, fill.CreatedAt)
+		if rate == 0 {
+			var err error
+			if rate, err = self.prices.Rate(fill.FeeAsset, market.Quote); err != nil {
+				log.Printf("[WARN] %v. Fill: %s\n", err, fill.ID)
+				continue
+			}
+		}
+		fill.Fee = fill.Fee * rate
+		fill.FeeAsset = market.Quote
+	}
+	return out
+}
+
+// returns the fiat value of an amount of the asset, or an empty string if we don't know
+func (self *taxFiat) value(amount float64, asset string, t time.Time) string {
+	rate := self.rate(asset, t)
+	if rate == 0 {
+		return ""
+	}
+	return taxFloat(amount * rate)
+}
+
+func taxFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func taxDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+func taxWriteGeneric(w *csv.Writer, market model.Market, disposals model.Disposals, method model.LotMethod, fiat *taxFiat) error {
+	base := strings.ToUpper(market.Base)
+	quote := strings.ToUpper(market.Quote)
+	for _, disposal := range disposals {
+		var basis string
+		if !disposal.AcquiredAt.IsZero() {
+			basis = fiat.value(disposal.CostBasis, quote, disposal.AcquiredAt)
+		}
+		if err := w.Write([]string{
+			taxDate(disposal.AcquiredAt),
+			taxDate(disposal.SoldAt),
+			market.Name,
+			base,
+			taxFloat(disposal.Size),
+			taxFloat(disposal.Proceeds),
+			taxFloat(disposal.CostBasis),
+			taxFloat(disposal.Gain()),
+			quote,
+			fiat.value(disposal.Proceeds, quote, disposal.SoldAt),
+			basis,
+			strings.ToUpper(fiat.fiat),
+			strings.ToUpper(method.String()),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returns what we sent and what we received
+func taxSentReceived(market model.Market, fill *model.Fill) (sent float64, sentAsset string, received float64, receivedAsset string) {
+	base := strings.ToUpper(market.Base)
+	quote := strings.ToUpper(market.Quote)
+	if fill.Side == model.BUY {
+		return fill.Size * fill.Price, quote, fill.Size, base
+	}
+	return fill.Size, base, fill.Size * fill.Price, quote
+}
+
+func taxWriteKoinly(w *csv.Writer, market model.Market, fills model.Fills, fiat *taxFiat) error {
+	for i := range fills {
+		fill := &fills[i]
+		sent, sentAsset, received, receivedAsset := taxSentReceived(market, fill)
+		var fee string
+		if fill.FeeAsset != "" {
+			fee = taxFloat(fill.Fee)
+		}
+		worth := fiat.value(received, receivedAsset, fill.CreatedAt)
+		if worth == "" {
+			worth = fiat.value(sent, sentAsset, fill.CreatedAt)
+		}
+		if err := w.Write([]string{
+			fill.CreatedAt.UTC().Format("2006-01-02 15:04:05 UTC"),
+			taxFloat(sent),
+			sentAsset,
+			taxFloat(received),
+			receivedAsset,
+			fee,
+			strings.ToUpper(fill.FeeAsset),
+			worth,
+			strings.ToUpper(fiat.fiat),
+			"",
+			fill.Side.String() + " " + market.Name,
+			fill.ID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func taxWriteCoinTracker(w *csv.Writer, market model.Market, fills model.Fills) error {
+	for i := range fills {
+		fill := &fills[i]
+		sent, sentAsset, received, receivedAsset := taxSentReceived(market, fill)
+		var fee string
+		if fill.FeeAsset != "" {
+			fee = taxFloat(fill.Fee)
+		}
+		if err := w.Write([]string{
+			fill.CreatedAt.UTC().Format("01/02/2006 15:04:05"),
+			taxFloat(received),
+			receivedAsset,
+			taxFloat(sent),
+			sentAsset,
+			fee,
+			strings.ToUpper(fill.FeeAsset),
+			"",
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *TaxCommand) Run(args []string) int {
+	ctx := transport.Context()
+
+	var (
+		err error
+		flg *flag.Flag
+	)
+
+	var exchange model.Exchange
+	if exchange, err = exchanges.GetExchange(); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var client model.Client
+	if client, err = exchange.GetClient(model.PRIVATE, flag.Sandbox()); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var all []model.Market
+	if all, err = exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split()); err != nil {
+		return c.ReturnError(err)
+	}
+
+	var markets []model.Market
+	if markets, err = getMarkets(all); err != nil {
+		return c.ReturnError(err)
+	}
+
+	// --method=[fifo|lifo|hifo]
+	method := model.FIFO
+	flg = flag.Get("method")
+	if flg.Exists {
+		if method, err = model.NewLotMethod(flg.String()); err != nil {
+			return c.ReturnError(errors.Errorf("method %v is invalid", flg))
+		}
+	}
+
+	// --format=[generic|koinly|cointracker]
+	format := TAX_FORMAT_GENERIC
+	flg = flag.Get("format")
+	if flg.Exists {
+		if format, err = NewTaxFormat(flg.String()); err != nil {
+			return c.ReturnError(errors.Errorf("format %v is invalid", flg))
+		}
+	}
+
+	// --fiat=X (defaults to the first currency in taxFiats that the exchange quotes)
+	fiat := &taxFiat{
+		ctx:      ctx,
+		exchange: exchange,
+		client:   client,
+		markets:  all,
+		candles:  make(map[string]model.Candles),
+		prices:   model.NewPrices(ctx, exchange, client, all),
+	}
+	flg = flag.Get("fiat")
+	if flg.Exists {
+		if !taxQuoted(all, flg.String()) {
+			return c.ReturnError(errors.Errorf("fiat %v is invalid. %s does not have any %v markets", flg, exchange.GetInfo().Name, flg))
+		}
+		fiat.fiat = flg.String()
+	} else {
+		for _, curr := range taxFiats {
+			if taxQuoted(all, curr) {
+				fiat.fiat = curr
+				break
+			}
+		}
+		if fiat.fiat == "" {
+			return c.ReturnError(errors.New("missing argument: fiat"))
+		}
+	}
+
+	// --since=YYYY-MM-DD
+	var since time.Time
+	flg = flag.Get("since")
+	if flg.Exists {
+		if since, err = time.Parse("2006-01-02", flg.String()); err != nil {
+			return c.ReturnError(errors.Errorf("since %v is invalid", flg))
+		}
+	}
+
+	// --output=file
+	var out io.Writer = os.Stdout
+	flg = flag.Get("output")
+	if flg.Exists {
+		var file *os.File
+		if file, err = os.Create(flg.String()); err != nil {
+			return c.ReturnError(errors.Wrap(err, 1))
+		}
+		defer file.Close()
+		out = file
+	}
+
+	w := csv.NewWriter(out)
+	switch format {
+	case TAX_FORMAT_KOINLY:
+		err = w.Write([]string{"Date", "Sent Amount", "Sent Currency", "Received Amount", "Received Currency", "Fee Amount", "Fee Currency", "Net Worth Amount", "Net Worth Currency", "Label", "Description", "TxHash"})
+	case TAX_FORMAT_COINTRACKER:
+		err = w.Write([]string{"Date", "Received Quantity", "Received Currency", "Sent Quantity", "Sent Currency", "Fee Amount", "Fee Currency", "Tag"})
+	default:
+		err = w.Write([]string{"Date Acquired", "Date Sold", "Market", "Asset", "Size", "Proceeds", "Cost Basis", "Gain", "Currency", "Proceeds (Fiat)", "Cost Basis (Fiat)", "Fiat", "Method"})
+	}
+	if err != nil {
+		return c.ReturnError(errors.Wrap(err, 1))
+	}
+
+	for _, market := range markets {
+		// we need the complete history to know the cost basis, even if we export a part of it
+		var fills model.Fills
+		if fills, err = exchange.GetFills(ctx, client, market.Name, time.Time{}); err != nil {
+			return c.ReturnError(err)
+		}
+		fills.Sort()
+
+		if format == TAX_FORMAT_GENERIC {
+			disposals, _ := model.Dispose(fiat.convertFees(market, fills), market.Base, market.Quote, method)
+			var export model.Disposals
+			for _, disposal := range disposals {
+				if !disposal.SoldAt.Before(since) {
+					export = append(export, disposal)
+				}
+			}
+			err = taxWriteGeneric(w, market, export, method, fiat)
+		} else {
+			var export model.Fills
+			for _, fill := range fills {
+				if !fill.CreatedAt.Before(since) {
+					export = append(export, fill)
+				}
+			}
+			if format == TAX_FORMAT_KOINLY {
+				err = taxWriteKoinly(w, market, export, fiat)
+			} else {
+				err = taxWriteCoinTracker(w, market, export)
+			}
+		}
+		if err != nil {
+			return c.ReturnError(errors.Wrap(err, 1))
+		}
+	}
+
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return c.ReturnError(errors.Wrap(err, 1))
+	}
+
+	return 0
+}
+
+func (c *TaxCommand) Help() string {
+	text := `
+Usage: ./nefertiti tax [options]
+
+The tax command downloads your trade history, and then exports it to CSV.
+
+The generic format has one row per disposal (aka a lot that you sold). The
+lot method decides what lot gets sold first. The cost basis includes the
+fees you paid when you bought the lot, and the proceeds are after the fees
+you paid when you sold the lot.
+
+The koinly and cointracker formats have one row per fill, and can be imported
+into these tax tools. The tax tool will then compute the cost basis.
+
+Fiat values are computed from the daily candles of the asset/fiat market on
+the same exchange. Values are left blank if the exchange doesn't have such a
+market, or if the trade is older than the candles. Fees that you paid in a
+third asset (for example: BNB) are valued the same way in the quote currency,
+or at the current price if the trade is older than the candles.
+
+Please note that some exchanges do not report the fees you paid. Some other
+exchanges only report the trades of the last couple of days or weeks.
+
+Options:
+  --exchange = name
+  --market   = one or more market pairs, or "all"
+  --quote    = if --market=all, then only the markets with this quote
+               currency, for example: BTC or USDT (optional)
+  --method   = [fifo|lifo|hifo] (optional, defaults to fifo)
+  --format   = [generic|koinly|cointracker] (optional, defaults to generic)
+  --fiat     = fiat currency, for example: USD or EUR (optional, defaults to
+               the first of USD, EUR, GBP, USDT, USDC or BUSD that the
+               exchange quotes)
+  --since    = YYYY-MM-DD, export only the trades from this date (optional)
+  --output   = name of the CSV file (optional, defaults to the console)
+`
+	return strings.TrimSpace(text)
+}
+
+func (c *TaxCommand) Synopsis() string {
+	return "Export your trades and your cost basis to CSV."
+}
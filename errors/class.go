@@ -0,0 +1,211 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Class tells us what kind of error we have, so we know what to do about it
+type Class int
+
+const (
+	UNCLASSIFIED Class = iota
+	RATE_LIMITED
+	INSUFFICIENT_FUNDS
+	INVALID_PRICE
+	INVALID_SIZE
+	MARKET_CLOSED
+	AUTH
+	TRANSIENT
+)
+
+var classString = map[Class]string{
+	UNCLASSIFIED:       "unclassified",
+	RATE_LIMITED:       "rate limited",
+	INSUFFICIENT_FUNDS: "insufficient funds",
+	INVALID_PRICE:      "invalid price",
+	INVALID_SIZE:       "invalid size",
+	MARKET_CLOSED:      "market closed",
+	AUTH:               "auth",
+	TRANSIENT:          "transient",
+}
+
+func (class Class) String() string {
+	if out, ok := classString[class]; ok {
+		return out
+	}
+	return fmt.Sprintf("%d", class)
+}
+
+// Retryable returns true if trying again (after a while) might succeed
+func (class Class) Retryable() bool {
+	return class == RATE_LIMITED || class == TRANSIENT
+}
+
+// Classifier maps the native error (or error code) of an exchange to a class. Returns UNCLASSIFIED if it does not recognize the error.
+type Classifier func(err error) Class
+
+var (
+	classifiers = make(map[string]Classifier)
+	classMutex  sync.RWMutex
+)
+
+// Register adds the classifier of an exchange. Every exchange registers a classifier that knows about its own error
+// codes, and that classifier only ever sees the errors of that exchange (see ClassIn).
+func Register(exchange string, classifier Classifier) {
+	classMutex.Lock()
+	defer classMutex.Unlock()
+	classifiers[strings.ToLower(exchange)] = classifier
+}
+
+// Code maps (part of) an error message to a class
+type Code struct {
+	Code  string
+	Class Class
+}
+
+// Codes is a list of codes. The first code that matches wins, so the specific codes go before the generic ones.
+type Codes []Code
+
+// Match returns the class of the first code that is part of the message (ignoring case), or UNCLASSIFIED if none of them are
+func (codes Codes) Match(msg string) Class {
+	msg = strings.ToLower(msg)
+	for _, code := range codes {
+		if strings.Contains(msg, strings.ToLower(code.Code)) {
+			return code.Class
+		}
+	}
+	return UNCLASSIFIED
+}
+
+// Classify returns the class of the first code that is part of the error message, or UNCLASSIFIED if none of them are
+func (codes Codes) Classify(err error) Class {
+	return codes.Match(err.Error())
+}
+
+// Classify returns a copy of the error, with a class attached to it
+func Classify(err error, class Class) *Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*Error); ok {
+		out := *e
+		out.class = class
+		return &out
+	}
+	out := Wrap(err, 1)
+	out.class = class
+	return out
+}
+
+// ClassOf returns the class of an error. An error either (a) has a class attached to it, or (b) is a common network
+// error, or (c) is UNCLASSIFIED. Use ClassIn for the errors that an exchange returned.
+func ClassOf(err error) Class {
+	return ClassIn("", err)
+}
+
+// ClassIn returns the class of an error that an exchange returned. An error either (a) has a class attached to it, or
+// (b) is recognized by the classifier of that exchange, or (c) is a common network error, or (d) is UNCLASSIFIED.
+func ClassIn(exchange string, err error) Class {
+	if err == nil {
+		return UNCLASSIFIED
+	}
+
+	if e, ok := err.(*Error); ok {
+		if e.class != UNCLASSIFIED {
+			return e.class
+		}
+		return ClassIn(exchange, e.Err)
+	}
+
+	// for example: the *url.Error that an http.Client wraps around the error of its transport
+	if e, ok := err.(interface{ Unwrap() error }); ok {
+		if class := ClassIn(exchange, e.Unwrap()); class != UNCLASSIFIED {
+			return class
+		}
+	}
+
+	if exchange != "" {
+		classMutex.RLock()
+		classifier, ok := classifiers[strings.ToLower(exchange)]
+		classMutex.RUnlock()
+		if ok {
+			if class := classifier(err); class != UNCLASSIFIED {
+				return class
+			}
+		}
+	}
+
+	return classOf(err)
+}
+
+// IsRetryable returns true if trying again (after a while) might succeed
+func IsRetryable(err error) bool {
+	return ClassOf(err).Retryable()
+}
+
+var (
+	// the HTTP status codes that tell us to try again later
+	transientStatus = []int{
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+	// the TCP/IP errors that tell us to try again later
+	transientCodes = Codes{
+		{Code: "no such host", Class: TRANSIENT},
+		{Code: "network is unreachable", Class: TRANSIENT},
+		{Code: "connection reset", Class: TRANSIENT},
+		{Code: "connection refused", Class: TRANSIENT},
+		{Code: "broken pipe", Class: TRANSIENT},
+		{Code: "operation timed out", Class: TRANSIENT},
+		{Code: "i/o timeout", Class: TRANSIENT},
+		{Code: "unexpected EOF", Class: TRANSIENT},
+	}
+)
+
+// ClassOfStatus returns the class of an HTTP status code
+func ClassOfStatus(code int) Class {
+	if code == http.StatusTooManyRequests {
+		return RATE_LIMITED
+	}
+	if code == http.StatusUnauthorized || code == http.StatusForbidden {
+		return AUTH
+	}
+	for _, status := range transientStatus {
+		if code == status {
+			return TRANSIENT
+		}
+	}
+	return UNCLASSIFIED
+}
+
+// classOf recognizes the common network errors, and the errors that are nothing but an HTTP status, for example: 502 Bad Gateway
+func classOf(err error) Class {
+	if err == context.DeadlineExceeded || err == io.EOF || err == io.ErrUnexpectedEOF {
+		return TRANSIENT
+	}
+	if e, ok := err.(net.Error); ok && e.Timeout() {
+		return TRANSIENT
+	}
+	if _, ok := err.(*net.DNSError); ok {
+		return TRANSIENT
+	}
+	for _, code := range []int{http.StatusTooManyRequests, http.StatusUnauthorized, http.StatusForbidden} {
+		if strings.Contains(err.Error(), fmt.Sprintf("%d %s", code, http.StatusText(code))) {
+			return ClassOfStatus(code)
+		}
+	}
+	for _, code := range transientStatus {
+		if strings.Contains(err.Error(), fmt.Sprintf("%d %s", code, http.StatusText(code))) {
+			return TRANSIENT
+		}
+	}
+	return transientCodes.Classify(err)
+}
@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Class
+	}{
+		{nil, UNCLASSIFIED},
+		{New("502 Bad Gateway"), TRANSIENT},
+		{fmt.Errorf("GET 429 Too Many Requests /api/v3/order"), RATE_LIMITED},
+		{fmt.Errorf("401 Unauthorized"), AUTH},
+		{fmt.Errorf("dial tcp: lookup api.binance.com: no such host"), TRANSIENT},
+		{fmt.Errorf("read: connection reset by peer"), TRANSIENT},
+		{fmt.Errorf("market BTC-EUR does not exist"), UNCLASSIFIED},
+		{Classify(fmt.Errorf("not enough BTC"), INSUFFICIENT_FUNDS), INSUFFICIENT_FUNDS},
+		{Wrap(Classify(fmt.Errorf("502 Bad Gateway"), AUTH), 0), AUTH},
+	}
+	for _, test := range tests {
+		if got := ClassOf(test.err); got != test.want {
+			t.Errorf("TestClassOf(%v) failed, got: %v, want: %v.", test.err, got, test.want)
+		}
+	}
+}
+
+func TestClassIn(t *testing.T) {
+	Register("Foo", Codes{{Code: "-1021", Class: TRANSIENT}}.Classify)
+	Register("Bar", Codes{{Code: "400002", Class: TRANSIENT}}.Classify)
+	tests := []struct {
+		exchange string
+		err      error
+		want     Class
+	}{
+		{"Foo", fmt.Errorf("code=-1021, msg=Timestamp for this request is outside of the recvWindow"), TRANSIENT},
+		{"foo", fmt.Errorf("code=-1021, msg=Timestamp for this request is outside of the recvWindow"), TRANSIENT},
+		{"Bar", fmt.Errorf("code=-1021, msg=Timestamp for this request is outside of the recvWindow"), UNCLASSIFIED},
+		{"Bar", fmt.Errorf("400002 Invalid KC-API-TIMESTAMP"), TRANSIENT},
+		{"Foo", fmt.Errorf("order 400002 does not exist"), UNCLASSIFIED},
+		{"", fmt.Errorf("400002 Invalid KC-API-TIMESTAMP"), UNCLASSIFIED},
+		{"Bar", fmt.Errorf("502 Bad Gateway"), TRANSIENT},
+		{"Bar", Classify(fmt.Errorf("400002 Invalid KC-API-TIMESTAMP"), AUTH), AUTH},
+	}
+	for _, test := range tests {
+		if got := ClassIn(test.exchange, test.err); got != test.want {
+			t.Errorf("TestClassIn(%s, %v) failed, got: %v, want: %v.", test.exchange, test.err, got, test.want)
+		}
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	policy := Backoff{Attempts: 5, Min: time.Second, Max: 5 * time.Second}
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		got := policy.Delay(attempt)
+		if got < want/2 || got > want {
+			t.Errorf("TestBackoffDelay(%d) failed, got: %v, want: between %v and %v.", attempt, got, want/2, want)
+		}
+	}
+}
+
+func TestCodesMatch(t *testing.T) {
+	codes := Codes{
+		{Code: "insufficient", Class: INSUFFICIENT_FUNDS},
+		{Code: "price", Class: INVALID_PRICE},
+	}
+	for msg, want := range map[string]Class{
+		"Insufficient balance at this price": INSUFFICIENT_FUNDS,
+		"order price is too low":             INVALID_PRICE,
+		"market is closed":                   UNCLASSIFIED,
+	} {
+		// the first code that matches wins, every time
+		for i := 0; i < 10; i++ {
+			if got := codes.Match(msg); got != want {
+				t.Errorf("TestCodesMatch(%s) failed, got: %v, want: %v.", msg, got, want)
+				break
+			}
+		}
+	}
+}
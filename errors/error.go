@@ -18,6 +18,7 @@ type Error struct {
 	stack  []uintptr
 	frames []StackFrame
 	suffix []string
+	class  Class
 }
 
 // New makes an Error from the given value.
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/svanas/nefertiti/shutdown"
+)
+
+// Backoff is our retry policy: we try again after an exponential delay (with jitter), for as long as the error is retryable
+type Backoff struct {
+	Attempts int           // the maximum number of attempts, including the first one
+	Min      time.Duration // the delay after the first attempt
+	Max      time.Duration // the delay never exceeds this
+}
+
+// DefaultBackoff tries 5 times, after 1, 2, 4 and 8 seconds (give or take some jitter)
+var DefaultBackoff = Backoff{
+	Attempts: 5,
+	Min:      time.Second,
+	Max:      30 * time.Second,
+}
+
+// Delay returns how long we wait after the (zero-based) attempt. The delay doubles every attempt, and then we pick
+// a random delay between half of that and all of it, so that our bots do not all try again at the same time.
+func (self *Backoff) Delay(attempt int) time.Duration {
+	out := self.Min
+	for i := 0; i < attempt && out < self.Max; i++ {
+		out = out * 2
+	}
+	if out > self.Max {
+		out = self.Max
+	}
+	if out <= 0 {
+		return 0
+	}
+	return out/2 + time.Duration(rand.Int63n(int64(out/2)+1))
+}
+
+// Retry calls fn until (a) it succeeds, or (b) it returns an error that is not retryable, or (c) we run out of attempts,
+// or (d) we are asked to stop. Returns the last error.
+func (self *Backoff) Retry(fn func() error) error {
+	return self.RetryIf(fn, IsRetryable)
+}
+
+// RetryIf calls fn until (a) it succeeds, or (b) it returns an error that retryable says no to, or (c) we run out of
+// attempts, or (d) we are asked to stop. Returns the last error.
+func (self *Backoff) RetryIf(fn func() error, retryable func(err error) bool) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !retryable(err) || attempt+1 >= self.Attempts {
+			return err
+		}
+		delay := self.Delay(attempt)
+		log.Printf("[WARNING] %v. Trying again in %v\n", err, delay.Round(time.Millisecond))
+		if !shutdown.Sleep(delay) {
+			return err
+		}
+	}
+}
+
+// Retry calls fn with our DefaultBackoff policy
+func Retry(fn func() error) error {
+	return DefaultBackoff.Retry(fn)
+}
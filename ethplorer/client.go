@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/svanas/nefertiti/transport"
 )
 
 const (
@@ -54,7 +56,7 @@ func New(apiKey string) *Client {
 func (client *Client) get(url string) ([]byte, error) {
 	var err error
 	var resp *http.Response
-	if resp, err = http.Get(url); err != nil {
+	if resp, err = transport.New().Get(url); err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
@@ -16,6 +16,7 @@ import (
 	filemutex "github.com/alexflint/go-filemutex"
 	"github.com/svanas/nefertiti/aggregation"
 	"github.com/svanas/nefertiti/binance"
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/logger"
@@ -25,6 +26,8 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
+	"github.com/svanas/nefertiti/shutdown"
+	"github.com/svanas/nefertiti/transport"
 )
 
 var (
@@ -72,6 +75,9 @@ func init() {
 			}
 		}
 
+		// re-measure the offset between our clock and the Binance clock every now and then
+		client.SyncTime()
+
 		if flag.Debug() {
 			log.Printf("[DEBUG] %s %s (weight: %d)\n", method, path, weight)
 		}
@@ -94,6 +100,42 @@ func isBinanceError(err error) (*binance.BinanceError, bool) {
 	return binance.IsBinanceError(err)
 }
 
+// binanceCodes maps the Binance filter failures (they all share the same -1013 code) to our error classes
+var binanceCodes = errors.Codes{
+	{Code: "PRICE_FILTER", Class: errors.INVALID_PRICE},
+	{Code: "PERCENT_PRICE", Class: errors.INVALID_PRICE},
+	{Code: "Invalid price", Class: errors.INVALID_PRICE},
+	{Code: "LOT_SIZE", Class: errors.INVALID_SIZE},
+	{Code: "MIN_NOTIONAL", Class: errors.INVALID_SIZE},
+	{Code: "Invalid quantity", Class: errors.INVALID_SIZE},
+}
+
+// binanceClassify maps the Binance error codes to our error classes
+func binanceClassify(err error) errors.Class {
+	binanceError, ok := binance.IsBinanceError(err)
+	if !ok {
+		return errors.UNCLASSIFIED
+	}
+	switch binanceError.Code {
+	case -1003, -1015: // TOO_MANY_REQUESTS, TOO_MANY_ORDERS
+		return errors.RATE_LIMITED
+	case -1000, -1001, -1006, -1007, -1021: // UNKNOWN, DISCONNECTED, UNEXPECTED_RESP, TIMEOUT, INVALID_TIMESTAMP
+		return errors.TRANSIENT
+	case -1002, -1022, -2014, -2015: // UNAUTHORIZED, INVALID_SIGNATURE, BAD_API_KEY_FMT, REJECTED_MBX_KEY
+		return errors.AUTH
+	case -1013: // INVALID_MESSAGE, aka filter failure
+		return binanceCodes.Classify(err)
+	case -2010: // NEW_ORDER_REJECTED
+		if strings.Contains(binanceError.Message, "insufficient balance") {
+			return errors.INSUFFICIENT_FUNDS
+		}
+		if strings.Contains(binanceError.Message, "Market is closed") {
+			return errors.MARKET_CLOSED
+		}
+	}
+	return errors.UNCLASSIFIED
+}
+
 func binanceOrderSide(order *binance.Order) model.OrderSide {
 	if order.Side == exchange.SideTypeSell {
 		return model.SELL
@@ -104,17 +146,54 @@ func binanceOrderSide(order *binance.Order) model.OrderSide {
 	return model.ORDER_SIDE_NONE
 }
 
-//lint:ignore U1000 func is unused
 func binanceOrderType(order *binance.Order) model.OrderType {
-	if order.Type == exchange.OrderTypeLimit || order.Type == exchange.OrderTypeStopLossLimit {
+	if order.Type == exchange.OrderTypeLimit || order.Type == exchange.OrderTypeLimitMaker {
 		return model.LIMIT
 	}
+	if order.Type == exchange.OrderTypeStopLossLimit || order.Type == exchange.OrderTypeTakeProfitLimit {
+		return model.STOP_LIMIT
+	}
 	if order.Type == exchange.OrderTypeMarket || order.Type == exchange.OrderTypeStopLoss {
 		return model.MARKET
 	}
 	return model.ORDER_TYPE_NONE
 }
 
+func binanceOrderStatus(order *binance.Order) model.OrderStatus {
+	switch order.Status {
+	case exchange.OrderStatusTypeNew:
+		return model.OPEN
+	case exchange.OrderStatusTypePartiallyFilled:
+		return model.PARTIALLY_FILLED
+	case exchange.OrderStatusTypeFilled:
+		return model.FILLED
+	case exchange.OrderStatusTypeCanceled, exchange.OrderStatusTypePendingCancel:
+		return model.CANCELLED
+	case exchange.OrderStatusTypeRejected:
+		return model.REJECTED
+	case exchange.OrderStatusTypeExpired:
+		return model.EXPIRED
+	}
+	return model.ORDER_STATUS_NONE
+}
+
+func binanceOrder(order *binance.Order) model.Order {
+	return model.Order{
+		ID:            strconv.FormatInt(order.OrderID, 10),
+		ClientOrderID: order.ClientOrderID,
+		Side:          binanceOrderSide(order),
+		Type:          binanceOrderType(order),
+		Status:        binanceOrderStatus(order),
+		Market:        order.Symbol,
+		Size:          order.GetSize(),
+		Price:         order.GetPrice(),
+		Filled:        order.GetFilled(),
+		AvgPrice:      order.GetAvgPrice(),
+		CreatedAt:     time.Unix(order.Time/1000, 0),
+		Bot:           binance.IsClientOrderID(order.ClientOrderID),
+	}
+}
+
 func binanceOrderIndex(orders []binance.Order, orderID int64) int {
 	for i, o := range orders {
 		if o.OrderID == orderID {
@@ -169,7 +248,7 @@ func (self *Binance) baseURL(sandbox bool) string {
 
 	output := self.ExchangeInfo.REST.URI
 
-	if output != binance.BASE_URL_US {
+	if output == binance.BASE_URL {
 		arg := flag.Get("cluster")
 		if arg.Exists {
 			if cluster, err := arg.Int64(); err == nil {
@@ -183,7 +262,9 @@ func (self *Binance) baseURL(sandbox bool) string {
 				}
 			}
 		} else {
-			client := &http.Client{Timeout: time.Second}
+			// go through our transport, so that we ping the clusters through --proxy (if any)
+			client := transport.New()
+			client.Timeout = time.Second
 
 			endpoints := map[string]time.Duration{
 				binance.BASE_URL:   0,
@@ -244,12 +325,28 @@ func (self *Binance) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
-func (self *Binance) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *Binance) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        false,
+		MarketOrders:     true,
+		MarketBuyByQuote: false,
+		PostOnly:         true,
+		StopLoss:         true,
+		StopLossStrategy: true,
+		OCO:              true,
+		Trailing:         false,
+		BotOrders:        true,
+		Candles:          []time.Duration{model.CANDLE_15M, model.CANDLE_1H, model.CANDLE_4H, model.CANDLE_1D},
+	}
+}
+
+func (self *Binance) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	if permission != model.PRIVATE {
 		return binance.New(self.baseURL(sandbox), "", ""), nil
 	}
 
-	apiKey, apiSecret, err := promptForApiKeys("Binance")
+	apiKey, apiSecret, err := promptForApiKeys(self.GetInfo().Name)
 	if err != nil {
 		return nil, err
 	}
@@ -257,10 +354,10 @@ func (self *Binance) GetClient(permission model.Permission, sandbox bool) (inter
 	return binance.New(self.baseURL(sandbox), apiKey, apiSecret), nil
 }
 
-func (self *Binance) GetMarkets(cached, sandbox bool, blacklist []string) ([]model.Market, error) {
+func (self *Binance) GetMarkets(ctx context.Context, cached, sandbox bool, blacklist []string) ([]model.Market, error) {
 	var out []model.Market
 
-	precs, err := binance.GetPrecs(binance.New(self.baseURL(sandbox), "", ""), cached)
+	precs, err := binance.GetPrecs(binance.New(self.baseURL(sandbox), "", "").WithContext(ctx), cached)
 
 	if err != nil {
 		return nil, errors.Wrap(err, 1)
@@ -286,8 +383,8 @@ func (self *Binance) GetMarkets(cached, sandbox bool, blacklist []string) ([]mod
 	return out, nil
 }
 
-func (self *Binance) getMarketsEx(cached, sandbox bool, ignore, quotes []string) ([]model.Market, error) {
-	markets, err := self.GetMarkets(cached, sandbox, ignore)
+func (self *Binance) getMarketsEx(ctx context.Context, cached, sandbox bool, ignore, quotes []string) ([]model.Market, error) {
+	markets, err := self.GetMarkets(ctx, cached, sandbox, ignore)
 
 	if err != nil {
 		return nil, err
@@ -351,12 +448,14 @@ func (self *Binance) listen(client *binance.Client, service model.Notify, level
 
 // listens to the filled orders, look for newly filled orders, automatically place new sell orders.
 func (self *Binance) sell(
+	ctx context.Context,
 	client *binance.Client,
 	strategy model.Strategy,
 	quotes []string,
 	mult, stop multiplier.Mult,
 	hold, earn model.Markets,
 	service model.Notify,
+	values *fillValuer,
 	twitter *notify.TwitterKeys,
 	level int64,
 	old []binance.Order,
@@ -370,7 +469,7 @@ func (self *Binance) sell(
 		new     []binance.Order
 		markets []model.Market
 	)
-	if markets, err = self.getMarketsEx(true, sandbox, nil, quotes); err != nil {
+	if markets, err = self.getMarketsEx(ctx, true, sandbox, nil, quotes); err != nil {
 		return old, err
 	}
 	for _, market := range markets {
@@ -409,6 +508,7 @@ func (self *Binance) sell(
 								title = fmt.Sprintf("%s %s", title, multiplier.Format(mult))
 							}
 						}
+						title += values.value(ctx, order.Symbol, order.GetSize(), order.GetPrice())
 						if err = service.SendMessage(order, title, model.ALWAYS); err != nil {
 							log.Printf("[ERROR] %v", err)
 						}
@@ -424,13 +524,13 @@ func (self *Binance) sell(
 						if order.Type == exchange.OrderTypeStopLoss || order.Type == exchange.OrderTypeStopLossLimit {
 							if flag.Dca() {
 								var prec int
-								if prec, err = self.GetSizePrec(client, order.Symbol); err == nil {
+								if prec, err = self.GetSizePrec(ctx, client, order.Symbol); err == nil {
 									size := 2.2 * order.GetSize()
-									_, _, err = self.Order(client,
+									_, _, err = self.Order(ctx, client,
 										model.BUY,
 										order.Symbol,
 										precision.Round(size, prec),
-										0, model.MARKET, "",
+										0, model.MARKET, nil, "",
 									)
 								}
 								if err != nil {
@@ -460,7 +560,7 @@ func (self *Binance) sell(
 							bought = call.Price
 						}
 						if bought == 0 {
-							if bought, err = self.GetTicker(client, order.Symbol); err != nil {
+							if bought, err = self.GetTicker(ctx, client, order.Symbol); err != nil {
 								return new, err
 							}
 						}
@@ -471,34 +571,37 @@ func (self *Binance) sell(
 						quote string
 					)
 					if base, quote, err = model.ParseMarket(markets, order.Symbol); err == nil {
-						qty := self.GetMaxSize(client, base, quote, hold.HasMarket(order.Symbol), earn.HasMarket(order.Symbol), order.GetSize(), mult)
+						fees := model.GetFees(ctx, self, client, order.Symbol)
+						qty := self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(order.Symbol), earn.HasMarket(order.Symbol), order.GetSize(), fees.Mult(mult))
 						if qty > 0 {
 							var prec int
-							if prec, err = self.GetPricePrec(client, order.Symbol); err == nil {
+							if prec, err = self.GetPricePrec(ctx, client, order.Symbol); err == nil {
 								var ticker float64
-								if ticker, err = self.GetTicker(client, order.Symbol); err == nil {
+								if ticker, err = self.GetTicker(ctx, client, order.Symbol); err == nil {
 									target := func() float64 {
 										if call != nil && call.HasTarget() {
 											return precision.Round(call.ParseTarget(), prec)
 										}
-										return pricing.Multiply(bought, mult, prec)
+										return pricing.Multiply(bought, fees.Mult(mult), prec)
 									}()
 									if ticker >= target {
-										_, _, err = self.Order(client,
+										_, _, err = self.Order(ctx, client,
 											model.SELL,
 											order.Symbol,
 											order.GetSize(),
 											0, model.MARKET,
+											nil,
 											strconv.FormatFloat(bought, 'f', -1, 64),
 										)
 									} else {
 										limit := func() error {
-											_, _, err := self.Order(client,
+											_, _, err := self.Order(ctx, client,
 												model.SELL,
 												order.Symbol,
 												qty,
 												target,
 												model.LIMIT,
+												nil,
 												strconv.FormatFloat(bought, 'f', -1, 64),
 											)
 											return err
@@ -508,7 +611,7 @@ func (self *Binance) sell(
 											var symbol *exchange.Symbol
 											if symbol, err = binance.GetSymbol(client, order.Symbol); err == nil {
 												if symbol.OcoAllowed {
-													if _, err = self.OCO(client,
+													if _, err = self.OCO(ctx, client,
 														order.Symbol,
 														qty,
 														target,
@@ -547,6 +650,7 @@ func (self *Binance) sell(
 }
 
 func (self *Binance) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -563,7 +667,7 @@ func (self *Binance) Sell(
 		apiKey    string
 		apiSecret string
 	)
-	if apiKey, apiSecret, err = promptForApiKeys("Binance"); err != nil {
+	if apiKey, apiSecret, err = promptForApiKeys(self.GetInfo().Name); err != nil {
 		return err
 	}
 
@@ -579,7 +683,8 @@ func (self *Binance) Sell(
 		}
 	}
 
-	client := binance.New(self.baseURL(sandbox), apiKey, apiSecret)
+	client := binance.New(self.baseURL(sandbox), apiKey, apiSecret).WithContext(ctx)
+	values := newFillValuer(self, client)
 
 	// get my open orders
 	var open []binance.Order
@@ -599,7 +704,7 @@ func (self *Binance) Sell(
 	} else {
 		flag.Set("quote", strings.Join(quotes, ","))
 	}
-	if markets, err = self.getMarketsEx(true, sandbox, nil, quotes); err != nil {
+	if markets, err = self.getMarketsEx(ctx, true, sandbox, nil, quotes); err != nil {
 		return err
 	}
 	for _, market := range markets {
@@ -619,7 +724,10 @@ func (self *Binance) Sell(
 		return err
 	}
 
-	for {
+	for !shutdown.Stopping() {
+		if !breaker.Get(self.Name).Wait() {
+			break
+		}
 		// read the dynamic settings
 		var (
 			level  int64 = notify.LEVEL_DEFAULT
@@ -635,39 +743,69 @@ func (self *Binance) Sell(
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listen to the filled orders, look for newly filled orders, automatically place new sell orders.
-		if filled, err = self.sell(client, strategy, quotes, mult, stop, hold, earn, service, twitter, level, filled, sandbox, debug); err != nil {
+		if filled, err = self.sell(ctx, client, strategy, quotes, mult, stop, hold, earn, service, values, twitter, level, filled, sandbox, debug); err != nil {
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listen to the open orders, send a notification on newly opened orders.
 		if open, err = self.listen(client, service, level, open); err != nil {
 			logger.Error(self.Name, err, level, service)
 		}
+		breaker.Get(self.Name).Done(err, service, level)
 	}
+
+	return nil
 }
 
 func (self *Binance) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return nil, nil, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
+
+	if err = opts.Validate(kind); err != nil {
+		return nil, nil, err
+	}
+
+	timeInForce := exchange.TimeInForceTypeGTC
+	switch opts.GetTimeInForce() {
+	case model.IOC:
+		timeInForce = exchange.TimeInForceTypeIOC
+	case model.FOK:
+		timeInForce = exchange.TimeInForceTypeFOK
+	case model.GTT:
+		return nil, nil, orderNotSupported(self.Name, "GTT")
+	}
 
 	service := binanceClient.NewCreateOrderService().
 		Symbol(market).
 		Quantity(size).
 		NewClientOrderID(self.newClientOrderID(metadata))
 
-	if kind == model.MARKET {
+	switch kind {
+	case model.MARKET:
 		service.Type(exchange.OrderTypeMarket)
-	} else {
-		service.Type(exchange.OrderTypeLimit).TimeInForce(exchange.TimeInForceTypeGTC).Price(price)
+	case model.STOP_LIMIT:
+		if opts.IsPostOnly() {
+			return nil, nil, orderNotSupported(self.Name, "post-only stop-limit")
+		}
+		service.Type(exchange.OrderTypeStopLossLimit).TimeInForce(timeInForce).Price(price).StopPrice(opts.StopPrice)
+	default:
+		if opts.IsPostOnly() {
+			service.Type(exchange.OrderTypeLimitMaker).Price(price)
+		} else {
+			service.Type(exchange.OrderTypeLimit).TimeInForce(timeInForce).Price(price)
+		}
 	}
 
 	if side == model.BUY {
@@ -677,7 +815,7 @@ func (self *Binance) Order(
 	}
 
 	var order *exchange.CreateOrderResponse
-	if order, err = service.Do(context.Background()); err != nil {
+	if order, err = service.Do(ctx); err != nil {
 		return nil, nil, errors.Wrap(err, 1)
 	}
 
@@ -689,13 +827,14 @@ func (self *Binance) Order(
 	return []byte(order.ClientOrderID), out, nil
 }
 
-func (self *Binance) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *Binance) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	var err error
 
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
 
 	service := binanceClient.NewCreateOrderService().
 		Symbol(market).
@@ -708,7 +847,7 @@ func (self *Binance) StopLoss(client interface{}, market string, size float64, p
 		service.Type(exchange.OrderTypeStopLoss)
 	} else {
 		var prec int
-		if prec, err = self.GetPricePrec(client, market); err != nil {
+		if prec, err = self.GetPricePrec(ctx, client, market); err != nil {
 			return nil, err
 		}
 		limit := price
@@ -722,19 +861,19 @@ func (self *Binance) StopLoss(client interface{}, market string, size float64, p
 	}
 
 	var order *exchange.CreateOrderResponse
-	if order, err = service.Do(context.Background()); err != nil {
+	if order, err = service.Do(ctx); err != nil {
 		// --- BEGIN --- svanas 2019-02-07 ------------------------------------
 		_, ok := isBinanceError(err)
 		if ok {
 			logger.Warn(err)
 			// -1013 stop loss orders are not supported for this symbol
 			if kind != model.LIMIT {
-				return self.StopLoss(client, market, size, price, model.LIMIT, metadata)
+				return self.StopLoss(ctx, client, market, size, price, model.LIMIT, metadata)
 			}
 			// -2010 order would trigger immediately
 			if strings.Contains(err.Error(), "would trigger immediately") {
 				var prec int
-				if prec, err = self.GetPricePrec(client, market); err == nil {
+				if prec, err = self.GetPricePrec(ctx, client, market); err == nil {
 					lower := price
 					for {
 						lower = lower * 0.99
@@ -742,7 +881,7 @@ func (self *Binance) StopLoss(client interface{}, market string, size float64, p
 							break
 						}
 					}
-					return self.StopLoss(client, market, size, precision.Round(lower, prec), kind, metadata)
+					return self.StopLoss(ctx, client, market, size, precision.Round(lower, prec), kind, metadata)
 				}
 			}
 		}
@@ -758,11 +897,12 @@ func (self *Binance) StopLoss(client interface{}, market string, size float64, p
 	return out, nil
 }
 
-func (self *Binance) OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *Binance) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
 
 	clientOrderId1 := self.newClientOrderID(metadata)
 	clientOrderId2 := self.newClientOrderID(metadata)
@@ -784,13 +924,13 @@ func (self *Binance) OCO(client interface{}, market string, size float64, price,
 		err  error
 		resp *exchange.CreateOCOResponse
 	)
-	if resp, err = svc.Do(context.Background()); err != nil {
+	if resp, err = svc.Do(ctx); err != nil {
 		_, ok := isBinanceError(err)
 		if ok {
 			// -1013 Stop loss orders are not supported for this symbol
 			if strings.Contains(err.Error(), "loss orders are not supported") {
 				var prec int
-				if prec, err = self.GetPricePrec(client, market); err != nil {
+				if prec, err = self.GetPricePrec(ctx, client, market); err != nil {
 					return nil, err
 				}
 				lower := stop
@@ -801,7 +941,7 @@ func (self *Binance) OCO(client interface{}, market string, size float64, price,
 					}
 				}
 				svc.StopLimitPrice(precision.Round(lower, prec)).StopLimitTimeInForce(exchange.TimeInForceTypeGTC)
-				resp, err = svc.Do(context.Background())
+				resp, err = svc.Do(ctx)
 			}
 		}
 		if err != nil {
@@ -817,13 +957,14 @@ func (self *Binance) OCO(client interface{}, market string, size float64, price,
 	return out, nil
 }
 
-func (self *Binance) GetClosed(client interface{}, market string) (model.Orders, error) {
+func (self *Binance) GetClosed(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
 
 	var orders []binance.Order
 	if orders, err = binanceClient.Orders(market); err != nil {
@@ -832,25 +973,37 @@ func (self *Binance) GetClosed(client interface{}, market string) (model.Orders,
 
 	var out model.Orders
 	for _, order := range orders {
-		out = append(out, model.Order{
-			Side:      binanceOrderSide(&order),
-			Market:    order.Symbol,
-			Size:      order.GetSize(),
-			Price:     order.GetPrice(),
-			CreatedAt: time.Unix(order.Time/1000, 0),
-		})
+		wrapped := binanceOrder(&order)
+		wrapped.Bought = binanceOrderBought(&order)
+		out = append(out, wrapped)
 	}
 
 	return out, nil
 }
 
-func (self *Binance) GetOpened(client interface{}, market string) (model.Orders, error) {
+// returns the price we paid for the base asset of a sell order, from the metadata in the client order ID
+func binanceOrderBought(order *binance.Order) float64 {
+	// orders without metadata have a client order ID that looks like x-BROKER-random
+	if binanceOrderSide(order) == model.SELL && strings.Count(order.ClientOrderID, "-") > 2 {
+		metadata, err := binance.ParseClientOrderMetadata(order)
+		if err == nil {
+			out, err := strconv.ParseFloat(strings.Replace(metadata, "_", ".", -1), 64)
+			if err == nil {
+				return out
+			}
+		}
+	}
+	return 0
+}
+
+func (self *Binance) GetOpened(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
 
 	var orders []binance.Order
 	if orders, err = binanceClient.OpenOrdersEx(market); err != nil {
@@ -859,25 +1012,115 @@ func (self *Binance) GetOpened(client interface{}, market string) (model.Orders,
 
 	var out model.Orders
 	for _, order := range orders {
-		out = append(out, model.Order{
-			Side:      binanceOrderSide(&order),
-			Market:    order.Symbol,
-			Size:      order.GetSize(),
-			Price:     order.GetPrice(),
-			CreatedAt: time.Unix(order.Time/1000, 0),
-		})
+		out = append(out, binanceOrder(&order))
 	}
 
 	return out, nil
 }
 
-func (self *Binance) GetBook(client interface{}, market string, side model.BookSide) (interface{}, error) {
+func (self *Binance) GetOrder(ctx context.Context, client model.Client, market string, id string) (*model.Order, error) {
+	binanceClient, ok := client.(*binance.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	binanceClient = binanceClient.WithContext(ctx)
+
+	orderID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("order ID %s is invalid", id)
+	}
+
+	order, err := binanceClient.GetOrder(market, orderID)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	out := binanceOrder(order)
+	out.Bought = binanceOrderBought(order)
+
+	// the fee is in the trades, not in the order
+	if out.Filled > 0 {
+		trades, err := binanceClient.OrderTrades(market, orderID)
+		if err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		for _, trade := range trades {
+			if fee, err := strconv.ParseFloat(trade.Commission, 64); err == nil {
+				out.Fee += fee
+				out.FeeAsset = trade.CommissionAsset
+			}
+		}
+	}
+
+	return &out, nil
+}
+
+func (self *Binance) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (model.Fills, error) {
 	var err error
 
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
+
+	var startTime int64
+	if !since.IsZero() {
+		startTime = since.UnixNano() / int64(time.Millisecond)
+	}
+
+	var trades []*exchange.TradeV3
+	if trades, err = binanceClient.Trades(market, startTime); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	// the trades don't have a client order ID, but their orders do
+	var orders []binance.Order
+	if orders, err = binanceClient.AllOrders(market); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	clientOrderID := make(map[int64]string)
+	for _, order := range orders {
+		clientOrderID[order.OrderID] = order.ClientOrderID
+	}
+
+	var out model.Fills
+	for _, trade := range trades {
+		fill := model.Fill{
+			ID:            strconv.FormatInt(trade.ID, 10),
+			OrderID:       strconv.FormatInt(trade.OrderID, 10),
+			ClientOrderID: clientOrderID[trade.OrderID],
+			Side:          model.SELL,
+			Market:        trade.Symbol,
+			FeeAsset:      trade.CommissionAsset,
+			CreatedAt:     time.Unix(0, trade.Time*int64(time.Millisecond)),
+		}
+		if trade.IsBuyer {
+			fill.Side = model.BUY
+		}
+		if fill.Price, err = strconv.ParseFloat(trade.Price, 64); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		if fill.Size, err = strconv.ParseFloat(trade.Quantity, 64); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		if fill.Fee, err = strconv.ParseFloat(trade.Commission, 64); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		out = append(out, fill)
+	}
+
+	return out, nil
+}
+
+func (self *Binance) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (interface{}, error) {
+	var err error
+
+	binanceClient, ok := client.(*binance.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	binanceClient = binanceClient.WithContext(ctx)
 
 	var book *exchange.DepthResponse
 	if book, err = binanceClient.Depth(market, 1000); err != nil {
@@ -894,13 +1137,13 @@ func (self *Binance) GetBook(client interface{}, market string, side model.BookS
 	return out, nil
 }
 
-func (self *Binance) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *Binance) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]binance.BookEntry)
 	if !ok {
 		return nil, errors.New("invalid argument: book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -931,13 +1174,14 @@ func (self *Binance) Aggregate(client, book interface{}, market string, agg floa
 	return out, nil
 }
 
-func (self *Binance) GetTicker(client interface{}, market string) (float64, error) {
+func (self *Binance) GetTicker(ctx context.Context, client model.Client, market string) (float64, error) {
 	var err error
 
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
 
 	var ticker *exchange.PriceChangeStats
 	if ticker, err = binanceClient.Ticker(market); err != nil {
@@ -952,11 +1196,62 @@ func (self *Binance) GetTicker(client interface{}, market string) (float64, erro
 	return out, nil
 }
 
-func (self *Binance) Get24h(client interface{}, market string) (*model.Stats, error) {
+func (self *Binance) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
+
+	var period string
+	switch interval {
+	case model.CANDLE_15M:
+		period = "15m"
+	case model.CANDLE_1H:
+		period = "1h"
+	case model.CANDLE_4H:
+		period = "4h"
+	case model.CANDLE_1D:
+		period = "1d"
+	default:
+		return nil, errors.Errorf("interval %v is not supported", interval)
+	}
+
+	klines, err := binanceClient.Klines(market, period)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	var out model.Candles
+	for _, kline := range klines {
+		candle := model.Candle{Time: time.Unix(kline.OpenTime/1000, 0)}
+		if candle.Open, err = strconv.ParseFloat(kline.Open, 64); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		if candle.High, err = strconv.ParseFloat(kline.High, 64); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		if candle.Low, err = strconv.ParseFloat(kline.Low, 64); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		if candle.Close, err = strconv.ParseFloat(kline.Close, 64); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		if candle.Volume, err = strconv.ParseFloat(kline.Volume, 64); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		out = append(out, candle)
+	}
+
+	return out, nil
+}
+
+func (self *Binance) Get24h(ctx context.Context, client model.Client, market string) (*model.Stats, error) {
+	binanceClient, ok := client.(*binance.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	binanceClient = binanceClient.WithContext(ctx)
 
 	stats, err := binanceClient.Ticker(market)
 	if err != nil {
@@ -1001,11 +1296,55 @@ func (self *Binance) Get24h(client interface{}, market string) (*model.Stats, er
 	}, nil
 }
 
-func (self *Binance) GetPricePrec(client interface{}, market string) (int, error) {
+func (self *Binance) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	binanceClient, ok := client.(*binance.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	binanceClient = binanceClient.WithContext(ctx)
+	account, err := binanceClient.Account()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	// commission rates are in basis points, for example: 10 is 0.1%
+	return &model.Fees{
+		Maker: float64(account.MakerCommission) / 10000,
+		Taker: float64(account.TakerCommission) / 10000,
+	}, nil
+}
+
+func (self *Binance) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	binanceClient, ok := client.(*binance.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	binanceClient = binanceClient.WithContext(ctx)
+	account, err := binanceClient.Account()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Balances
+	for _, balance := range account.Balances {
+		free, _ := strconv.ParseFloat(balance.Free, 64)
+		locked, _ := strconv.ParseFloat(balance.Locked, 64)
+		if free+locked > 0 {
+			out = append(out, model.Balance{
+				Asset:     balance.Asset,
+				Total:     free + locked,
+				Available: free,
+				Locked:    locked,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (self *Binance) GetPricePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
 	precs, err := binance.GetPrecs(binanceClient, true)
 	if err != nil {
 		return 0, errors.Wrap(err, 1)
@@ -1017,11 +1356,12 @@ func (self *Binance) GetPricePrec(client interface{}, market string) (int, error
 	return 8, nil
 }
 
-func (self *Binance) GetSizePrec(client interface{}, market string) (int, error) {
+func (self *Binance) GetSizePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
 	precs, err := binance.GetPrecs(binanceClient, true)
 	if err != nil {
 		return 0, errors.Wrap(err, 1)
@@ -1033,14 +1373,14 @@ func (self *Binance) GetSizePrec(client interface{}, market string) (int, error)
 	return 0, nil
 }
 
-func (self *Binance) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+func (self *Binance) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
 	if hold {
 		if base == "BNB" {
 			return 0
 		}
 	}
-	return model.GetSizeMax(hold, earn, def, mult, func() int {
-		prec, err := self.GetSizePrec(client, self.FormatMarket(base, quote))
+	return model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, self.FormatMarket(base, quote)), func() int {
+		prec, err := self.GetSizePrec(ctx, client, self.FormatMarket(base, quote))
 		if err != nil {
 			return 0
 		}
@@ -1048,13 +1388,14 @@ func (self *Binance) GetMaxSize(client interface{}, base, quote string, hold, ea
 	})
 }
 
-func (self *Binance) Cancel(client interface{}, market string, side model.OrderSide) error {
+func (self *Binance) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
 	var err error
 
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
 
 	var orders []binance.Order
 	if orders, err = binanceClient.OpenOrdersEx(market); err != nil {
@@ -1076,13 +1417,43 @@ func (self *Binance) Cancel(client interface{}, market string, side model.OrderS
 	return nil
 }
 
-func (self *Binance) Buy(client interface{}, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
+func (self *Binance) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	binanceClient, ok := client.(*binance.Client)
+	if !ok {
+		return errors.New("invalid argument: client")
+	}
+	binanceClient = binanceClient.WithContext(ctx)
+
+	orderID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return errors.Errorf("order ID %s is invalid", id)
+	}
+
+	order, err := binanceClient.GetOrder(market, orderID)
+	if err != nil {
+		return errors.Wrap(err, 1)
+	}
+
+	if err = binanceClient.CancelOrder(market, orderID); err != nil {
+		return errors.Wrap(err, 1)
+	}
+
+	tmp := session.GetTempFileName(order.ClientOrderID, ".binance")
+	if _, err = os.Stat(tmp); err == nil {
+		os.Remove(tmp)
+	}
+
+	return nil
+}
+
+func (self *Binance) Buy(ctx context.Context, client model.Client, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	var err error
 
 	binanceClient, ok := client.(*binance.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	binanceClient = binanceClient.WithContext(ctx)
 
 	// step #1: delete the buy order(s) that are open in your book
 	if cancel {
@@ -1116,7 +1487,7 @@ func (self *Binance) Buy(client interface{}, cancel bool, market string, calls m
 				limit float64 = call.Price
 			)
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
 			// --- BEGIN --- svanas 2018-11-30 --- <APIError> code=-1013, msg=Filter failure: MIN_NOTIONAL.
 			if min, err = self.getMinTrade(binanceClient, market, true); err != nil {
@@ -1124,25 +1495,25 @@ func (self *Binance) Buy(client interface{}, cancel bool, market string, calls m
 			}
 			if min > 0 {
 				if limit == 0 {
-					if limit, err = self.GetTicker(client, market); err != nil {
+					if limit, err = self.GetTicker(ctx, client, market); err != nil {
 						return err
 					}
 				}
 				if (qty * limit) < min {
 					var prec int
-					if prec, err = self.GetSizePrec(client, market); err != nil {
+					if prec, err = self.GetSizePrec(ctx, client, market); err != nil {
 						return err
 					}
 					qty = precision.Ceil((min / limit), prec)
 				}
 			}
 			// ---- END ---- svanas 2018-11-30 ------------------------------------------------------------
-			oid, _, err = self.Order(client,
+			oid, _, err = self.Order(ctx, client,
 				model.BUY,
 				market,
 				qty,
 				limit,
-				kind, "",
+				kind, nil, "",
 			)
 			if err != nil {
 				return err
@@ -1150,7 +1521,7 @@ func (self *Binance) Buy(client interface{}, cancel bool, market string, calls m
 			if oid != nil {
 				if kind == model.MARKET {
 					var ticker float64
-					if ticker, err = self.GetTicker(client, market); err == nil {
+					if ticker, err = self.GetTicker(ctx, client, market); err == nil {
 						err = model.Call2File(&model.Call{
 							Buy: &model.Buy{
 								Market: call.Market,
@@ -1180,7 +1551,7 @@ func (self *Binance) IsLeveragedToken(name string) bool {
 		(len(name) > 4 && strings.HasSuffix(strings.ToUpper(name), "BULL"))
 }
 
-func (self *Binance) HasAlgoOrder(client interface{}, market string) (bool, error) {
+func (self *Binance) HasAlgoOrder(ctx context.Context, client model.Client, market string) (bool, error) {
 	return false, nil
 }
 
@@ -2,6 +2,7 @@
 package exchanges
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -12,6 +13,7 @@ import (
 	filemutex "github.com/alexflint/go-filemutex"
 	"github.com/svanas/nefertiti/aggregation"
 	exchange "github.com/svanas/nefertiti/bitstamp"
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/logger"
@@ -21,6 +23,7 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
+	"github.com/svanas/nefertiti/shutdown"
 )
 
 var (
@@ -76,6 +79,15 @@ func init() {
 	}
 }
 
+// bitstampCodes maps the Bitstamp error messages to our error classes
+var bitstampCodes = errors.Codes{
+	{Code: "Check your account balance", Class: errors.INSUFFICIENT_FUNDS},
+	{Code: "Minimum order size", Class: errors.INVALID_SIZE},
+	{Code: "Price is more than", Class: errors.INVALID_PRICE},
+	{Code: "API key not found", Class: errors.AUTH},
+	{Code: "Invalid signature", Class: errors.AUTH},
+}
+
 type Bitstamp struct {
 	*model.ExchangeInfo
 }
@@ -84,7 +96,22 @@ func (self *Bitstamp) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
-func (self *Bitstamp) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *Bitstamp) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        false,
+		MarketOrders:     false,
+		MarketBuyByQuote: false,
+		PostOnly:         false,
+		StopLoss:         false,
+		StopLossStrategy: false,
+		OCO:              false,
+		Trailing:         false,
+		BotOrders:        false,
+	}
+}
+
+func (self *Bitstamp) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	if permission != model.PRIVATE {
 		return exchange.New("", ""), nil
 	}
@@ -120,10 +147,10 @@ func (self *Bitstamp) getMarket(client *exchange.Client, name string) (*exchange
 	}
 }
 
-func (self *Bitstamp) GetMarkets(cached, sandbox bool, blacklist []string) ([]model.Market, error) {
+func (self *Bitstamp) GetMarkets(ctx context.Context, cached, sandbox bool, blacklist []string) ([]model.Market, error) {
 	var out []model.Market
 
-	markets, err := exchange.GetMarkets(exchange.New("", ""), cached)
+	markets, err := exchange.GetMarkets(exchange.New("", "").WithContext(ctx), cached)
 
 	if err != nil {
 		return nil, err
@@ -219,10 +246,12 @@ func (self *Bitstamp) listen(
 
 // listens to the transaction history, look for newly filled orders, automatically place new LIMIT SELL orders.
 func (self *Bitstamp) sell(
+	ctx context.Context,
 	client *exchange.Client,
 	mult multiplier.Mult,
 	hold, earn model.Markets,
 	service model.Notify,
+	values *fillValuer,
 	twitter *notify.TwitterKeys,
 	level int64,
 	old exchange.Transactions,
@@ -233,7 +262,7 @@ func (self *Bitstamp) sell(
 		markets []model.Market
 	)
 
-	if markets, err = self.GetMarkets(false, sandbox, nil); err != nil {
+	if markets, err = self.GetMarkets(ctx, false, sandbox, nil); err != nil {
 		return old, err
 	}
 
@@ -314,7 +343,7 @@ func (self *Bitstamp) sell(
 					logger.Error(self.Name, err, level, service)
 				} else {
 					if service != nil {
-						if err = service.SendMessage(order, fmt.Sprintf("Bitstamp - Done %s (Reason: Filled %f qty)", strings.Title(side), order.Amount(client)), model.ALWAYS); err != nil {
+						if err = service.SendMessage(order, fmt.Sprintf("Bitstamp - Done %s (Reason: Filled %f qty)%s", strings.Title(side), order.Amount(client), values.value(ctx, order.Market(client), order.Amount(client), order.Price(client))), model.ALWAYS); err != nil {
 							log.Printf("[ERROR] %v", err)
 						}
 					}
@@ -345,7 +374,7 @@ func (self *Bitstamp) sell(
 			}
 
 			var sp int
-			if sp, err = self.GetSizePrec(client, orders[i].Market(client)); err != nil {
+			if sp, err = self.GetSizePrec(ctx, client, orders[i].Market(client)); err != nil {
 				return old, err
 			} else {
 				qty = precision.Floor(qty, sp)
@@ -358,16 +387,17 @@ func (self *Bitstamp) sell(
 			)
 			base, quote, err = model.ParseMarket(markets, orders[i].Market(client))
 			if err == nil {
-				qty = self.GetMaxSize(client, base, quote, hold.HasMarket(orders[i].Market(client)), earn.HasMarket(orders[i].Market(client)), qty, mult)
+				fees := model.GetFees(ctx, self, client, orders[i].Market(client))
+				qty = self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(orders[i].Market(client)), earn.HasMarket(orders[i].Market(client)), qty, fees.Mult(mult))
 				if qty > 0 {
 					var pp int
-					if pp, err = self.GetPricePrec(client, orders[i].Market(client)); err == nil {
+					if pp, err = self.GetPricePrec(ctx, client, orders[i].Market(client)); err == nil {
 						attempts := 0
 						for {
 							_, err = client.SellLimitOrder(
 								orders[i].Market(client),
 								qty,
-								pricing.Multiply(orders[i].Price(client), mult, pp),
+								pricing.Multiply(orders[i].Price(client), fees.Mult(mult), pp),
 							)
 							if err != nil && strings.Contains(err.Error(), "Order could not be placed") {
 								attempts++
@@ -397,6 +427,7 @@ func (self *Bitstamp) sell(
 }
 
 func (self *Bitstamp) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -429,7 +460,8 @@ func (self *Bitstamp) Sell(
 		}
 	}
 
-	client := exchange.New(apiKey, apiSecret)
+	client := exchange.New(apiKey, apiSecret).WithContext(ctx)
+	values := newFillValuer(self, client)
 
 	// get my open orders
 	var open []exchange.Order
@@ -461,7 +493,10 @@ func (self *Bitstamp) Sell(
 	reboughtAt := time.Now()
 	const rebuyAfterDays = 14
 
-	for {
+	for !shutdown.Stopping() {
+		if !breaker.Get(self.Name).Wait() {
+			break
+		}
 		// read the dynamic settings
 		var (
 			level int64 = notify.LEVEL_DEFAULT
@@ -473,7 +508,7 @@ func (self *Bitstamp) Sell(
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listens to the transaction history, look for newly filled orders, automatically place new LIMIT SELL orders.
-		if transactions, err = self.sell(client, mult, hold, earn, service, twitter, level, transactions, sandbox); err != nil {
+		if transactions, err = self.sell(ctx, client, mult, hold, earn, service, values, twitter, level, transactions, sandbox); err != nil {
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listens to the open orders, look for cancelled orders, send a notification.
@@ -504,7 +539,7 @@ func (self *Bitstamp) Sell(
 					if !youngest.IsZero() && time.Since(youngest).Hours() > 24*rebuyAfterDays {
 						// did we recently sell an "aggressive" order on this market? then prevent us from buying this pump.
 						var closed model.Orders
-						if closed, err = self.GetClosed(client, market.Name); err != nil {
+						if closed, err = self.GetClosed(ctx, client, market.Name); err != nil {
 							logger.Error(self.Name, err, level, service)
 						} else {
 							if time.Since(closed.Youngest(model.SELL, time.Now())).Hours() < 24*rebuyAfterDays {
@@ -515,11 +550,11 @@ func (self *Bitstamp) Sell(
 									market.Name, youngest.Format(time.RFC1123), rebuyAfterDays,
 								), level, service)
 								var ticker float64
-								if ticker, err = self.GetTicker(client, market.Name); err != nil {
+								if ticker, err = self.GetTicker(ctx, client, market.Name); err != nil {
 									logger.Error(self.Name, err, level, service)
 								} else {
 									var precSize int
-									if precSize, err = self.GetSizePrec(client, market.Name); err != nil {
+									if precSize, err = self.GetSizePrec(ctx, client, market.Name); err != nil {
 										logger.Error(self.Name, err, level, service)
 									} else {
 										for {
@@ -552,22 +587,32 @@ func (self *Bitstamp) Sell(
 			}
 			reboughtAt = time.Now()
 		}
+		breaker.Get(self.Name).Done(err, service, level)
 	}
+
+	return nil
 }
 
 func (self *Bitstamp) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, nil, errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
+
+	if err = plainOrder(self.Name, kind, opts); err != nil {
+		return nil, nil, err
+	}
 
 	var order *exchange.Order
 	if side == model.BUY {
@@ -588,21 +633,22 @@ func (self *Bitstamp) Order(
 	return []byte(order.Id), out, nil
 }
 
-func (self *Bitstamp) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *Bitstamp) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *Bitstamp) OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *Bitstamp) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *Bitstamp) GetClosed(client interface{}, market string) (model.Orders, error) {
+func (self *Bitstamp) GetClosed(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
 
 	var transactions []exchange.Transaction
 	if transactions, err = bitstamp.GetUserTransactions(market); err != nil {
@@ -612,12 +658,20 @@ func (self *Bitstamp) GetClosed(client interface{}, market string) (model.Orders
 	var out model.Orders
 	for _, transaction := range transactions {
 		if transaction.OrderId() != "" {
+			// every transaction is a fill
 			side, _ := transaction.Side(bitstamp)
 			out = append(out, model.Order{
+				ID:        transaction.OrderId(),
 				Side:      model.NewOrderSide(side),
+				Type:      model.LIMIT,
+				Status:    model.FILLED,
 				Market:    transaction.Market(bitstamp),
 				Size:      transaction.Amount(bitstamp),
 				Price:     transaction.Price(bitstamp),
+				Filled:    transaction.Amount(bitstamp),
+				AvgPrice:  transaction.Price(bitstamp),
+				Fee:       transaction.Fee(),
+				FeeAsset:  transaction.Quote(bitstamp),
 				CreatedAt: transaction.DateTime(),
 			})
 		}
@@ -626,13 +680,14 @@ func (self *Bitstamp) GetClosed(client interface{}, market string) (model.Orders
 	return out, nil
 }
 
-func (self *Bitstamp) GetOpened(client interface{}, market string) (model.Orders, error) {
+func (self *Bitstamp) GetOpened(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
 
 	var orders []exchange.Order
 	if orders, err = bitstamp.GetOpenOrdersEx(market); err != nil {
@@ -642,23 +697,78 @@ func (self *Bitstamp) GetOpened(client interface{}, market string) (model.Orders
 	var out model.Orders
 	for _, order := range orders {
 		out = append(out, model.Order{
-			Side:   model.NewOrderSide(order.Side()),
-			Market: market,
-			Size:   order.Amount,
-			Price:  order.Price,
+			ID:        order.Id,
+			Side:      model.NewOrderSide(order.Side()),
+			Type:      model.LIMIT,
+			Status:    model.OPEN,
+			Market:    market,
+			Size:      order.Amount,
+			Price:     order.Price,
+			CreatedAt: order.GetDateTimeEx(),
 		})
 	}
 
 	return out, nil
 }
 
-func (self *Bitstamp) GetBook(client interface{}, market string, side model.BookSide) (interface{}, error) {
+func (self *Bitstamp) GetOrder(ctx context.Context, client model.Client, market string, id string) (*model.Order, error) {
+	bitstamp, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	bitstamp = bitstamp.WithContext(ctx)
+
+	order, err := bitstamp.OrderStatus(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filled, price, fee := order.Filled()
+
+	out := &model.Order{
+		ID:            order.Id.String(),
+		ClientOrderID: order.ClientOrderId,
+		Side:          model.NewOrderSide(order.Side()),
+		Type:          model.LIMIT,
+		Market:        market,
+		Size:          filled + order.AmountRemaining,
+		Price:         price,
+		Filled:        filled,
+		AvgPrice:      price,
+		Fee:           fee,
+		FeeAsset:      order.Quote(),
+		CreatedAt:     order.GetDateTime(),
+	}
+
+	switch order.Status {
+	case exchange.ORDER_STATUS_FINISHED:
+		out.Status = model.FILLED
+	case exchange.ORDER_STATUS_CANCELED:
+		out.Status = model.CANCELLED
+	default:
+		out.Status = model.GetOrderStatus(out.Size, out.Filled, false)
+	}
+
+	return out, nil
+}
+
+func (self *Bitstamp) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (model.Fills, error) {
+	// our closed orders are our user transactions, and every user transaction is a fill.
+	closed, err := self.GetClosed(ctx, client, market)
+	if err != nil {
+		return nil, err
+	}
+	return model.OrdersToFills(closed, since), nil
+}
+
+func (self *Bitstamp) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (interface{}, error) {
 	var err error
 
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
 
 	var book *exchange.OrderBook
 	if book, err = bitstamp.OrderBook(market); err != nil {
@@ -675,13 +785,13 @@ func (self *Bitstamp) GetBook(client interface{}, market string, side model.Book
 	return out, nil
 }
 
-func (self *Bitstamp) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *Bitstamp) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]exchange.BookEntry)
 	if !ok {
 		return nil, errors.New("invalid argument: book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -706,11 +816,12 @@ func (self *Bitstamp) Aggregate(client, book interface{}, market string, agg flo
 	return out, nil
 }
 
-func (self *Bitstamp) GetTicker(client interface{}, market string) (float64, error) {
+func (self *Bitstamp) GetTicker(ctx context.Context, client model.Client, market string) (float64, error) {
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
 
 	ticker, err := bitstamp.Ticker(market)
 	if err != nil {
@@ -720,11 +831,16 @@ func (self *Bitstamp) GetTicker(client interface{}, market string) (float64, err
 	return ticker.Last, nil
 }
 
-func (self *Bitstamp) Get24h(client interface{}, market string) (*model.Stats, error) {
+func (self *Bitstamp) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (self *Bitstamp) Get24h(ctx context.Context, client model.Client, market string) (*model.Stats, error) {
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
 
 	ticker, err := bitstamp.Ticker(market)
 	if err != nil {
@@ -739,11 +855,40 @@ func (self *Bitstamp) Get24h(client interface{}, market string) (*model.Stats, e
 	}, nil
 }
 
-func (self *Bitstamp) GetPricePrec(client interface{}, marketName string) (int, error) {
+func (self *Bitstamp) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	return lowestTier(0.005, 0.005), nil
+}
+
+func (self *Bitstamp) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	bitstamp, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	bitstamp = bitstamp.WithContext(ctx)
+	balances, err := bitstamp.Balances()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Balances
+	for _, balance := range balances {
+		if balance.Total > 0 {
+			out = append(out, model.Balance{
+				Asset:     balance.Currency,
+				Total:     balance.Total,
+				Available: balance.Available,
+				Locked:    balance.Reserved,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (self *Bitstamp) GetPricePrec(ctx context.Context, client model.Client, marketName string) (int, error) {
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
 	market, err := self.getMarket(bitstamp, marketName)
 	if err != nil {
 		return 0, err
@@ -751,11 +896,12 @@ func (self *Bitstamp) GetPricePrec(client interface{}, marketName string) (int,
 	return market.PricePrec, nil
 }
 
-func (self *Bitstamp) GetSizePrec(client interface{}, marketName string) (int, error) {
+func (self *Bitstamp) GetSizePrec(ctx context.Context, client model.Client, marketName string) (int, error) {
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
 	market, err := self.getMarket(bitstamp, marketName)
 	if err != nil {
 		return 0, err
@@ -763,21 +909,21 @@ func (self *Bitstamp) GetSizePrec(client interface{}, marketName string) (int, e
 	return market.SizePrec, nil
 }
 
-func (self *Bitstamp) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+func (self *Bitstamp) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
 	market := self.FormatMarket(base, quote)
 
 	fn := func() int {
-		prec, err := self.GetSizePrec(client, market)
+		prec, err := self.GetSizePrec(ctx, client, market)
 		if err != nil {
 			return 0
 		}
 		return prec
 	}
 
-	out := model.GetSizeMax(hold, earn, def, mult, fn)
+	out := model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, market), fn)
 
 	if hold {
-		ticker, err := self.GetTicker(client, market)
+		ticker, err := self.GetTicker(ctx, client, market)
 		if err == nil {
 			bitstamp, ok := client.(*exchange.Client)
 			if ok {
@@ -786,7 +932,7 @@ func (self *Bitstamp) GetMaxSize(client interface{}, base, quote string, hold, e
 					if (out * ticker) >= min {
 						// we are good
 					} else {
-						stats, err := self.Get24h(client, market)
+						stats, err := self.Get24h(ctx, client, market)
 						if err == nil {
 							qty := (min / stats.Low)
 							if qty > out {
@@ -802,13 +948,14 @@ func (self *Bitstamp) GetMaxSize(client interface{}, base, quote string, hold, e
 	return out
 }
 
-func (self *Bitstamp) Cancel(client interface{}, market string, side model.OrderSide) error {
+func (self *Bitstamp) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
 	var err error
 
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
 
 	var orders []exchange.Order
 	if orders, err = bitstamp.GetOpenOrdersEx(market); err != nil {
@@ -826,13 +973,23 @@ func (self *Bitstamp) Cancel(client interface{}, market string, side model.Order
 	return nil
 }
 
-func (self *Bitstamp) Buy(client interface{}, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
+func (self *Bitstamp) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	bitstamp, ok := client.(*exchange.Client)
+	if !ok {
+		return errors.New("invalid argument: client")
+	}
+	bitstamp = bitstamp.WithContext(ctx)
+	return bitstamp.CancelOrder(id)
+}
+
+func (self *Bitstamp) Buy(ctx context.Context, client model.Client, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	var err error
 
 	bitstamp, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	bitstamp = bitstamp.WithContext(ctx)
 
 	// step #1: delete the buy order(s) that are open in your book
 	if cancel {
@@ -865,7 +1022,7 @@ func (self *Bitstamp) Buy(client interface{}, cancel bool, market string, calls
 				limit float64 = call.Price
 			)
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
 			// --- BEGIN --- svanas 2020-01-06 --- Minimum order size is 25.0 EUR ---
 			if min, err = exchange.GetMinimumOrder(bitstamp, market); err != nil {
@@ -873,13 +1030,13 @@ func (self *Bitstamp) Buy(client interface{}, cancel bool, market string, calls
 			}
 			if min > 0 {
 				if limit == 0 {
-					if limit, err = self.GetTicker(client, market); err != nil {
+					if limit, err = self.GetTicker(ctx, client, market); err != nil {
 						return err
 					}
 				}
 				if (qty * limit) < min {
 					var prec int
-					if prec, err = self.GetSizePrec(client, market); err != nil {
+					if prec, err = self.GetSizePrec(ctx, client, market); err != nil {
 						return err
 					}
 					qty = precision.Ceil((min / limit), prec)
@@ -913,7 +1070,7 @@ func (self *Bitstamp) IsLeveragedToken(name string) bool {
 	return false
 }
 
-func (self *Bitstamp) HasAlgoOrder(client interface{}, market string) (bool, error) {
+func (self *Bitstamp) HasAlgoOrder(ctx context.Context, client model.Client, market string) (bool, error) {
 	return false, nil
 }
 
@@ -2,6 +2,7 @@
 package exchanges
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -13,6 +14,7 @@ import (
 	"github.com/alexflint/go-filemutex"
 	"github.com/svanas/nefertiti/aggregation"
 	exchange "github.com/svanas/nefertiti/bittrex"
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/logger"
@@ -22,6 +24,7 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
+	"github.com/svanas/nefertiti/shutdown"
 )
 
 const (
@@ -228,6 +231,17 @@ func bittrexCancelOrder(client *exchange.Client, order *exchange.Order) (float64
 
 // ----------------------------------------------------------------------------
 
+// bittrexCodes maps the Bittrex error codes to our error classes
+var bittrexCodes = errors.Codes{
+	{Code: "INSUFFICIENT_FUNDS", Class: errors.INSUFFICIENT_FUNDS},
+	{Code: "MIN_TRADE_REQUIREMENT_NOT_MET", Class: errors.INVALID_SIZE},
+	{Code: "DUST_TRADE_DISALLOWED_MIN_VALUE", Class: errors.INVALID_SIZE},
+	{Code: "ORDERBOOK_DEPTH", Class: errors.INVALID_PRICE},
+	{Code: "MARKET_OFFLINE", Class: errors.MARKET_CLOSED},
+	{Code: "APIKEY_INVALID", Class: errors.AUTH},
+	{Code: "INVALID_SIGNATURE", Class: errors.AUTH},
+}
+
 type Bittrex struct {
 	*model.ExchangeInfo
 	markets []exchange.Market
@@ -237,7 +251,22 @@ func (self *Bittrex) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
-func (self *Bittrex) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *Bittrex) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        false,
+		MarketOrders:     true,
+		MarketBuyByQuote: false,
+		PostOnly:         true,
+		StopLoss:         false,
+		StopLossStrategy: true,
+		OCO:              true,
+		Trailing:         false,
+		BotOrders:        false,
+	}
+}
+
+func (self *Bittrex) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	if permission != model.PRIVATE {
 		return exchange.New("", "", bittrexAppID), nil
 	}
@@ -287,14 +316,14 @@ func (self *Bittrex) minTradeSize(client *exchange.Client, market1 string) (floa
 	return market3.MinTradeSize, nil
 }
 
-func (self *Bittrex) GetMarkets(cached, sandbox bool, ignore []string) ([]model.Market, error) {
+func (self *Bittrex) GetMarkets(ctx context.Context, cached, sandbox bool, ignore []string) ([]model.Market, error) {
 	var (
 		err error
 		out []model.Market
 	)
 
 	if self.markets == nil || !cached {
-		client := exchange.New("", "", bittrexAppID)
+		client := exchange.New("", "", bittrexAppID).WithContext(ctx)
 		if self.markets, err = client.GetMarkets(); err != nil {
 			return nil, errors.Wrap(err, 1)
 		}
@@ -406,11 +435,13 @@ func (self *Bittrex) listen(
 
 // listens to the order history, look for newly filled orders, automatically place new LIMIT SELL orders.
 func (self *Bittrex) sell(
+	ctx context.Context,
 	client *exchange.Client,
 	strategy model.Strategy,
 	mult, stop multiplier.Mult,
 	hold, earn model.Markets,
 	service model.Notify,
+	values *fillValuer,
 	twitter *notify.TwitterKeys,
 	level int64,
 	old exchange.Orders,
@@ -421,7 +452,7 @@ func (self *Bittrex) sell(
 		markets []model.Market
 	)
 
-	if markets, err = self.GetMarkets(true, sandbox, nil); err != nil {
+	if markets, err = self.GetMarkets(ctx, true, sandbox, nil); err != nil {
 		return old, err
 	}
 
@@ -454,6 +485,7 @@ func (self *Bittrex) sell(
 								title = fmt.Sprintf("%s %s", title, multiplier.Format(mult))
 							}
 						}
+						title += values.value(ctx, order.MarketName(), order.QuantityFilled(), order.Price())
 						if err = service.SendMessage(order, title, model.ALWAYS); err != nil {
 							log.Printf("[ERROR] %v", err)
 						}
@@ -473,7 +505,7 @@ func (self *Bittrex) sell(
 								if sold > 0 {
 									bought := sold * (1 + (1 - float64(stop)))
 									if bought > sold {
-										ticker, _ := self.GetTicker(client, order.MarketName())
+										ticker, _ := self.GetTicker(ctx, client, order.MarketName())
 										if ticker > bought {
 											logger.InfoEx(self.Name, fmt.Sprintf("Not rebuying %s because ticker %v is higher than limit %v\n", order.MarketName(), ticker, bought), level, service)
 											return false
@@ -486,15 +518,15 @@ func (self *Bittrex) sell(
 									prec int
 									size float64 = 2.2 * order.QuantityFilled()
 								)
-								if prec, err = self.GetSizePrec(client, order.MarketName()); err != nil {
+								if prec, err = self.GetSizePrec(ctx, client, order.MarketName()); err != nil {
 									return new, err
 								}
 								for {
-									_, _, err = self.Order(client,
+									_, _, err = self.Order(ctx, client,
 										model.BUY,
 										order.MarketName(),
 										precision.Round(size, prec),
-										0, model.MARKET, "",
+										0, model.MARKET, nil, "",
 									)
 									if err == nil {
 										break
@@ -529,7 +561,7 @@ func (self *Bittrex) sell(
 				if side == model.BUY {
 					bought := order.Price()
 					if bought == 0 {
-						if bought, err = self.GetTicker(client, order.MarketName()); err != nil {
+						if bought, err = self.GetTicker(ctx, client, order.MarketName()); err != nil {
 							return new, err
 						}
 					}
@@ -540,19 +572,20 @@ func (self *Bittrex) sell(
 					base, quote, err = model.ParseMarket(markets, order.MarketName())
 					// --- BEGIN --- svanas 2021-05-28 --- do not error on new listings ---
 					if err != nil {
-						if markets, err = self.GetMarkets(false, sandbox, nil); err == nil {
+						if markets, err = self.GetMarkets(ctx, false, sandbox, nil); err == nil {
 							base, quote, err = model.ParseMarket(markets, order.MarketName())
 						}
 					}
 					// ---- END ---- svanas 2021-05-28 ------------------------------------
 					if err == nil {
 						var prec int
-						if prec, err = self.GetPricePrec(client, order.MarketName()); err == nil {
-							qty := self.GetMaxSize(client, base, quote, hold.HasMarket(order.MarketName()), earn.HasMarket(order.MarketName()), order.QuantityFilled(), mult)
+						if prec, err = self.GetPricePrec(ctx, client, order.MarketName()); err == nil {
+							fees := model.GetFees(ctx, self, client, order.MarketName())
+							qty := self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(order.MarketName()), earn.HasMarket(order.MarketName()), order.QuantityFilled(), fees.Mult(mult))
 							if qty > 0 {
-								tgt := pricing.Multiply(bought, mult, prec)
+								tgt := pricing.Multiply(bought, fees.Mult(mult), prec)
 								if strategy == model.STRATEGY_STOP_LOSS {
-									_, err = self.OCO(
+									_, err = self.OCO(ctx,
 										client,
 										order.MarketName(),
 										qty,
@@ -561,12 +594,13 @@ func (self *Bittrex) sell(
 										strconv.FormatFloat(bought, 'f', -1, 64),
 									)
 								} else {
-									_, _, err = self.Order(
+									_, _, err = self.Order(ctx,
 										client, model.SELL,
 										order.MarketName(),
 										qty,
 										tgt,
 										model.LIMIT,
+										nil,
 										strconv.FormatFloat(bought, 'f', -1, 64),
 									)
 								}
@@ -585,6 +619,7 @@ func (self *Bittrex) sell(
 }
 
 func (self *Bittrex) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -617,7 +652,8 @@ func (self *Bittrex) Sell(
 		}
 	}
 
-	client := exchange.New(apiKey, apiSecret, bittrexAppID)
+	client := exchange.New(apiKey, apiSecret, bittrexAppID).WithContext(ctx)
+	values := newFillValuer(self, client)
 
 	// get my order history
 	var history exchange.Orders
@@ -638,7 +674,10 @@ func (self *Bittrex) Sell(
 	reopenedAt := time.Now()
 	const reopenAfterDays = 21
 
-	for {
+	for !shutdown.Stopping() {
+		if !breaker.Get(self.Name).Wait() {
+			break
+		}
 		// read the dynamic settings
 		var (
 			level int64 = notify.LEVEL_DEFAULT
@@ -653,7 +692,7 @@ func (self *Bittrex) Sell(
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listens to the order history, look for newly filled orders, automatically place new LIMIT SELL orders.
-		if history, err = self.sell(client, strategy, mult, stop, hold, earn, service, twitter, level, history, sandbox); err != nil {
+		if history, err = self.sell(ctx, client, strategy, mult, stop, hold, earn, service, values, twitter, level, history, sandbox); err != nil {
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listens to the open orders, look for cancelled orders, send a notification.
@@ -683,9 +722,9 @@ func (self *Bittrex) Sell(
 							var ocoTriggerPrice float64
 							if ocoTriggerPrice, err = bittrexCancelOrder(client, &order); err == nil {
 								if ocoTriggerPrice > 0 {
-									_, err = self.OCO(client, order.MarketName(), order.Quantity, order.Price(), ocoTriggerPrice, "")
+									_, err = self.OCO(ctx, client, order.MarketName(), order.Quantity, order.Price(), ocoTriggerPrice, "")
 								} else {
-									_, _, err = self.Order(client, side, order.MarketName(), order.Quantity, order.Price(), model.LIMIT, "")
+									_, _, err = self.Order(ctx, client, side, order.MarketName(), order.Quantity, order.Price(), model.LIMIT, nil, "")
 								}
 							}
 
@@ -698,40 +737,66 @@ func (self *Bittrex) Sell(
 			}
 			reopenedAt = time.Now()
 		}
+		breaker.Get(self.Name).Done(err, service, level)
 	}
+
+	return nil
 }
 
 func (self *Bittrex) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market1 string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, nil, errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	var market3 string
 	if market3, err = self.convertMarket(market1); err != nil {
 		return nil, nil, err
 	}
 
+	if err = opts.Validate(kind); err != nil {
+		return nil, nil, err
+	}
+	if kind == model.STOP_LIMIT {
+		return nil, nil, orderNotSupported(self.Name, kind.String())
+	}
+
+	timeInForce := exchange.GTC
+	switch opts.GetTimeInForce() {
+	case model.IOC:
+		timeInForce = exchange.IOC
+	case model.FOK:
+		timeInForce = exchange.FOK
+	case model.GTT:
+		return nil, nil, orderNotSupported(self.Name, "GTT")
+	}
+	if opts.IsPostOnly() {
+		timeInForce = exchange.POST_ONLY_GTC
+	}
+
 	var order *exchange.Order
 	if side == model.BUY {
 		if kind == model.MARKET {
 			order, err = bittrex.CreateOrder(market3, exchange.BUY, exchange.MARKET, size, 0, exchange.IOC)
 		} else if kind == model.LIMIT {
-			order, err = bittrex.CreateOrder(market3, exchange.BUY, exchange.LIMIT, size, price, exchange.GTC)
+			order, err = bittrex.CreateOrder(market3, exchange.BUY, exchange.LIMIT, size, price, timeInForce)
 		}
 	} else if side == model.SELL {
 		if kind == model.MARKET {
 			order, err = bittrex.CreateOrder(market3, exchange.SELL, exchange.MARKET, size, 0, exchange.IOC)
 		} else if kind == model.LIMIT {
-			order, err = bittrex.CreateOrder(market3, exchange.SELL, exchange.LIMIT, size, price, exchange.GTC)
+			order, err = bittrex.CreateOrder(market3, exchange.SELL, exchange.LIMIT, size, price, timeInForce)
 		}
 	}
 
@@ -747,17 +812,17 @@ func (self *Bittrex) Order(
 	return []byte(order.Id), out, nil
 }
 
-func (self *Bittrex) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *Bittrex) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *Bittrex) OCO(client interface{}, market1 string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *Bittrex) OCO(ctx context.Context, client model.Client, market1 string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	var (
 		err error
 		id  []byte
 	)
 
-	if id, _, err = self.Order(client, model.SELL, market1, size, price, model.LIMIT, metadata); err != nil {
+	if id, _, err = self.Order(ctx, client, model.SELL, market1, size, price, model.LIMIT, nil, metadata); err != nil {
 		return nil, err
 	}
 
@@ -793,13 +858,41 @@ func (self *Bittrex) OCO(client interface{}, market1 string, size float64, price
 	return out, nil
 }
 
-func (self *Bittrex) GetClosed(client interface{}, market1 string) (model.Orders, error) {
+func bittrexOrder(order *exchange.Order, market1 string, createdAt time.Time) model.Order {
+	out := model.Order{
+		ID:            string(order.Id),
+		ClientOrderID: string(order.ClientOrderId),
+		Side:          bittrexOrderSide(order),
+		Type:          model.LIMIT,
+		Status:        model.GetOrderStatus(order.Quantity, order.QuantityFilled(), order.Status == "CLOSED"),
+		Market:        market1,
+		Size:          order.Quantity,
+		Price:         order.Price(),
+		Filled:        order.QuantityFilled(),
+		Fee:           order.Commission,
+		CreatedAt:     createdAt,
+	}
+	if order.Type() == exchange.MARKET {
+		out.Type = model.MARKET
+	}
+	if out.Filled > 0 {
+		out.AvgPrice = order.Proceeds / out.Filled
+	}
+	// the commission is in the quote currency
+	if symbols := strings.Split(order.MarketSymbol, "-"); len(symbols) > 1 {
+		out.FeeAsset = symbols[1]
+	}
+	return out
+}
+
+func (self *Bittrex) GetClosed(ctx context.Context, client model.Client, market1 string) (model.Orders, error) {
 	var err error
 
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	var market3 string
 	if market3, err = self.convertMarket(market1); err != nil {
@@ -817,25 +910,20 @@ func (self *Bittrex) GetClosed(client interface{}, market1 string) (model.Orders
 		if closedAt, err = time.Parse(exchange.TIME_FORMAT, order.ClosedAt); err != nil {
 			return nil, errors.Wrap(err, 1)
 		}
-		out = append(out, model.Order{
-			Side:      bittrexOrderSide(&order),
-			Market:    market1,
-			Size:      order.Quantity,
-			Price:     order.Price(),
-			CreatedAt: closedAt,
-		})
+		out = append(out, bittrexOrder(&order, market1, closedAt))
 	}
 
 	return out, nil
 }
 
-func (self *Bittrex) GetOpened(client interface{}, market1 string) (model.Orders, error) {
+func (self *Bittrex) GetOpened(ctx context.Context, client model.Client, market1 string) (model.Orders, error) {
 	var err error
 
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	var market3 string
 	if market3, err = self.convertMarket(market1); err != nil {
@@ -853,25 +941,89 @@ func (self *Bittrex) GetOpened(client interface{}, market1 string) (model.Orders
 		if openedAt, err = time.Parse(exchange.TIME_FORMAT, order.CreatedAt); err != nil {
 			return nil, errors.Wrap(err, 1)
 		}
-		out = append(out, model.Order{
-			Side:      bittrexOrderSide(&order),
-			Market:    market1,
-			Size:      order.Quantity,
-			Price:     order.Price(),
-			CreatedAt: openedAt,
+		out = append(out, bittrexOrder(&order, market1, openedAt))
+	}
+
+	return out, nil
+}
+
+func (self *Bittrex) GetOrder(ctx context.Context, client model.Client, market1 string, id string) (*model.Order, error) {
+	var err error
+
+	bittrex, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("arg is not a valid v3 client")
+	}
+	bittrex = bittrex.WithContext(ctx)
+
+	var order *exchange.Order
+	if order, err = bittrex.GetOrder(exchange.OrderId(id)); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	var createdAt time.Time
+	if createdAt, err = time.Parse(exchange.TIME_FORMAT, order.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	out := bittrexOrder(order, market1, createdAt)
+	return &out, nil
+}
+
+func (self *Bittrex) GetFills(ctx context.Context, client model.Client, market1 string, since time.Time) (model.Fills, error) {
+	var err error
+
+	bittrex, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("arg is not a valid v3 client")
+	}
+	bittrex = bittrex.WithContext(ctx)
+
+	var market3 string
+	if market3, err = self.convertMarket(market1); err != nil {
+		return nil, err
+	}
+
+	// the fills of an order are aggregated into the order
+	var history exchange.Orders
+	if history, err = bittrex.GetOrderHistory(market3); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	var out model.Fills
+	for _, order := range history {
+		var closedAt time.Time
+		if closedAt, err = time.Parse(exchange.TIME_FORMAT, order.ClosedAt); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		if closedAt.Before(since) {
+			continue
+		}
+		out = append(out, model.Fill{
+			ID:            string(order.Id),
+			OrderID:       string(order.Id),
+			ClientOrderID: string(order.ClientOrderId),
+			Side:          bittrexOrderSide(&order),
+			Market:        market1,
+			Price:         order.Price(),
+			Size:          order.QuantityFilled(),
+			Fee:           order.Commission,
+			FeeAsset:      market3[strings.Index(market3, "-")+1:], // the commission is in quote currency
+			CreatedAt:     closedAt,
 		})
 	}
 
 	return out, nil
 }
 
-func (self *Bittrex) GetBook(client interface{}, market1 string, side model.BookSide) (interface{}, error) {
+func (self *Bittrex) GetBook(ctx context.Context, client model.Client, market1 string, side model.BookSide) (interface{}, error) {
 	var err error
 
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	var market3 string
 	if market3, err = self.convertMarket(market1); err != nil {
@@ -893,13 +1045,13 @@ func (self *Bittrex) GetBook(client interface{}, market1 string, side model.Book
 	return nil, errors.Errorf("non-exhaustive match: %v", side)
 }
 
-func (self *Bittrex) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *Bittrex) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]exchange.BookEntry)
 	if !ok {
 		return nil, errors.New("arg is not a valid v3 order book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -924,13 +1076,14 @@ func (self *Bittrex) Aggregate(client, book interface{}, market string, agg floa
 	return out, nil
 }
 
-func (self *Bittrex) GetTicker(client interface{}, market1 string) (float64, error) {
+func (self *Bittrex) GetTicker(ctx context.Context, client model.Client, market1 string) (float64, error) {
 	var err error
 
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return 0, errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	var market3 string
 	if market3, err = self.convertMarket(market1); err != nil {
@@ -945,11 +1098,16 @@ func (self *Bittrex) GetTicker(client interface{}, market1 string) (float64, err
 	return ticker.LastTradeRate, nil
 }
 
-func (self *Bittrex) Get24h(client interface{}, market1 string) (*model.Stats, error) {
+func (self *Bittrex) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (self *Bittrex) Get24h(ctx context.Context, client model.Client, market1 string) (*model.Stats, error) {
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	market3, err := self.convertMarket(market1)
 	if err != nil {
@@ -977,11 +1135,40 @@ func (self *Bittrex) Get24h(client interface{}, market1 string) (*model.Stats, e
 	}, nil
 }
 
-func (self *Bittrex) GetPricePrec(client interface{}, market1 string) (int, error) {
+func (self *Bittrex) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	return lowestTier(0.0035, 0.0035), nil
+}
+
+func (self *Bittrex) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	bittrex, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("arg is not a valid v3 client")
+	}
+	bittrex = bittrex.WithContext(ctx)
+	balances, err := bittrex.GetBalances()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Balances
+	for _, balance := range balances {
+		if balance.Total > 0 {
+			out = append(out, model.Balance{
+				Asset:     balance.CurrencySymbol,
+				Total:     balance.Total,
+				Available: balance.Available,
+				Locked:    balance.Total - balance.Available,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (self *Bittrex) GetPricePrec(ctx context.Context, client model.Client, market1 string) (int, error) {
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return 0, errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	market3, err := self.getMarket(bittrex, market1)
 	if err != nil {
@@ -991,13 +1178,13 @@ func (self *Bittrex) GetPricePrec(client interface{}, market1 string) (int, erro
 	return market3.Precision, nil
 }
 
-func (self *Bittrex) GetSizePrec(client interface{}, market string) (int, error) {
+func (self *Bittrex) GetSizePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	return 8, nil
 }
 
-func (self *Bittrex) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
-	return model.GetSizeMax(hold, earn, def, mult, func() int {
-		prec, err := self.GetSizePrec(client, self.FormatMarket(base, quote))
+func (self *Bittrex) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+	return model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, self.FormatMarket(base, quote)), func() int {
+		prec, err := self.GetSizePrec(ctx, client, self.FormatMarket(base, quote))
 		if err != nil {
 			return 0
 		}
@@ -1005,13 +1192,14 @@ func (self *Bittrex) GetMaxSize(client interface{}, base, quote string, hold, ea
 	})
 }
 
-func (self *Bittrex) Cancel(client interface{}, market1 string, side model.OrderSide) error {
+func (self *Bittrex) Cancel(ctx context.Context, client model.Client, market1 string, side model.OrderSide) error {
 	var err error
 
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	var market3 string
 	if market3, err = self.convertMarket(market1); err != nil {
@@ -1034,13 +1222,26 @@ func (self *Bittrex) Cancel(client interface{}, market1 string, side model.Order
 	return nil
 }
 
-func (self *Bittrex) Buy(client interface{}, cancel bool, market1 string, calls model.Calls, deviation float64, kind model.OrderType) error {
+func (self *Bittrex) CancelOrder(ctx context.Context, client model.Client, market1 string, id string) error {
+	bittrex, ok := client.(*exchange.Client)
+	if !ok {
+		return errors.New("arg is not a valid v3 client")
+	}
+	bittrex = bittrex.WithContext(ctx)
+	if err := bittrex.CancelOrder(exchange.OrderId(id)); err != nil {
+		return errors.Wrap(err, 1)
+	}
+	return nil
+}
+
+func (self *Bittrex) Buy(ctx context.Context, client model.Client, cancel bool, market1 string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	var err error
 
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	var market3 string
 	if market3, err = self.convertMarket(market1); err != nil {
@@ -1074,26 +1275,26 @@ func (self *Bittrex) Buy(client interface{}, cancel bool, market1 string, calls
 		if !call.Skip {
 			limit := call.Price
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
-			_, _, err = self.Order(client,
+			_, _, err = self.Order(ctx, client,
 				model.BUY,
 				market1,
 				call.Size,
 				limit,
-				kind, "",
+				kind, nil, "",
 			)
 			if err != nil {
 				// --- BEGIN --- svanas 2019-05-12 ------------------------------------
 				if strings.Contains(err.Error(), "MIN_TRADE_REQUIREMENT_NOT_MET") {
 					var min float64
 					if min, err = self.minTradeSize(bittrex, market1); err == nil {
-						_, _, err = self.Order(client,
+						_, _, err = self.Order(ctx, client,
 							model.BUY,
 							market1,
 							min,
 							limit,
-							kind, "",
+							kind, nil, "",
 						)
 					}
 				}
@@ -1110,11 +1311,12 @@ func (self *Bittrex) IsLeveragedToken(name string) bool {
 	return len(name) > 4 && (strings.HasSuffix(strings.ToUpper(name), "BEAR") || strings.HasSuffix(strings.ToUpper(name), "BULL"))
 }
 
-func (self *Bittrex) HasAlgoOrder(client interface{}, market1 string) (bool, error) {
+func (self *Bittrex) HasAlgoOrder(ctx context.Context, client model.Client, market1 string) (bool, error) {
 	bittrex, ok := client.(*exchange.Client)
 	if !ok {
 		return false, errors.New("arg is not a valid v3 client")
 	}
+	bittrex = bittrex.WithContext(ctx)
 
 	market3, err := self.convertMarket(market1)
 	if err != nil {
@@ -2,6 +2,7 @@
 package exchanges
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -10,6 +11,7 @@ import (
 
 	filemutex "github.com/alexflint/go-filemutex"
 	"github.com/svanas/nefertiti/aggregation"
+	"github.com/svanas/nefertiti/breaker"
 	exchange "github.com/svanas/nefertiti/cexio"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
@@ -20,6 +22,7 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
+	"github.com/svanas/nefertiti/shutdown"
 )
 
 var (
@@ -75,6 +78,17 @@ func init() {
 	}
 }
 
+// cexIoCodes maps the CEX.IO error messages to our error classes
+var cexIoCodes = errors.Codes{
+	{Code: "Insufficient funds", Class: errors.INSUFFICIENT_FUNDS},
+	{Code: "Invalid amount", Class: errors.INVALID_SIZE},
+	{Code: "Invalid price", Class: errors.INVALID_PRICE},
+	{Code: "Rate limit exceeded", Class: errors.RATE_LIMITED},
+	{Code: "Invalid API key", Class: errors.AUTH},
+	{Code: "API key is not activated", Class: errors.AUTH},
+	{Code: "Nonce must be incremented", Class: errors.TRANSIENT},
+}
+
 type CexIo struct {
 	*model.ExchangeInfo
 }
@@ -101,7 +115,22 @@ func (self *CexIo) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
-func (self *CexIo) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *CexIo) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        false,
+		MarketOrders:     false,
+		MarketBuyByQuote: false,
+		PostOnly:         false,
+		StopLoss:         false,
+		StopLossStrategy: false,
+		OCO:              false,
+		Trailing:         false,
+		BotOrders:        false,
+	}
+}
+
+func (self *CexIo) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	if permission != model.PRIVATE {
 		return exchange.New("", "", ""), nil
 	}
@@ -119,13 +148,13 @@ func (self *CexIo) GetClient(permission model.Permission, sandbox bool) (interfa
 	return exchange.New(apiKey, apiSecret, userName), nil
 }
 
-func (self *CexIo) GetMarkets(cached, sandbox bool, ignore []string) ([]model.Market, error) {
+func (self *CexIo) GetMarkets(ctx context.Context, cached, sandbox bool, ignore []string) ([]model.Market, error) {
 	var (
 		err error
 		out []model.Market
 	)
 
-	client := exchange.New("", "", "")
+	client := exchange.New("", "", "").WithContext(ctx)
 
 	var pairs []exchange.Pair
 	if pairs, err = client.CurrencyLimits(); err != nil {
@@ -222,10 +251,12 @@ func (self *CexIo) listen(
 
 // listens to the archived orders, look for newly filled orders, automatically place new LIMIT SELL orders.
 func (self *CexIo) sell(
+	ctx context.Context,
 	client *exchange.Client,
 	mult multiplier.Mult,
 	hold, earn model.Markets,
 	service model.Notify,
+	values *fillValuer,
 	twitter *notify.TwitterKeys,
 	level int64,
 	old exchange.Orders,
@@ -234,7 +265,7 @@ func (self *CexIo) sell(
 	var err error
 
 	var markets []model.Market
-	if markets, err = self.GetMarkets(true, sandbox, nil); err != nil {
+	if markets, err = self.GetMarkets(ctx, true, sandbox, nil); err != nil {
 		return old, err
 	}
 
@@ -258,7 +289,7 @@ func (self *CexIo) sell(
 			if side != exchange.SIDE_UNKNOWN {
 				if notify.CanSend(level, notify.FILLED) {
 					if service != nil {
-						if err = service.SendMessage(order, fmt.Sprintf("CEX.IO - Done %s (Reason: Filled %f qty)", strings.Title(order.Type), order.Amount), model.ALWAYS); err != nil {
+						if err = service.SendMessage(order, fmt.Sprintf("CEX.IO - Done %s (Reason: Filled %f qty)%s", strings.Title(order.Type), order.Amount, values.value(ctx, self.FormatMarket(order.Symbol1, order.Symbol2), order.Amount, order.Price)), model.ALWAYS); err != nil {
 							log.Printf("[ERROR] %v", err)
 						}
 					}
@@ -276,14 +307,15 @@ func (self *CexIo) sell(
 						)
 						base, quote, err = model.ParseMarket(markets, market)
 						if err == nil {
-							qty := self.GetMaxSize(client, base, quote, hold.HasMarket(market), earn.HasMarket(market), order.Amount, mult)
+							fees := model.GetFees(ctx, self, client, market)
+							qty := self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(market), earn.HasMarket(market), order.Amount, fees.Mult(mult))
 							if qty > 0 {
 								var prec int
-								if prec, err = self.GetPricePrec(client, market); err == nil {
+								if prec, err = self.GetPricePrec(ctx, client, market); err == nil {
 									_, err = client.PlaceOrder(
 										order.Symbol1, order.Symbol2, exchange.SELL,
 										qty,
-										pricing.Multiply(order.Price, mult, prec),
+										pricing.Multiply(order.Price, fees.Mult(mult), prec),
 									)
 								}
 							}
@@ -301,6 +333,7 @@ func (self *CexIo) sell(
 }
 
 func (self *CexIo) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -334,7 +367,8 @@ func (self *CexIo) Sell(
 		}
 	}
 
-	client := exchange.New(apiKey, apiSecret, userName)
+	client := exchange.New(apiKey, apiSecret, userName).WithContext(ctx)
+	values := newFillValuer(self, client)
 
 	// get my open orders
 	var open []exchange.Order
@@ -352,7 +386,10 @@ func (self *CexIo) Sell(
 		return err
 	}
 
-	for {
+	for !shutdown.Stopping() {
+		if !breaker.Get(self.Name).Wait() {
+			break
+		}
 		// read the dynamic settings
 		var (
 			level int64 = notify.LEVEL_DEFAULT
@@ -364,29 +401,39 @@ func (self *CexIo) Sell(
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listen to the archived orders, look for newly filled orders, automatically place new LIMIT SELL orders.
-		if archive, err = self.sell(client, mult, hold, earn, service, twitter, level, archive, sandbox); err != nil {
+		if archive, err = self.sell(ctx, client, mult, hold, earn, service, values, twitter, level, archive, sandbox); err != nil {
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listen to the open orders, look for cancelled orders, send a notification.
 		if open, err = self.listen(client, service, level, open); err != nil {
 			logger.Error(self.Name, err, level, service)
 		}
+		breaker.Get(self.Name).Done(err, service, level)
 	}
+
+	return nil
 }
 
 func (self *CexIo) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	cexio, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, nil, errors.New("invalid argument: client")
 	}
+	cexio = cexio.WithContext(ctx)
+
+	if err = plainOrder(self.Name, kind, opts); err != nil {
+		return nil, nil, err
+	}
 
 	var symbol1 string
 	var symbol2 string
@@ -413,21 +460,47 @@ func (self *CexIo) Order(
 	return []byte(order.Id), out, nil
 }
 
-func (self *CexIo) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *CexIo) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *CexIo) OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *CexIo) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *CexIo) GetClosed(client interface{}, market string) (model.Orders, error) {
+func cexioOrder(order *exchange.Order, market string) model.Order {
+	out := model.Order{
+		ID:       order.Id,
+		Side:     model.NewOrderSide(order.Type),
+		Type:     model.LIMIT,
+		Market:   market,
+		Size:     order.Amount,
+		Price:    order.Price,
+		Filled:   order.Amount - order.GetRemains(),
+		AvgPrice: order.Price,
+		Fee:      order.Fee,
+		FeeAsset: order.Symbol2,
+	}
+	out.CreatedAt, _ = order.GetTime()
+	switch order.Status {
+	case exchange.ORDER_STATUS_DONE:
+		out.Status = model.FILLED
+	case exchange.ORDER_STATUS_CANCELLED, exchange.ORDER_STATUS_CANCELLED_PARTIALLY_DONE:
+		out.Status = model.CANCELLED
+	default:
+		out.Status = model.GetOrderStatus(out.Size, out.Filled, false)
+	}
+	return out
+}
+
+func (self *CexIo) GetClosed(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
 	cexio, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	cexio = cexio.WithContext(ctx)
 
 	var symbol1 string
 	var symbol2 string
@@ -442,24 +515,20 @@ func (self *CexIo) GetClosed(client interface{}, market string) (model.Orders, e
 
 	var out model.Orders
 	for _, order := range orders {
-		out = append(out, model.Order{
-			Side:   model.NewOrderSide(order.Type),
-			Market: market,
-			Size:   order.Amount,
-			Price:  order.Price,
-		})
+		out = append(out, cexioOrder(&order, market))
 	}
 
 	return out, nil
 }
 
-func (self *CexIo) GetOpened(client interface{}, market string) (model.Orders, error) {
+func (self *CexIo) GetOpened(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
 	cexio, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	cexio = cexio.WithContext(ctx)
 
 	var symbol1 string
 	var symbol2 string
@@ -474,24 +543,45 @@ func (self *CexIo) GetOpened(client interface{}, market string) (model.Orders, e
 
 	var out model.Orders
 	for _, order := range orders {
-		out = append(out, model.Order{
-			Side:   model.NewOrderSide(order.Type),
-			Market: market,
-			Size:   order.Amount,
-			Price:  order.Price,
-		})
+		out = append(out, cexioOrder(&order, market))
 	}
 
 	return out, nil
 }
 
-func (self *CexIo) GetBook(client interface{}, market string, side model.BookSide) (interface{}, error) {
+func (self *CexIo) GetOrder(ctx context.Context, client model.Client, market string, id string) (*model.Order, error) {
+	cexio, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	cexio = cexio.WithContext(ctx)
+
+	order, err := cexio.GetOrder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	out := cexioOrder(order, market)
+	return &out, nil
+}
+
+func (self *CexIo) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (model.Fills, error) {
+	// the API doesn't give us our fills, so we make do with our closed orders.
+	closed, err := self.GetClosed(ctx, client, market)
+	if err != nil {
+		return nil, err
+	}
+	return model.OrdersToFills(closed, since), nil
+}
+
+func (self *CexIo) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (interface{}, error) {
 	var err error
 
 	cexio, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	cexio = cexio.WithContext(ctx)
 
 	var symbol1 string
 	var symbol2 string
@@ -514,13 +604,13 @@ func (self *CexIo) GetBook(client interface{}, market string, side model.BookSid
 	return out, nil
 }
 
-func (self *CexIo) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *CexIo) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]exchange.BookEntry)
 	if !ok {
 		return nil, errors.New("invalid argument: book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -545,11 +635,12 @@ func (self *CexIo) Aggregate(client, book interface{}, market string, agg float6
 	return out, nil
 }
 
-func (self *CexIo) GetTicker(client interface{}, market string) (float64, error) {
+func (self *CexIo) GetTicker(ctx context.Context, client model.Client, market string) (float64, error) {
 	cexio, ok := client.(*exchange.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	cexio = cexio.WithContext(ctx)
 
 	symbol1, symbol2, err := self.decodePair(market)
 	if err != nil {
@@ -564,11 +655,16 @@ func (self *CexIo) GetTicker(client interface{}, market string) (float64, error)
 	return ticker.Last, nil
 }
 
-func (self *CexIo) Get24h(client interface{}, market string) (*model.Stats, error) {
+func (self *CexIo) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (self *CexIo) Get24h(ctx context.Context, client model.Client, market string) (*model.Stats, error) {
 	cexio, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	cexio = cexio.WithContext(ctx)
 
 	symbol1, symbol2, err := self.decodePair(market)
 	if err != nil {
@@ -588,8 +684,36 @@ func (self *CexIo) Get24h(client interface{}, market string) (*model.Stats, erro
 	}, nil
 }
 
+func (self *CexIo) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	return lowestTier(0.0016, 0.0025), nil
+}
+
+func (self *CexIo) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	cexio, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	cexio = cexio.WithContext(ctx)
+	balances, err := cexio.Balances()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Balances
+	for _, balance := range balances {
+		if balance.Available+balance.Orders > 0 {
+			out = append(out, model.Balance{
+				Asset:     balance.Currency,
+				Total:     balance.Available + balance.Orders,
+				Available: balance.Available,
+				Locked:    balance.Orders,
+			})
+		}
+	}
+	return out, nil
+}
+
 // see: https://blog.cex.io/news/precision-and-minimum-order-size-change-for-certain-trading-pairs-20957
-func (self *CexIo) GetPricePrec(client interface{}, market string) (int, error) {
+func (self *CexIo) GetPricePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	if out, ok := func() map[string]int {
 		return map[string]int{
 			"ADA-EUR":  6,
@@ -675,13 +799,13 @@ func (self *CexIo) GetPricePrec(client interface{}, market string) (int, error)
 	return 0, nil
 }
 
-func (self *CexIo) GetSizePrec(client interface{}, market string) (int, error) {
+func (self *CexIo) GetSizePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	return 8, nil
 }
 
-func (self *CexIo) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
-	return model.GetSizeMax(hold, earn, def, mult, func() int {
-		prec, err := self.GetSizePrec(client, self.FormatMarket(base, quote))
+func (self *CexIo) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+	return model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, self.FormatMarket(base, quote)), func() int {
+		prec, err := self.GetSizePrec(ctx, client, self.FormatMarket(base, quote))
 		if err != nil {
 			return 0
 		}
@@ -689,13 +813,14 @@ func (self *CexIo) GetMaxSize(client interface{}, base, quote string, hold, earn
 	})
 }
 
-func (self *CexIo) Cancel(client interface{}, market string, side model.OrderSide) error {
+func (self *CexIo) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
 	var err error
 
 	cexio, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	cexio = cexio.WithContext(ctx)
 
 	var symbol1 string
 	var symbol2 string
@@ -719,13 +844,23 @@ func (self *CexIo) Cancel(client interface{}, market string, side model.OrderSid
 	return nil
 }
 
-func (self *CexIo) Buy(client interface{}, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
+func (self *CexIo) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	cexio, ok := client.(*exchange.Client)
+	if !ok {
+		return errors.New("invalid argument: client")
+	}
+	cexio = cexio.WithContext(ctx)
+	return cexio.CancelOrder(id)
+}
+
+func (self *CexIo) Buy(ctx context.Context, client model.Client, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	var err error
 
 	cexio, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	cexio = cexio.WithContext(ctx)
 
 	var symbol1 string
 	var symbol2 string
@@ -760,7 +895,7 @@ func (self *CexIo) Buy(client interface{}, cancel bool, market string, calls mod
 		if !call.Skip {
 			limit := call.Price
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
 			if _, err = cexio.PlaceOrder(symbol1, symbol2, exchange.BUY, call.Size, limit); err != nil {
 				return err
@@ -775,7 +910,7 @@ func (self *CexIo) IsLeveragedToken(name string) bool {
 	return false
 }
 
-func (self *CexIo) HasAlgoOrder(client interface{}, market string) (bool, error) {
+func (self *CexIo) HasAlgoOrder(ctx context.Context, client model.Client, market string) (bool, error) {
 	return false, nil
 }
 
@@ -2,6 +2,7 @@
 package exchanges
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -14,6 +15,7 @@ import (
 	filemutex "github.com/alexflint/go-filemutex"
 	exchange "github.com/svanas/go-crypto-dot-com"
 	"github.com/svanas/nefertiti/aggregation"
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/logger"
@@ -23,6 +25,7 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
+	"github.com/svanas/nefertiti/shutdown"
 )
 
 var (
@@ -39,6 +42,25 @@ type CryptoDotComSessionInfo struct {
 	Cooldown bool `json:"cooldown"`
 }
 
+// cryptoDotComClient wraps the crypto.com SDK client, so it can be a model.Client
+type cryptoDotComClient struct {
+	*exchange.Client
+}
+
+func newCryptoDotComClient(apiKey, apiSecret string) *cryptoDotComClient {
+	return &cryptoDotComClient{exchange.New(apiKey, apiSecret)}
+}
+
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (client *cryptoDotComClient) Exchange() string {
+	return "crypto.com"
+}
+
+// WithContext returns the client itself: the SDK does not take a context, so its requests are not cancelled when ctx is done
+func (client *cryptoDotComClient) WithContext(ctx context.Context) *cryptoDotComClient {
+	return client
+}
+
 func cryptoDotComRequestsPerSecond(def float64) (float64, error) {
 	var (
 		err  error
@@ -126,6 +148,16 @@ func init() {
 	}
 }
 
+// cryptoDotComCodes maps the Crypto.com error codes to our error classes
+var cryptoDotComCodes = errors.Codes{
+	{Code: "INSUFFICIENT_AVAILABLE_BALANCE", Class: errors.INSUFFICIENT_FUNDS},
+	{Code: "INVALID_PRICE", Class: errors.INVALID_PRICE},
+	{Code: "FAR_AWAY_LIMIT_PRICE", Class: errors.INVALID_PRICE},
+	{Code: "TOO_MANY_REQUESTS", Class: errors.RATE_LIMITED},
+	{Code: "UNAUTHORIZED", Class: errors.AUTH},
+	{Code: "INVALID_NONCE", Class: errors.TRANSIENT},
+}
+
 type CryptoDotCom struct {
 	*model.ExchangeInfo
 	symbols []exchange.Symbol
@@ -133,7 +165,7 @@ type CryptoDotCom struct {
 
 //-------------------- private -------------------
 
-func (self *CryptoDotCom) getSymbol(client *exchange.Client, name string) (*exchange.Symbol, error) {
+func (self *CryptoDotCom) getSymbol(client *cryptoDotComClient, name string) (*exchange.Symbol, error) {
 	cached := true
 	for {
 		symbols, err := self.getSymbols(client, nil, cached)
@@ -152,7 +184,7 @@ func (self *CryptoDotCom) getSymbol(client *exchange.Client, name string) (*exch
 	}
 }
 
-func (self *CryptoDotCom) getSymbols(client *exchange.Client, quotes []string, cached bool) ([]exchange.Symbol, error) {
+func (self *CryptoDotCom) getSymbols(client *cryptoDotComClient, quotes []string, cached bool) ([]exchange.Symbol, error) {
 	if len(self.symbols) == 0 || !cached {
 		var err error
 		if self.symbols, err = client.Symbols(); err != nil {
@@ -214,15 +246,59 @@ func (self *CryptoDotCom) indexByTradeId(trades []exchange.Trade, id int64) int
 	return -1
 }
 
+// the API doesn't tell us how much of an open order has been filled
+func (self *CryptoDotCom) openToOrder(order *exchange.Order, market string) model.Order {
+	return model.Order{
+		ID:        strconv.FormatInt(order.Id, 10),
+		Side:      self.getOrderSide(order.GetSide()),
+		Type:      model.LIMIT,
+		Status:    model.OPEN,
+		Market:    market,
+		Size:      order.Volume,
+		Price:     order.Price,
+		CreatedAt: order.GetCreatedAt(),
+	}
+}
+
+// a trade is a filled order
+func (self *CryptoDotCom) tradeToOrder(trade *exchange.Trade, market string) model.Order {
+	return model.Order{
+		ID:        strconv.FormatInt(trade.Id, 10),
+		Side:      self.getOrderSide(trade.GetSide()),
+		Status:    model.FILLED,
+		Market:    market,
+		Size:      trade.Volume,
+		Price:     trade.Price,
+		Filled:    trade.Volume,
+		AvgPrice:  trade.Price,
+		CreatedAt: trade.GetCreatedAt(),
+	}
+}
+
 //-------------------- public --------------------
 
 func (self *CryptoDotCom) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
-func (self *CryptoDotCom) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *CryptoDotCom) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        false,
+		MarketOrders:     true,
+		MarketBuyByQuote: false,
+		PostOnly:         false,
+		StopLoss:         false,
+		StopLossStrategy: false,
+		OCO:              false,
+		Trailing:         false,
+		BotOrders:        false,
+	}
+}
+
+func (self *CryptoDotCom) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	if permission != model.PRIVATE {
-		return exchange.New("", ""), nil
+		return newCryptoDotComClient("", ""), nil
 	}
 
 	var (
@@ -234,13 +310,13 @@ func (self *CryptoDotCom) GetClient(permission model.Permission, sandbox bool) (
 		return nil, err
 	}
 
-	return exchange.New(apiKey, apiSecret), nil
+	return newCryptoDotComClient(apiKey, apiSecret), nil
 }
 
-func (self *CryptoDotCom) GetMarkets(cached, sandbox bool, blacklist []string) ([]model.Market, error) {
+func (self *CryptoDotCom) GetMarkets(ctx context.Context, cached, sandbox bool, blacklist []string) ([]model.Market, error) {
 	var out []model.Market
 
-	symbols, err := self.getSymbols(exchange.New("", ""), nil, cached)
+	symbols, err := self.getSymbols(newCryptoDotComClient("", ""), nil, cached)
 	if err != nil {
 		return nil, err
 	}
@@ -271,7 +347,7 @@ func (self *CryptoDotCom) FormatMarket(base, quote string) string {
 
 // listen to the opened orders, look for cancelled orders, send a notification.
 func (self *CryptoDotCom) listen(
-	client *exchange.Client,
+	client *cryptoDotComClient,
 	symbols []exchange.Symbol,
 	service model.Notify,
 	level int64,
@@ -341,7 +417,8 @@ func (self *CryptoDotCom) listen(
 
 // listen to the filled orders, look for newly filled orders, automatically place new LIMIT SELL orders.
 func (self *CryptoDotCom) sell(
-	client *exchange.Client,
+	ctx context.Context,
+	client *cryptoDotComClient,
 	symbols []exchange.Symbol,
 	mult multiplier.Mult,
 	hold, earn model.Markets,
@@ -402,7 +479,7 @@ func (self *CryptoDotCom) sell(
 
 			if qty > new[i].Volume {
 				var prec int
-				if prec, err = self.GetSizePrec(client, new[i].Symbol); err == nil {
+				if prec, err = self.GetSizePrec(ctx, client, new[i].Symbol); err == nil {
 					qty = precision.Floor(qty, prec)
 				}
 			}
@@ -414,16 +491,17 @@ func (self *CryptoDotCom) sell(
 			)
 			base, quote, err = self.parseSymbol(symbols, new[i].Symbol)
 			if err == nil {
-				qty = self.GetMaxSize(client, base, quote, hold.HasMarket(new[i].Symbol), earn.HasMarket(new[i].Symbol), qty, mult)
+				fees := model.GetFees(ctx, self, client, new[i].Symbol)
+				qty = self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(new[i].Symbol), earn.HasMarket(new[i].Symbol), qty, fees.Mult(mult))
 				if qty > 0 {
 					var prec int
-					if prec, err = self.GetPricePrec(client, new[i].Symbol); err == nil {
+					if prec, err = self.GetPricePrec(ctx, client, new[i].Symbol); err == nil {
 						_, err = client.CreateOrder(
 							new[i].Symbol,
 							exchange.SELL,
 							exchange.LIMIT,
 							qty,
-							pricing.Multiply(new[i].Price, mult, prec),
+							pricing.Multiply(new[i].Price, fees.Mult(mult), prec),
 						)
 					}
 				}
@@ -444,6 +522,7 @@ func (self *CryptoDotCom) sell(
 }
 
 func (self *CryptoDotCom) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -467,7 +546,7 @@ func (self *CryptoDotCom) Sell(
 		return err
 	}
 
-	client := exchange.New(apiKey, apiSecret)
+	client := newCryptoDotComClient(apiKey, apiSecret)
 
 	var (
 		quotes  []string = []string{model.BTC}
@@ -509,7 +588,10 @@ func (self *CryptoDotCom) Sell(
 		return err
 	}
 
-	for {
+	for !shutdown.Stopping() {
+		if !breaker.Get(self.Name).Wait() {
+			break
+		}
 		// read the dynamic settings
 		var (
 			level int64 = notify.LEVEL_DEFAULT
@@ -523,31 +605,41 @@ func (self *CryptoDotCom) Sell(
 			logger.Error(self.Name, err, level, service)
 		}
 		// listen to the filled orders, look for newly filled orders, automatically place new LIMIT SELL orders.
-		if filled, err = self.sell(client, symbols, mult, hold, earn, service, level, filled); err != nil {
+		if filled, err = self.sell(ctx, client, symbols, mult, hold, earn, service, level, filled); err != nil {
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listen to the opened orders, look for cancelled orders, send a notification.
 		if opened, err = self.listen(client, symbols, service, level, opened, filled); err != nil {
 			logger.Error(self.Name, err, level, service)
 		}
+		breaker.Get(self.Name).Done(err, service, level)
 	}
+
+	return nil
 }
 
 func (self *CryptoDotCom) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	var out int64
 
-	crypto, ok := client.(*exchange.Client)
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return nil, nil, errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
+
+	if err = plainOrder(self.Name, kind, opts); err != nil {
+		return nil, nil, err
+	}
 
 	if side == model.BUY {
 		if kind == model.MARKET {
@@ -570,21 +662,22 @@ func (self *CryptoDotCom) Order(
 	return []byte(strconv.FormatInt(out, 10)), nil, nil
 }
 
-func (self *CryptoDotCom) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *CryptoDotCom) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *CryptoDotCom) OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *CryptoDotCom) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *CryptoDotCom) GetClosed(client interface{}, market string) (model.Orders, error) {
+func (self *CryptoDotCom) GetClosed(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
-	crypto, ok := client.(*exchange.Client)
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
 
 	var trades []exchange.Trade
 	if trades, err = crypto.MyTrades(market); err != nil {
@@ -593,25 +686,20 @@ func (self *CryptoDotCom) GetClosed(client interface{}, market string) (model.Or
 
 	var out model.Orders
 	for _, trade := range trades {
-		out = append(out, model.Order{
-			Side:      self.getOrderSide(trade.GetSide()),
-			Market:    market,
-			Size:      trade.Volume,
-			Price:     trade.Price,
-			CreatedAt: trade.GetCreatedAt(),
-		})
+		out = append(out, self.tradeToOrder(&trade, market))
 	}
 
 	return out, nil
 }
 
-func (self *CryptoDotCom) GetOpened(client interface{}, market string) (model.Orders, error) {
+func (self *CryptoDotCom) GetOpened(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
-	crypto, ok := client.(*exchange.Client)
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
 
 	var orders []exchange.Order
 	if orders, err = crypto.OpenOrders(market); err != nil {
@@ -620,25 +708,63 @@ func (self *CryptoDotCom) GetOpened(client interface{}, market string) (model.Or
 
 	var out model.Orders
 	for _, order := range orders {
-		out = append(out, model.Order{
-			Side:      self.getOrderSide(order.GetSide()),
-			Market:    market,
-			Size:      order.Volume,
-			Price:     order.Price,
-			CreatedAt: order.GetCreatedAt(),
-		})
+		out = append(out, self.openToOrder(&order, market))
 	}
 
 	return out, nil
 }
 
-func (self *CryptoDotCom) GetBook(client interface{}, market string, side model.BookSide) (interface{}, error) {
+func (self *CryptoDotCom) GetOrder(ctx context.Context, client model.Client, market string, id string) (*model.Order, error) {
+	crypto, ok := client.(*cryptoDotComClient)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	crypto = crypto.WithContext(ctx)
+
+	orderId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("order ID %s is invalid", id)
+	}
+
+	// the API doesn't give us one order, so we look for it in our open orders first, and then in our trades
+	orders, err := crypto.OpenOrders(market)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	if i := self.indexByOrderId(orders, orderId); i != -1 {
+		out := self.openToOrder(&orders[i], market)
+		return &out, nil
+	}
+
+	trades, err := crypto.MyTrades(market)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	if i := self.indexByTradeId(trades, orderId); i != -1 {
+		out := self.tradeToOrder(&trades[i], market)
+		return &out, nil
+	}
+
+	return nil, errors.Errorf("order %s does not exist", id)
+}
+
+func (self *CryptoDotCom) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (model.Fills, error) {
+	// the API doesn't give us our fills, so we make do with our closed orders. the fees are unknown.
+	closed, err := self.GetClosed(ctx, client, market)
+	if err != nil {
+		return nil, err
+	}
+	return model.OrdersToFills(closed, since), nil
+}
+
+func (self *CryptoDotCom) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (interface{}, error) {
 	var err error
 
-	crypto, ok := client.(*exchange.Client)
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
 
 	var book *exchange.OrderBook
 	if book, err = crypto.OrderBook(market); err != nil {
@@ -655,13 +781,13 @@ func (self *CryptoDotCom) GetBook(client interface{}, market string, side model.
 	return out, nil
 }
 
-func (self *CryptoDotCom) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *CryptoDotCom) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]exchange.BookEntry)
 	if !ok {
 		return nil, errors.New("invalid argument: book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -686,11 +812,12 @@ func (self *CryptoDotCom) Aggregate(client, book interface{}, market string, agg
 	return out, nil
 }
 
-func (self *CryptoDotCom) GetTicker(client interface{}, market string) (float64, error) {
-	crypto, ok := client.(*exchange.Client)
+func (self *CryptoDotCom) GetTicker(ctx context.Context, client model.Client, market string) (float64, error) {
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
 
 	ticker, err := crypto.Ticker(market)
 	if err != nil {
@@ -700,11 +827,16 @@ func (self *CryptoDotCom) GetTicker(client interface{}, market string) (float64,
 	return ticker.Last, nil
 }
 
-func (self *CryptoDotCom) Get24h(client interface{}, market string) (*model.Stats, error) {
-	crypto, ok := client.(*exchange.Client)
+func (self *CryptoDotCom) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (self *CryptoDotCom) Get24h(ctx context.Context, client model.Client, market string) (*model.Stats, error) {
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
 
 	ticker, err := crypto.Ticker(market)
 	if err != nil {
@@ -719,11 +851,20 @@ func (self *CryptoDotCom) Get24h(client interface{}, market string) (*model.Stat
 	}, nil
 }
 
-func (self *CryptoDotCom) GetPricePrec(client interface{}, market string) (int, error) {
-	crypto, ok := client.(*exchange.Client)
+func (self *CryptoDotCom) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	return lowestTier(0.004, 0.004), nil
+}
+
+func (self *CryptoDotCom) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	return nil, errors.New("balances are not supported on Crypto.com")
+}
+
+func (self *CryptoDotCom) GetPricePrec(ctx context.Context, client model.Client, market string) (int, error) {
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return 8, errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
 	symbol, err := self.getSymbol(crypto, market)
 	if err != nil {
 		return 8, err
@@ -731,11 +872,12 @@ func (self *CryptoDotCom) GetPricePrec(client interface{}, market string) (int,
 	return symbol.PriceDecimals, nil
 }
 
-func (self *CryptoDotCom) GetSizePrec(client interface{}, market string) (int, error) {
-	crypto, ok := client.(*exchange.Client)
+func (self *CryptoDotCom) GetSizePrec(ctx context.Context, client model.Client, market string) (int, error) {
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
 	symbol, err := self.getSymbol(crypto, market)
 	if err != nil {
 		return 0, err
@@ -743,14 +885,14 @@ func (self *CryptoDotCom) GetSizePrec(client interface{}, market string) (int, e
 	return symbol.QuantityDecimals, nil
 }
 
-func (self *CryptoDotCom) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+func (self *CryptoDotCom) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
 	if hold {
 		if base == "CRO" {
 			return 0
 		}
 	}
-	return model.GetSizeMax(hold, earn, def, mult, func() int {
-		prec, err := self.GetSizePrec(client, self.FormatMarket(base, quote))
+	return model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, self.FormatMarket(base, quote)), func() int {
+		prec, err := self.GetSizePrec(ctx, client, self.FormatMarket(base, quote))
 		if err != nil {
 			return 0
 		}
@@ -758,13 +900,14 @@ func (self *CryptoDotCom) GetMaxSize(client interface{}, base, quote string, hol
 	})
 }
 
-func (self *CryptoDotCom) Cancel(client interface{}, market string, side model.OrderSide) error {
+func (self *CryptoDotCom) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
 	var err error
 
-	crypto, ok := client.(*exchange.Client)
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
 
 	var orders []exchange.Order
 	if orders, err = crypto.OpenOrders(market); err != nil {
@@ -782,13 +925,33 @@ func (self *CryptoDotCom) Cancel(client interface{}, market string, side model.O
 	return nil
 }
 
-func (self *CryptoDotCom) Buy(client interface{}, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
+func (self *CryptoDotCom) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	crypto, ok := client.(*cryptoDotComClient)
+	if !ok {
+		return errors.New("invalid argument: client")
+	}
+	crypto = crypto.WithContext(ctx)
+
+	orderId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return errors.Errorf("order ID %s is invalid", id)
+	}
+
+	if err = crypto.CancelOrder(market, orderId); err != nil {
+		return errors.Wrap(err, 1)
+	}
+
+	return nil
+}
+
+func (self *CryptoDotCom) Buy(ctx context.Context, client model.Client, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	var err error
 
-	crypto, ok := client.(*exchange.Client)
+	crypto, ok := client.(*cryptoDotComClient)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	crypto = crypto.WithContext(ctx)
 
 	// step #1: delete the buy order(s) that are open in your book
 	if cancel {
@@ -817,7 +980,7 @@ func (self *CryptoDotCom) Buy(client interface{}, cancel bool, market string, ca
 		if !call.Skip {
 			limit := call.Price
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
 			if kind == model.MARKET {
 				_, err = crypto.CreateOrder(market, exchange.BUY, exchange.MARKET, call.Size, 0)
@@ -837,7 +1000,7 @@ func (self *CryptoDotCom) IsLeveragedToken(name string) bool {
 	return false
 }
 
-func (self *CryptoDotCom) HasAlgoOrder(client interface{}, market string) (bool, error) {
+func (self *CryptoDotCom) HasAlgoOrder(ctx context.Context, client model.Client, market string) (bool, error) {
 	return false, nil
 }
 
@@ -0,0 +1,117 @@
+package exchanges
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/transport"
+)
+
+// endpointFlag returns the value of an arg for one exchange. The arg is either one URL that applies to every exchange,
+// for example: --rest-url=http://localhost:8080, or a comma-separated list of exchange=URL pairs, for example:
+// --proxy=Binance=socks5://127.0.0.1:1080,Kucoin=http://10.0.0.1:3128
+func endpointFlag(name string, info *model.ExchangeInfo) (*url.URL, error) {
+	arg := flag.Get(name)
+	if !arg.Exists {
+		return nil, nil
+	}
+	for _, value := range arg.Split() {
+		value = strings.TrimSpace(value)
+		if i := strings.Index(value, "="); i > 0 && (!strings.Contains(value, "://") || i < strings.Index(value, "://")) {
+			if !info.Equals(value[:i]) {
+				continue
+			}
+			value = value[i+1:]
+		}
+		out, err := url.Parse(value)
+		if err != nil {
+			return nil, errors.Errorf("invalid argument: %s: %v", name, err)
+		}
+		if out.Scheme == "" || out.Host == "" {
+			return nil, errors.Errorf("invalid argument: %s. Expected a URL, for example: http://localhost:8080", name)
+		}
+		return out, nil
+	}
+	return nil, nil
+}
+
+// hostOf returns the host in a URL, or an empty string if there isn't one
+func hostOf(rawurl string) string {
+	if out, err := url.Parse(rawurl); err == nil {
+		return out.Host
+	}
+	return ""
+}
+
+// rebase replaces the scheme and host of an endpoint with those of another URL, and prepends its path (if any)
+func rebase(endpoint string, to *url.URL) string {
+	if endpoint == "" {
+		return ""
+	}
+	out, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	out.Scheme = to.Scheme
+	out.Host = to.Host
+	out.Path = strings.TrimSuffix(to.Path, "/") + out.Path
+	return out.String()
+}
+
+// overrideEndpoints applies the --rest-url, --ws-url and --proxy args to an exchange
+func overrideEndpoints(exchange model.Exchange) error {
+	info := exchange.GetInfo()
+
+	var hosts []string
+	for _, rawurl := range []string{info.REST.URI, info.REST.Sandbox, info.WebSocket.URI, info.WebSocket.Sandbox} {
+		if host := hostOf(rawurl); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	rest, err := endpointFlag("rest-url", info)
+	if err != nil {
+		return err
+	}
+	if rest != nil {
+		// some of our wrappers know about the exchange endpoint, others don't. redirect the latter.
+		for _, host := range []string{hostOf(info.REST.URI), hostOf(info.REST.Sandbox)} {
+			if host != "" {
+				transport.Redirect(host, rest)
+			}
+		}
+		info.REST.URI = rebase(info.REST.URI, rest)
+		info.REST.Sandbox = rebase(info.REST.Sandbox, rest)
+		hosts = append(hosts, rest.Host)
+	}
+
+	ws, err := endpointFlag("ws-url", info)
+	if err != nil {
+		return err
+	}
+	if ws != nil {
+		info.WebSocket.URI = rebase(info.WebSocket.URI, ws)
+		info.WebSocket.Sandbox = rebase(info.WebSocket.Sandbox, ws)
+		hosts = append(hosts, ws.Host)
+	}
+
+	proxy, err := endpointFlag("proxy", info)
+	if err != nil {
+		return err
+	}
+	if proxy != nil {
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return errors.Errorf("invalid argument: proxy. Unsupported scheme: %s", proxy.Scheme)
+		}
+		for _, host := range hosts {
+			transport.SetProxy(host, proxy)
+		}
+	}
+
+	return nil
+}
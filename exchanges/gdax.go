@@ -2,6 +2,7 @@
 package exchanges
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -26,6 +27,8 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
+	"github.com/svanas/nefertiti/shutdown"
+	"github.com/svanas/nefertiti/transport"
 )
 
 var (
@@ -97,15 +100,27 @@ func canNotify(level int64, msg *gdax.Message) bool {
 		(mt == gdax.MESSAGE_DONE && msg.GetReason() == gdax.REASON_FILLED)
 }
 
+// gdaxCodes maps the Coinbase Pro error messages to our error classes
+var gdaxCodes = errors.Codes{
+	{Code: "Insufficient funds", Class: errors.INSUFFICIENT_FUNDS},
+	{Code: "size is too", Class: errors.INVALID_SIZE},
+	{Code: "price is too", Class: errors.INVALID_PRICE},
+	{Code: "Trading is disabled", Class: errors.MARKET_CLOSED},
+	{Code: "rate limit exceeded", Class: errors.RATE_LIMITED},
+	{Code: "Invalid API Key", Class: errors.AUTH},
+	{Code: "Invalid Passphrase", Class: errors.AUTH},
+	{Code: "invalid signature", Class: errors.AUTH},
+}
+
 type Gdax struct {
 	*model.ExchangeInfo
 	products []exchange.Product
 }
 
-func (self *Gdax) getMinOrderSize(client *gdax.Client, market string) (float64, error) {
+func (self *Gdax) getMinOrderSize(ctx context.Context, client *gdax.Client, market string) (float64, error) {
 	cached := true
 	for {
-		products, err := self.getProducts(client, cached)
+		products, err := self.getProducts(ctx, client, cached)
 
 		if err != nil {
 			return 0, err
@@ -134,6 +149,22 @@ func (self *Gdax) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
+func (self *Gdax) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        true,
+		MarketOrders:     true,
+		MarketBuyByQuote: false,
+		PostOnly:         true,
+		StopLoss:         true,
+		StopLossStrategy: false,
+		OCO:              false,
+		Trailing:         false,
+		BotOrders:        false,
+		Candles:          []time.Duration{model.CANDLE_15M, model.CANDLE_1H, model.CANDLE_1D},
+	}
+}
+
 func (self *Gdax) getClient(apiKey, apiSecret, apiPassphrase string, sandbox bool) *gdax.Client {
 	client := gdax.New(sandbox)
 
@@ -146,7 +177,7 @@ func (self *Gdax) getClient(apiKey, apiSecret, apiPassphrase string, sandbox boo
 	return client
 }
 
-func (self *Gdax) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *Gdax) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	if permission != model.PRIVATE {
 		return gdax.New(sandbox), nil
 	}
@@ -164,12 +195,13 @@ func (self *Gdax) GetClient(permission model.Permission, sandbox bool) (interfac
 	return self.getClient(apiKey, apiSecret, apiPassphrase, sandbox), nil
 }
 
-func (self *Gdax) getProducts(client interface{}, cached bool) ([]exchange.Product, error) {
+func (self *Gdax) getProducts(ctx context.Context, client model.Client, cached bool) ([]exchange.Product, error) {
 	if self.products == nil || !cached {
 		gdaxClient, ok := client.(*gdax.Client)
 		if !ok {
 			return nil, errors.New("invalid argument: client")
 		}
+		gdaxClient = gdaxClient.WithContext(ctx)
 		var err error
 		if self.products, err = gdaxClient.GetProducts(); err != nil {
 			return nil, err
@@ -178,10 +210,10 @@ func (self *Gdax) getProducts(client interface{}, cached bool) ([]exchange.Produ
 	return self.products, nil
 }
 
-func (self *Gdax) GetMarkets(cached, sandbox bool, blacklist []string) ([]model.Market, error) {
+func (self *Gdax) GetMarkets(ctx context.Context, cached, sandbox bool, blacklist []string) ([]model.Market, error) {
 	var out []model.Market
 
-	products, err := self.getProducts(gdax.New(sandbox), cached)
+	products, err := self.getProducts(ctx, gdax.New(sandbox), cached)
 
 	if err != nil {
 		return nil, err
@@ -212,6 +244,7 @@ func (self *Gdax) FormatMarket(base, quote string) string {
 }
 
 func (self *Gdax) sell(
+	ctx context.Context,
 	hold, earn model.Markets,
 	apiSecret string,
 	apiKey string,
@@ -236,21 +269,26 @@ func (self *Gdax) sell(
 	}
 
 	var markets []model.Market
-	if markets, err = self.GetMarkets(true, sandbox, nil); err != nil {
+	if markets, err = self.GetMarkets(ctx, true, sandbox, nil); err != nil {
 		return err
 	}
 
+	client := self.getClient(apiKey, apiSecret, apiPassphrase, sandbox)
+	values := newFillValuer(self, client)
+
 	var (
 		conn *ws.Conn
 		init func() (*ws.Conn, error)
 	)
 
 	init = func() (out *ws.Conn, err error) {
-		if out, _, err = ws.DefaultDialer.Dial(URI, nil); err != nil {
+		dialer := *ws.DefaultDialer
+		dialer.Proxy = transport.Proxy
+		if out, _, err = dialer.Dial(URI, nil); err != nil {
 			return nil, errors.Wrap(err, 1)
 		}
 		var sub *gdaxSubscribePrivate
-		if sub, err = self.newSubscribePrivate(apiSecret, apiKey, apiPassphrase, sandbox); err != nil {
+		if sub, err = self.newSubscribePrivate(ctx, apiSecret, apiKey, apiPassphrase, sandbox); err != nil {
 			return nil, err
 		}
 		if err = out.WriteJSON(sub); err != nil {
@@ -271,7 +309,7 @@ func (self *Gdax) sell(
 	lastInterval := time.Now()
 	const intervalMinutes = 1
 
-	for {
+	for !shutdown.Stopping() {
 		var data []byte
 		_, data, err = conn.ReadMessage()
 		if err != nil {
@@ -288,7 +326,7 @@ func (self *Gdax) sell(
 				}
 			} else {
 				// read: connection reset by peer?
-				if strings.Contains(err.Error(), "connection reset by peer") || strings.Contains(err.Error(), "operation timed out") {
+				if errors.ClassOf(err) == errors.TRANSIENT {
 					for {
 						log.Printf("[ERROR] %v", err)
 						time.Sleep(5 * time.Second)
@@ -296,7 +334,7 @@ func (self *Gdax) sell(
 						if err == nil {
 							break
 						} else {
-							if !strings.Contains(err.Error(), "connection reset by peer") && !strings.Contains(err.Error(), "operation timed out") {
+							if errors.ClassOf(err) != errors.TRANSIENT {
 								logger.Error(self.Name, err, level, service)
 								return err
 							}
@@ -325,7 +363,14 @@ func (self *Gdax) sell(
 				if mt != gdax.MESSAGE_RECEIVED && mt != gdax.MESSAGE_MATCH {
 					if canNotify(level, &msg) {
 						if service != nil {
-							if err = service.SendMessage(msg, msg.Title(), model.ALWAYS); err != nil {
+							title := msg.Title()
+							// what is this fill worth in the --reference currency?
+							if mt == gdax.MESSAGE_DONE && msg.GetReason() == gdax.REASON_FILLED && flag.Exists("reference") {
+								if order, err := client.GetOrder(msg.OrderID); err == nil {
+									title += values.value(ctx, msg.ProductID, gdax.ParseFloat(order.FilledSize), gdax.ParseFloat(msg.Price))
+								}
+							}
+							if err = service.SendMessage(msg, title, model.ALWAYS); err != nil {
 								log.Printf("[ERROR] %v", err)
 							}
 						}
@@ -342,11 +387,9 @@ func (self *Gdax) sell(
 					if mr == gdax.REASON_FILLED {
 						side := model.NewOrderSide(msg.Side)
 						if side == model.BUY {
-							client := self.getClient(apiKey, apiSecret, apiPassphrase, sandbox)
-
 							price := gdax.ParseFloat(msg.Price)
 							if price == 0 {
-								if price, err = self.GetTicker(client, msg.ProductID); err != nil {
+								if price, err = self.GetTicker(ctx, client, msg.ProductID); err != nil {
 									logger.Error(self.Name, err, level, service)
 								}
 							}
@@ -357,7 +400,7 @@ func (self *Gdax) sell(
 							} else {
 								qty := old.GetSize()
 								if qty == 0 {
-									if qty, err = self.getMinOrderSize(client, msg.ProductID); err != nil {
+									if qty, err = self.getMinOrderSize(ctx, client, msg.ProductID); err != nil {
 										logger.Error(self.Name, err, level, service)
 									}
 									qty = qty * 5
@@ -368,7 +411,7 @@ func (self *Gdax) sell(
 									quote string
 								)
 								if base, quote, err = model.ParseMarket(markets, msg.ProductID); err != nil {
-									if markets, err = self.GetMarkets(false, sandbox, nil); err == nil {
+									if markets, err = self.GetMarkets(ctx, false, sandbox, nil); err == nil {
 										base, quote, err = model.ParseMarket(markets, msg.ProductID)
 									}
 									if err != nil {
@@ -377,7 +420,7 @@ func (self *Gdax) sell(
 								}
 
 								var prec int
-								if prec, err = self.GetPricePrec(client, msg.ProductID); err != nil {
+								if prec, err = self.GetPricePrec(ctx, client, msg.ProductID); err != nil {
 									logger.Error(self.Name, err, level, service)
 								}
 
@@ -386,7 +429,8 @@ func (self *Gdax) sell(
 									logger.Error(self.Name, err, level, service)
 								}
 
-								// by default, we will sell at a 5% profit
+								// by default, we will sell at a 5% profit (after fees)
+								fees := model.GetFees(ctx, self, client, msg.ProductID)
 
 								order := (&gdax.Order{
 									Order: &exchange.Order{
@@ -395,8 +439,8 @@ func (self *Gdax) sell(
 										ProductID: msg.ProductID,
 									},
 								}).
-									SetSize(self.GetMaxSize(client, base, quote, hold.HasMarket(msg.ProductID), earn.HasMarket(msg.ProductID), qty, mult)).
-									SetPrice(pricing.Multiply(price, mult, prec))
+									SetSize(self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(msg.ProductID), earn.HasMarket(msg.ProductID), qty, fees.Mult(mult))).
+									SetPrice(pricing.Multiply(price, fees.Mult(mult), prec))
 
 								// log the newly created SELL order
 								var raw []byte
@@ -423,7 +467,6 @@ func (self *Gdax) sell(
 			var (
 				cursor *exchange.Cursor
 				orders []gdax.Order
-				client *gdax.Client = self.getClient(apiKey, apiSecret, apiPassphrase, sandbox)
 			)
 
 			// follow up on the "aggressive" strategy
@@ -445,7 +488,7 @@ func (self *Gdax) sell(
 					logger.Error(self.Name, errors.Wrap(err, 1), level, service)
 				} else {
 					var products []exchange.Product
-					if products, err = self.getProducts(client, true); err != nil {
+					if products, err = self.getProducts(ctx, client, true); err != nil {
 						logger.Error(self.Name, err, level, service)
 					} else {
 						for _, product := range products {
@@ -469,7 +512,7 @@ func (self *Gdax) sell(
 								if !youngest.IsZero() && time.Since(youngest).Hours() > 24*rebuyAfterDays {
 									// did we recently sell an "aggressive" order on this market? then prevent us from buying this pump.
 									var closed model.Orders
-									if closed, err = self.GetClosed(client, product.ID); err != nil {
+									if closed, err = self.GetClosed(ctx, client, product.ID); err != nil {
 										logger.Error(self.Name, err, level, service)
 									} else {
 										if time.Since(closed.Youngest(model.SELL, time.Now())).Hours() < 24*rebuyAfterDays {
@@ -493,7 +536,7 @@ func (self *Gdax) sell(
 											} else {
 												if hold.HasMarket(product.ID) {
 													qty = precision.Round((qty * 5), func() int {
-														prec, err := self.GetSizePrec(client, product.ID)
+														prec, err := self.GetSizePrec(ctx, client, product.ID)
 														if err != nil {
 															return 0
 														} else {
@@ -538,9 +581,12 @@ func (self *Gdax) sell(
 			lastInterval = time.Now()
 		}
 	}
+
+	return conn.Close()
 }
 
 func (self *Gdax) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -566,7 +612,7 @@ func (self *Gdax) Sell(
 	apiUserId = flag.Get("api-user-id").String()
 	if apiUserId == "" {
 		// get the GDAX user ID
-		client := self.getClient(apiKey, apiSecret, apiPassphrase, sandbox)
+		client := self.getClient(apiKey, apiSecret, apiPassphrase, sandbox).WithContext(ctx)
 		var me *gdax.Me
 		if me, err = client.GetMe(); err != nil {
 			return errors.Wrap(err, 1)
@@ -586,31 +632,57 @@ func (self *Gdax) Sell(
 		}
 	}
 
-	return self.sell(hold, earn, apiSecret, apiKey, apiPassphrase, apiUserId, service, twitter, sandbox, debug, success)
+	return self.sell(ctx, hold, earn, apiSecret, apiKey, apiPassphrase, apiUserId, service, twitter, sandbox, debug, success)
 }
 
 func (self *Gdax) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	gdaxClient, ok := client.(*gdax.Client)
 	if !ok {
 		return nil, nil, errors.New("invalid argument: client")
 	}
+	gdaxClient = gdaxClient.WithContext(ctx)
+
+	if err = opts.Validate(kind); err != nil {
+		return nil, nil, err
+	}
 
 	order := (&gdax.Order{
 		Order: &exchange.Order{
 			Type:      model.OrderTypeString[model.LIMIT],
 			Side:      model.OrderSideString[side],
 			ProductID: market,
+			PostOnly:  opts.IsPostOnly(),
 		},
 	}).SetSize(size).SetPrice(price)
 
+	switch opts.GetTimeInForce() {
+	case model.IOC:
+		order.TimeInForce = "IOC"
+	case model.FOK:
+		order.TimeInForce = "FOK"
+	case model.GTT:
+		return nil, nil, orderNotSupported(self.Name, "GTT")
+	}
+
+	if kind == model.STOP_LIMIT {
+		if side == model.BUY {
+			order.Stop = "entry"
+		} else {
+			order.Stop = "loss"
+		}
+		order.SetStopPrice(opts.StopPrice)
+	}
+
 	var saved *gdax.Order
 	if saved, err = gdaxClient.CreateOrder(order); err != nil {
 		return nil, nil, errors.Wrap(err, 1)
@@ -624,13 +696,14 @@ func (self *Gdax) Order(
 	return []byte(saved.ID), out, nil
 }
 
-func (self *Gdax) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *Gdax) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	var err error
 
 	gdaxClient, ok := client.(*gdax.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	gdaxClient = gdaxClient.WithContext(ctx)
 
 	order := (&gdax.Order{
 		Order: &exchange.Order{
@@ -643,7 +716,7 @@ func (self *Gdax) StopLoss(client interface{}, market string, size float64, pric
 
 	if kind == model.LIMIT {
 		var prec int
-		if prec, err = self.GetPricePrec(client, order.ProductID); err != nil {
+		if prec, err = self.GetPricePrec(ctx, client, order.ProductID); err != nil {
 			return nil, err
 		}
 		limit := price
@@ -669,15 +742,51 @@ func (self *Gdax) StopLoss(client interface{}, market string, size float64, pric
 	return out, nil
 }
 
-func (self *Gdax) OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *Gdax) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *Gdax) GetClosed(client interface{}, market string) (model.Orders, error) {
+func gdaxOrder(order *gdax.Order) model.Order {
+	out := model.Order{
+		ID:            order.ID,
+		ClientOrderID: order.ClientOID,
+		Side:          model.NewOrderSide(order.Side),
+		Type:          model.NewOrderType(order.Type),
+		Market:        order.ProductID,
+		Size:          order.GetSize(),
+		Price:         order.GetPrice(),
+		Filled:        gdax.ParseFloat(order.FilledSize),
+		Fee:           gdax.ParseFloat(order.FillFees),
+		FeeAsset:      order.ProductID[strings.Index(order.ProductID, "-")+1:], // the fees are always in quote currency
+		CreatedAt:     order.CreatedAt.Time(),
+	}
+	if out.Type == model.LIMIT && order.Stop != "" {
+		out.Type = model.STOP_LIMIT
+	}
+	if out.Filled > 0 {
+		out.AvgPrice = gdax.ParseFloat(order.ExecutedValue) / out.Filled
+	}
+	switch order.Status {
+	case "done":
+		if order.DoneReason == "filled" {
+			out.Status = model.FILLED
+		} else {
+			out.Status = model.CANCELLED
+		}
+	case "rejected":
+		out.Status = model.REJECTED
+	default:
+		out.Status = model.GetOrderStatus(out.Size, out.Filled, false)
+	}
+	return out
+}
+
+func (self *Gdax) GetClosed(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	gdaxClient, ok := client.(*gdax.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	gdaxClient = gdaxClient.WithContext(ctx)
 
 	cursor := gdaxClient.ListFills(exchange.ListFillsParams{
 		ProductID: market,
@@ -688,16 +797,25 @@ func (self *Gdax) GetClosed(client interface{}, market string) (model.Orders, er
 		out   model.Orders
 		fills []exchange.Fill
 	)
+	// the fees are always in quote currency
+	quote := market[strings.Index(market, "-")+1:]
 	for cursor.HasMore {
 		if err = cursor.NextPage(&fills); err != nil {
 			return nil, errors.Wrap(err, 1)
 		}
 		for _, fill := range fills {
 			out = append(out, model.Order{
+				ID:        fill.FillID,
 				Side:      model.NewOrderSide(fill.Side),
+				Type:      model.LIMIT,
+				Status:    model.FILLED,
 				Market:    fill.ProductID,
 				Size:      gdax.ParseFloat(fill.Size),
 				Price:     gdax.ParseFloat(fill.Price),
+				Filled:    gdax.ParseFloat(fill.Size),
+				AvgPrice:  gdax.ParseFloat(fill.Price),
+				Fee:       gdax.ParseFloat(fill.Fee),
+				FeeAsset:  quote,
 				CreatedAt: fill.CreatedAt.Time(),
 			})
 		}
@@ -706,11 +824,12 @@ func (self *Gdax) GetClosed(client interface{}, market string) (model.Orders, er
 	return out, nil
 }
 
-func (self *Gdax) GetOpened(client interface{}, market string) (model.Orders, error) {
+func (self *Gdax) GetOpened(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	gdaxClient, ok := client.(*gdax.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	gdaxClient = gdaxClient.WithContext(ctx)
 
 	cursor := gdaxClient.ListOrders(exchange.ListOrdersParams{
 		Status: "open",
@@ -727,13 +846,7 @@ func (self *Gdax) GetOpened(client interface{}, market string) (model.Orders, er
 		}
 		for _, order := range orders {
 			if order.ProductID == market {
-				out = append(out, model.Order{
-					Side:      model.NewOrderSide(order.Side),
-					Market:    order.ProductID,
-					Size:      order.GetSize(),
-					Price:     order.GetPrice(),
-					CreatedAt: order.CreatedAt.Time(),
-				})
+				out = append(out, gdaxOrder(&order))
 			}
 		}
 	}
@@ -741,11 +854,71 @@ func (self *Gdax) GetOpened(client interface{}, market string) (model.Orders, er
 	return out, nil
 }
 
-func (self *Gdax) GetBook(client interface{}, market string, side model.BookSide) (interface{}, error) {
+func (self *Gdax) GetOrder(ctx context.Context, client model.Client, market string, id string) (*model.Order, error) {
 	gdaxClient, ok := client.(*gdax.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	gdaxClient = gdaxClient.WithContext(ctx)
+
+	order, err := gdaxClient.GetOrder(id)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	out := gdaxOrder(order)
+	return &out, nil
+}
+
+func (self *Gdax) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (model.Fills, error) {
+	gdaxClient, ok := client.(*gdax.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	gdaxClient = gdaxClient.WithContext(ctx)
+
+	cursor := gdaxClient.ListFills(exchange.ListFillsParams{
+		ProductID: market,
+	})
+
+	var (
+		err   error
+		out   model.Fills
+		fills []exchange.Fill
+	)
+	// the fees are always in quote currency
+	quote := market[strings.Index(market, "-")+1:]
+	for cursor.HasMore {
+		if err = cursor.NextPage(&fills); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		for _, fill := range fills {
+			if fill.CreatedAt.Time().Before(since) {
+				continue
+			}
+			out = append(out, model.Fill{
+				ID:        strconv.Itoa(fill.TradeID),
+				OrderID:   fill.FillID,
+				Side:      model.NewOrderSide(fill.Side),
+				Market:    fill.ProductID,
+				Price:     gdax.ParseFloat(fill.Price),
+				Size:      gdax.ParseFloat(fill.Size),
+				Fee:       gdax.ParseFloat(fill.Fee),
+				FeeAsset:  quote,
+				CreatedAt: fill.CreatedAt.Time(),
+			})
+		}
+	}
+
+	return out, nil
+}
+
+func (self *Gdax) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (interface{}, error) {
+	gdaxClient, ok := client.(*gdax.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	gdaxClient = gdaxClient.WithContext(ctx)
 
 	var (
 		err  error
@@ -765,13 +938,13 @@ func (self *Gdax) GetBook(client interface{}, market string, side model.BookSide
 	return out, nil
 }
 
-func (self *Gdax) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *Gdax) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]exchange.BookEntry)
 	if !ok {
 		return nil, errors.New("invalid argument: book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -796,11 +969,12 @@ func (self *Gdax) Aggregate(client, book interface{}, market string, agg float64
 	return out, nil
 }
 
-func (self *Gdax) GetTicker(client interface{}, market string) (float64, error) {
+func (self *Gdax) GetTicker(ctx context.Context, client model.Client, market string) (float64, error) {
 	gdaxClient, ok := client.(*gdax.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	gdaxClient = gdaxClient.WithContext(ctx)
 
 	var (
 		err    error
@@ -813,11 +987,50 @@ func (self *Gdax) GetTicker(client interface{}, market string) (float64, error)
 	return gdax.ParseFloat(ticker.Price), nil
 }
 
-func (self *Gdax) Get24h(client interface{}, market string) (*model.Stats, error) {
+func (self *Gdax) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
 	gdaxClient, ok := client.(*gdax.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	gdaxClient = gdaxClient.WithContext(ctx)
+
+	// granularity must be one of 60, 300, 900, 3600, 21600, 86400 seconds
+	switch interval {
+	case model.CANDLE_15M, model.CANDLE_1H, model.CANDLE_1D:
+		// we are OK
+	default:
+		return nil, errors.Errorf("interval %v is not supported", interval)
+	}
+
+	rates, err := gdaxClient.GetHistoricRates(market, exchange.GetHistoricRatesParams{
+		Granularity: int(interval.Seconds()),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	// coinbase returns the newest candle first
+	var out model.Candles
+	for i := len(rates) - 1; i >= 0; i-- {
+		out = append(out, model.Candle{
+			Time:   rates[i].Time,
+			Open:   rates[i].Open,
+			High:   rates[i].High,
+			Low:    rates[i].Low,
+			Close:  rates[i].Close,
+			Volume: rates[i].Volume,
+		})
+	}
+
+	return out, nil
+}
+
+func (self *Gdax) Get24h(ctx context.Context, client model.Client, market string) (*model.Stats, error) {
+	gdaxClient, ok := client.(*gdax.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	gdaxClient = gdaxClient.WithContext(ctx)
 
 	var (
 		err       error
@@ -832,7 +1045,7 @@ func (self *Gdax) Get24h(client interface{}, market string) (*model.Stats, error
 		High:   gdax.ParseFloat(gdaxStats.High),
 		Low:    gdax.ParseFloat(gdaxStats.Low),
 		BtcVolume: func(stats1 *exchange.Stats) float64 {
-			products, err := self.getProducts(gdaxClient, true)
+			products, err := self.getProducts(ctx, gdaxClient, true)
 			if err == nil {
 				for _, product := range products {
 					if product.ID == market {
@@ -851,8 +1064,53 @@ func (self *Gdax) Get24h(client interface{}, market string) (*model.Stats, error
 	}, nil
 }
 
-func (self *Gdax) GetPricePrec(client interface{}, market string) (int, error) {
-	products, err := self.getProducts(client, true)
+func (self *Gdax) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	gdaxClient, ok := client.(*gdax.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	gdaxClient = gdaxClient.WithContext(ctx)
+	fees, err := gdaxClient.GetFees()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Fees
+	if out.Maker, err = strconv.ParseFloat(fees.MakerFeeRate, 64); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	if out.Taker, err = strconv.ParseFloat(fees.TakerFeeRate, 64); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	return &out, nil
+}
+
+func (self *Gdax) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	gdaxClient, ok := client.(*gdax.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	gdaxClient = gdaxClient.WithContext(ctx)
+	accounts, err := gdaxClient.GetAccounts()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Balances
+	for _, account := range accounts {
+		total := gdax.ParseFloat(account.Balance)
+		if total > 0 {
+			out = append(out, model.Balance{
+				Asset:     account.Currency,
+				Total:     total,
+				Available: gdax.ParseFloat(account.Available),
+				Locked:    gdax.ParseFloat(account.Hold),
+			})
+		}
+	}
+	return out, nil
+}
+
+func (self *Gdax) GetPricePrec(ctx context.Context, client model.Client, market string) (int, error) {
+	products, err := self.getProducts(ctx, client, true)
 	if err != nil {
 		return 8, err
 	}
@@ -864,8 +1122,8 @@ func (self *Gdax) GetPricePrec(client interface{}, market string) (int, error) {
 	return 8, errors.Errorf("market %s not found", market)
 }
 
-func (self *Gdax) GetSizePrec(client interface{}, market string) (int, error) {
-	products, err := self.getProducts(client, true)
+func (self *Gdax) GetSizePrec(ctx context.Context, client model.Client, market string) (int, error) {
+	products, err := self.getProducts(ctx, client, true)
 	if err != nil {
 		return 0, err
 	}
@@ -877,11 +1135,11 @@ func (self *Gdax) GetSizePrec(client interface{}, market string) (int, error) {
 	return 0, errors.Errorf("market %s not found", market)
 }
 
-func (self *Gdax) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+func (self *Gdax) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
 	market := self.FormatMarket(base, quote)
 
-	out := model.GetSizeMax(hold, earn, def, mult, func() int {
-		prec, err := self.GetSizePrec(client, market)
+	out := model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, self.FormatMarket(base, quote)), func() int {
+		prec, err := self.GetSizePrec(ctx, client, market)
 		if err != nil {
 			return 0
 		}
@@ -891,7 +1149,7 @@ func (self *Gdax) GetMaxSize(client interface{}, base, quote string, hold, earn
 	if hold {
 		gdaxClient, ok := client.(*gdax.Client)
 		if ok {
-			min, err := self.getMinOrderSize(gdaxClient, market)
+			min, err := self.getMinOrderSize(ctx, gdaxClient, market)
 			if err == nil {
 				if min > out {
 					out = min
@@ -903,11 +1161,12 @@ func (self *Gdax) GetMaxSize(client interface{}, base, quote string, hold, earn
 	return out
 }
 
-func (self *Gdax) Cancel(client interface{}, market string, side model.OrderSide) error {
+func (self *Gdax) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
 	gdaxClient, ok := client.(*gdax.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	gdaxClient = gdaxClient.WithContext(ctx)
 
 	cursor := gdaxClient.ListOrders(exchange.ListOrdersParams{
 		Status: "open",
@@ -935,13 +1194,26 @@ func (self *Gdax) Cancel(client interface{}, market string, side model.OrderSide
 	return nil
 }
 
-func (self *Gdax) Buy(client interface{}, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
+func (self *Gdax) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	gdaxClient, ok := client.(*gdax.Client)
+	if !ok {
+		return errors.New("invalid argument: client")
+	}
+	gdaxClient = gdaxClient.WithContext(ctx)
+	if err := gdaxClient.CancelOrder(id); err != nil {
+		return errors.Wrap(err, 1)
+	}
+	return nil
+}
+
+func (self *Gdax) Buy(ctx context.Context, client model.Client, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	var err error
 
 	gdaxClient, ok := client.(*gdax.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	gdaxClient = gdaxClient.WithContext(ctx)
 
 	// step #1: delete the buy order(s) that are open in your book
 	if cancel {
@@ -976,7 +1248,7 @@ func (self *Gdax) Buy(client interface{}, cancel bool, market string, calls mode
 		if !call.Skip {
 			limit := call.Price
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
 			order := (&gdax.Order{
 				Order: &exchange.Order{
@@ -1003,7 +1275,7 @@ func (self *Gdax) IsLeveragedToken(name string) bool {
 	return false
 }
 
-func (self *Gdax) HasAlgoOrder(client interface{}, market string) (bool, error) {
+func (self *Gdax) HasAlgoOrder(ctx context.Context, client model.Client, market string) (bool, error) {
 	return false, nil
 }
 
@@ -1032,13 +1304,13 @@ type gdaxSubscribePublic struct {
 	Channels   []string `json:"channels"`
 }
 
-func (self *Gdax) newSubscribePublic(sandbox bool) (*gdaxSubscribePublic, error) {
+func (self *Gdax) newSubscribePublic(ctx context.Context, sandbox bool) (*gdaxSubscribePublic, error) {
 	out := gdaxSubscribePublic{
 		Type:       "subscribe",
 		ProductIDs: []string{},
 		Channels:   []string{"user", "heartbeat"},
 	}
-	products, err := self.getProducts(gdax.New(sandbox), false)
+	products, err := self.getProducts(ctx, gdax.New(sandbox), false)
 	if err != nil {
 		return nil, err
 	}
@@ -1056,7 +1328,7 @@ type gdaxSubscribePrivate struct {
 	Timestamp  string `json:"timestamp"`
 }
 
-func (self *Gdax) newSubscribePrivate(secret, key, passphrase string, sandbox bool) (*gdaxSubscribePrivate, error) {
+func (self *Gdax) newSubscribePrivate(ctx context.Context, secret, key, passphrase string, sandbox bool) (*gdaxSubscribePrivate, error) {
 	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
 
 	message := fmt.Sprintf("%s%s%s%s", timestamp, "GET", "/users/self/verify", "")
@@ -1066,7 +1338,7 @@ func (self *Gdax) newSubscribePrivate(secret, key, passphrase string, sandbox bo
 		return nil, err
 	}
 
-	public, err := self.newSubscribePublic(sandbox)
+	public, err := self.newSubscribePublic(ctx, sandbox)
 	if err != nil {
 		return nil, err
 	}
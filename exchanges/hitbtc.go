@@ -2,6 +2,7 @@
 package exchanges
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -11,6 +12,7 @@ import (
 
 	filemutex "github.com/alexflint/go-filemutex"
 	"github.com/svanas/nefertiti/aggregation"
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	exchange "github.com/svanas/nefertiti/hitbtc"
@@ -21,6 +23,7 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
+	"github.com/svanas/nefertiti/shutdown"
 	"github.com/svanas/nefertiti/uuid"
 )
 
@@ -103,21 +106,64 @@ func (trades hitbtcTrades) indexByOrderId(id uint64) int {
 	return -1
 }
 
+// hitbtcCodes maps the HitBTC error messages to our error classes
+var hitbtcCodes = errors.Codes{
+	{Code: "Insufficient funds", Class: errors.INSUFFICIENT_FUNDS},
+	{Code: "Quantity too low", Class: errors.INVALID_SIZE},
+	{Code: "Too many requests", Class: errors.RATE_LIMITED},
+	{Code: "Authorization required", Class: errors.AUTH},
+	{Code: "Authorization failed", Class: errors.AUTH},
+	{Code: "Authorisation failed", Class: errors.AUTH},
+}
+
 type HitBTC struct {
 	*model.ExchangeInfo
 	symbols   []exchange.Symbol
 	updatedAt time.Time // the last time we pulled symbols
 }
 
+const hitbtcPartnerId = "refzzz18"
+
 // We can track API requests using ClientOrderID field. The string has 32 symbols,
 // we suggest to use the first 8 symbols as a unique partner ID which we assign to
 // you. Other 24 symbols are a unique order ID generated on your end.
 func (self *HitBTC) getUniquePartnerId() string {
 	out := uuid.New().LongEx("")
-	out = "refzzz18" + out[8:]
+	out = hitbtcPartnerId + out[len(hitbtcPartnerId):]
 	return out
 }
 
+// getClientOrderId returns a ClientOrderID that starts with our unique partner ID. If metadata is not empty, then the
+// ClientOrderID includes the metadata (for example: the price we paid for the base asset of a sell order).
+func (self *HitBTC) getClientOrderId(metadata string) string {
+	out := self.getUniquePartnerId()
+	if metadata != "" {
+		prefix := fmt.Sprintf("%s-%s-", out[:8], strings.Replace(metadata, ".", "_", -1))
+		if len(prefix) <= len(out)-8 {
+			return prefix + out[len(prefix):]
+		}
+	}
+	return out
+}
+
+// hitbtcOrderBot returns true if the order was opened by one of our bots
+func hitbtcOrderBot(clientOrderId string) bool {
+	return strings.HasPrefix(clientOrderId, hitbtcPartnerId)
+}
+
+// hitbtcOrderBought returns the price we paid for the base asset of a sell order, or zero if the ClientOrderID doesn't tell us
+func hitbtcOrderBought(side model.OrderSide, clientOrderId string) float64 {
+	if side == model.SELL {
+		subs := strings.Split(clientOrderId, "-")
+		if len(subs) >= 3 && subs[0] == hitbtcPartnerId {
+			if out, err := strconv.ParseFloat(strings.Replace(subs[1], "_", ".", -1), 64); err == nil {
+				return out
+			}
+		}
+	}
+	return 0
+}
+
 func (self *HitBTC) getSymbol(client *exchange.HitBtc, name string) (*exchange.Symbol, error) {
 	cached := true
 	for {
@@ -173,7 +219,22 @@ func (self *HitBTC) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
-func (self *HitBTC) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *HitBTC) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        false,
+		MarketOrders:     true,
+		MarketBuyByQuote: false,
+		PostOnly:         false,
+		StopLoss:         true,
+		StopLossStrategy: false,
+		OCO:              false,
+		Trailing:         false,
+		BotOrders:        true,
+	}
+}
+
+func (self *HitBTC) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	if permission != model.PRIVATE {
 		return exchange.New("", ""), nil
 	}
@@ -186,10 +247,10 @@ func (self *HitBTC) GetClient(permission model.Permission, sandbox bool) (interf
 	return exchange.New(apiKey, apiSecret), nil
 }
 
-func (self *HitBTC) GetMarkets(cached, sandbox bool, ignore []string) ([]model.Market, error) {
+func (self *HitBTC) GetMarkets(ctx context.Context, cached, sandbox bool, ignore []string) ([]model.Market, error) {
 	var out []model.Market
 
-	client := exchange.New("", "")
+	client := exchange.New("", "").WithContext(ctx)
 
 	symbols, err := self.getSymbols(client, cached)
 	if err != nil {
@@ -279,11 +340,13 @@ func (self *HitBTC) listen(
 
 // listens to the filled orders, look for newly filled orders, automatically place new sell orders.
 func (self *HitBTC) sell(
+	ctx context.Context,
 	client *exchange.HitBtc,
 	strategy model.Strategy,
 	mult multiplier.Mult,
 	hold, earn model.Markets,
 	service model.Notify,
+	values *fillValuer,
 	twitter *notify.TwitterKeys,
 	level int64,
 	old hitbtcTrades,
@@ -293,7 +356,7 @@ func (self *HitBTC) sell(
 
 	// get the markets
 	var markets []model.Market
-	if markets, err = self.GetMarkets(true, sandbox, nil); err != nil {
+	if markets, err = self.GetMarkets(ctx, true, sandbox, nil); err != nil {
 		return old, err
 	}
 
@@ -315,7 +378,7 @@ func (self *HitBTC) sell(
 
 			if notify.CanSend(level, notify.FILLED) {
 				if service != nil {
-					if err = service.SendMessage(trade, fmt.Sprintf("HitBTC - Done %s (Reason: Filled)", strings.Title(trade.Side)), model.ALWAYS); err != nil {
+					if err = service.SendMessage(trade, fmt.Sprintf("HitBTC - Done %s (Reason: Filled)%s", strings.Title(trade.Side), values.value(ctx, trade.Symbol, trade.Quantity, trade.Price)), model.ALWAYS); err != nil {
 						log.Printf("[ERROR] %v", err)
 					}
 				}
@@ -346,7 +409,7 @@ func (self *HitBTC) sell(
 
 				price := new[i].Price
 				if price == 0 {
-					if price, err = self.GetTicker(client, new[i].Symbol); err != nil {
+					if price, err = self.GetTicker(ctx, client, new[i].Symbol); err != nil {
 						return new, err
 					}
 				}
@@ -358,16 +421,18 @@ func (self *HitBTC) sell(
 				)
 				base, quote, err = model.ParseMarket(markets, new[i].Symbol)
 				if err == nil {
-					qty = self.GetMaxSize(client, base, quote, hold.HasMarket(new[i].Symbol), earn.HasMarket(new[i].Symbol), qty, mult)
+					fees := model.GetFees(ctx, self, client, new[i].Symbol)
+					qty = self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(new[i].Symbol), earn.HasMarket(new[i].Symbol), qty, fees.Mult(mult))
 					if qty > 0 {
 						var prec int
-						if prec, err = self.GetPricePrec(client, new[i].Symbol); err == nil {
-							_, _, err = self.Order(client,
+						if prec, err = self.GetPricePrec(ctx, client, new[i].Symbol); err == nil {
+							_, _, err = self.Order(ctx, client,
 								model.SELL,
 								new[i].Symbol,
 								qty,
-								pricing.Multiply(price, mult, prec),
+								pricing.Multiply(price, fees.Mult(mult), prec),
 								model.LIMIT,
+								nil,
 								strconv.FormatFloat(price, 'f', -1, 64),
 							)
 						}
@@ -391,6 +456,7 @@ func (self *HitBTC) sell(
 }
 
 func (self *HitBTC) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -423,7 +489,8 @@ func (self *HitBTC) Sell(
 		}
 	}
 
-	client := exchange.New(apiKey, apiSecret)
+	client := exchange.New(apiKey, apiSecret).WithContext(ctx)
+	values := newFillValuer(self, client)
 
 	// get my filled orders
 	var filled []exchange.Trade
@@ -441,7 +508,10 @@ func (self *HitBTC) Sell(
 		return err
 	}
 
-	for {
+	for !shutdown.Stopping() {
+		if !breaker.Get(self.Name).Wait() {
+			break
+		}
 		// read the dynamic settings
 		var (
 			level int64 = notify.LEVEL_DEFAULT
@@ -453,45 +523,73 @@ func (self *HitBTC) Sell(
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listens to the filled orders, look for newly filled orders, automatically place new sell orders.
-		if filled, err = self.sell(client, strategy, mult, hold, earn, service, twitter, level, filled, sandbox); err != nil {
+		if filled, err = self.sell(ctx, client, strategy, mult, hold, earn, service, values, twitter, level, filled, sandbox); err != nil {
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listens to the open orders, look for cancelled orders, send a notification.
 		if opened, err = self.listen(client, service, level, opened, filled); err != nil {
 			logger.Error(self.Name, err, level, service)
 		}
+		breaker.Get(self.Name).Done(err, service, level)
 	}
+
+	return nil
 }
 
 func (self *HitBTC) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return nil, nil, errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
+
+	if err = opts.Validate(kind); err != nil {
+		return nil, nil, err
+	}
+	if opts.IsPostOnly() {
+		return nil, nil, orderNotSupported(self.Name, "post-only")
+	}
+
+	timeInForce := exchange.GTC
+	switch opts.GetTimeInForce() {
+	case model.IOC:
+		timeInForce = exchange.IOC
+	case model.FOK:
+		timeInForce = exchange.FOK
+	case model.GTT:
+		return nil, nil, orderNotSupported(self.Name, "GTT")
+	}
 
 	var order exchange.Order
-	if kind == model.LIMIT {
+	if kind == model.LIMIT || kind == model.STOP_LIMIT {
 		order, err = hitbtc.PlaceOrder(
-			self.getUniquePartnerId(),
+			self.getClientOrderId(metadata),
 			market,
 			model.OrderSideString[side],
-			exchange.ORDER_TYPE_LIMIT,
-			exchange.GTC,
+			func() string {
+				if kind == model.STOP_LIMIT {
+					return exchange.ORDER_TYPE_STOP_LIMIT
+				}
+				return exchange.ORDER_TYPE_LIMIT
+			}(),
+			timeInForce,
 			size,
 			price,
-			0,
+			opts.GetStopPrice(),
 		)
 	} else {
 		order, err = hitbtc.PlaceOrder(
-			self.getUniquePartnerId(),
+			self.getClientOrderId(metadata),
 			market,
 			model.OrderSideString[side],
 			exchange.ORDER_TYPE_MARKET,
@@ -513,18 +611,19 @@ func (self *HitBTC) Order(
 	return []byte(order.ClientOrderId), out, nil
 }
 
-func (self *HitBTC) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *HitBTC) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	var err error
 
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
 
 	var order exchange.Order
 	if kind == model.LIMIT {
 		order, err = hitbtc.PlaceOrder(
-			self.getUniquePartnerId(),
+			self.getClientOrderId(metadata),
 			market,
 			model.OrderSideString[model.SELL],
 			exchange.ORDER_TYPE_STOP_LIMIT,
@@ -535,7 +634,7 @@ func (self *HitBTC) StopLoss(client interface{}, market string, size float64, pr
 		)
 	} else {
 		order, err = hitbtc.PlaceOrder(
-			self.getUniquePartnerId(),
+			self.getClientOrderId(metadata),
 			market,
 			model.OrderSideString[model.SELL],
 			exchange.ORDER_TYPE_STOP_MARKET,
@@ -557,43 +656,97 @@ func (self *HitBTC) StopLoss(client interface{}, market string, size float64, pr
 	return out, nil
 }
 
-func (self *HitBTC) OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *HitBTC) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	return nil, errors.New("Not implemented")
 }
 
-func (self *HitBTC) GetClosed(client interface{}, market string) (model.Orders, error) {
+func (self *HitBTC) toOrder(order *exchange.Order) model.Order {
+	out := model.Order{
+		ID:            strconv.FormatUint(order.Id, 10),
+		ClientOrderID: order.ClientOrderId,
+		Side:          self.getOrderSide(order),
+		Market:        order.Symbol,
+		Size:          order.Quantity,
+		Price:         order.ParsePrice(),
+		Filled:        order.CumQuantity,
+		CreatedAt:     order.Created,
+	}
+	out.Bought = hitbtcOrderBought(out.Side, order.ClientOrderId)
+	out.Bot = hitbtcOrderBot(order.ClientOrderId)
+	switch order.Type {
+	case exchange.ORDER_TYPE_LIMIT:
+		out.Type = model.LIMIT
+	case exchange.ORDER_TYPE_MARKET, exchange.ORDER_TYPE_STOP_MARKET:
+		out.Type = model.MARKET
+	case exchange.ORDER_TYPE_STOP_LIMIT:
+		out.Type = model.STOP_LIMIT
+	}
+	switch order.Status {
+	case exchange.ORDER_STATUS_NEW, exchange.ORDER_STATUS_SUSPENDED:
+		out.Status = model.OPEN
+	case exchange.ORDER_STATUS_PARTIALLY_FILLED:
+		out.Status = model.PARTIALLY_FILLED
+	case exchange.ORDER_STATUS_FILLED:
+		out.Status = model.FILLED
+	case exchange.ORDER_STATUS_CANCELED:
+		out.Status = model.CANCELLED
+	case exchange.ORDER_STATUS_EXPIRED:
+		out.Status = model.EXPIRED
+	}
+	return out
+}
+
+func (self *HitBTC) GetClosed(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
+
+	var symbol *exchange.Symbol
+	if symbol, err = self.getSymbol(hitbtc, market); err != nil {
+		return nil, err
+	}
 
 	var trades []exchange.Trade
 	if trades, err = hitbtc.GetTrades(market); err != nil {
 		return nil, errors.Wrap(err, 1)
 	}
 
+	// every trade is a fill
 	var out model.Orders
 	for _, trade := range trades {
 		out = append(out, model.Order{
-			Side:   self.getTradeSide(&trade),
-			Market: trade.Symbol,
-			Size:   trade.Quantity,
-			Price:  trade.Price,
+			ID:            strconv.FormatUint(trade.OrderId, 10),
+			ClientOrderID: trade.ClientOrderId,
+			Side:          self.getTradeSide(&trade),
+			Status:        model.FILLED,
+			Market:        trade.Symbol,
+			Size:          trade.Quantity,
+			Price:         trade.Price,
+			Filled:        trade.Quantity,
+			AvgPrice:      trade.Price,
+			Fee:           trade.Fee,
+			FeeAsset:      symbol.FeeCurrency,
+			CreatedAt:     trade.Timestamp,
+			Bought:        hitbtcOrderBought(self.getTradeSide(&trade), trade.ClientOrderId),
+			Bot:           hitbtcOrderBot(trade.ClientOrderId),
 		})
 	}
 
 	return out, nil
 }
 
-func (self *HitBTC) GetOpened(client interface{}, market string) (model.Orders, error) {
+func (self *HitBTC) GetOpened(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var err error
 
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
 
 	var orders []exchange.Order
 	if orders, err = hitbtc.GetOpenOrders(market); err != nil {
@@ -602,25 +755,120 @@ func (self *HitBTC) GetOpened(client interface{}, market string) (model.Orders,
 
 	var out model.Orders
 	for _, order := range orders {
-		out = append(out, model.Order{
-			Side:      self.getOrderSide(&order),
-			Market:    order.Symbol,
-			Size:      order.Quantity,
-			Price:     order.ParsePrice(),
-			CreatedAt: order.Created,
+		out = append(out, self.toOrder(&order))
+	}
+
+	return out, nil
+}
+
+// GetOrder returns an order by its client order ID (that is what Order returns) or by its order ID
+func (self *HitBTC) GetOrder(ctx context.Context, client model.Client, market string, id string) (*model.Order, error) {
+	var err error
+
+	hitbtc, ok := client.(*exchange.HitBtc)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	hitbtc = hitbtc.WithContext(ctx)
+
+	var order *exchange.Order
+	if active, err := hitbtc.GetActiveOrder(id); err == nil && active.Id != 0 {
+		order = &active
+	} else {
+		var history []exchange.Order
+		if history, err = hitbtc.GetOrder(id); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		if len(history) == 0 {
+			var all []exchange.Order
+			if all, err = hitbtc.GetOrderHistory(); err != nil {
+				return nil, errors.Wrap(err, 1)
+			}
+			for i := range all {
+				if strconv.FormatUint(all[i].Id, 10) == id {
+					history = all[i : i+1]
+					break
+				}
+			}
+		}
+		if len(history) == 0 {
+			return nil, errors.Errorf("order %s does not exist", id)
+		}
+		order = &history[0]
+	}
+
+	out := self.toOrder(order)
+
+	// the fee is in the trades, not in the order
+	if out.Filled > 0 {
+		var symbol *exchange.Symbol
+		if symbol, err = self.getSymbol(hitbtc, order.Symbol); err != nil {
+			return nil, err
+		}
+		var trades []exchange.Trade
+		if trades, err = hitbtc.GetOrderTrades(order.Id); err != nil {
+			return nil, errors.Wrap(err, 1)
+		}
+		var value float64
+		for _, trade := range trades {
+			out.Fee += trade.Fee
+			value += trade.Price * trade.Quantity
+		}
+		out.FeeAsset = symbol.FeeCurrency
+		if value > 0 {
+			out.AvgPrice = value / out.Filled
+		}
+	}
+
+	return &out, nil
+}
+
+func (self *HitBTC) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (model.Fills, error) {
+	var err error
+
+	hitbtc, ok := client.(*exchange.HitBtc)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	hitbtc = hitbtc.WithContext(ctx)
+
+	var symbol *exchange.Symbol
+	if symbol, err = self.getSymbol(hitbtc, market); err != nil {
+		return nil, err
+	}
+
+	var trades []exchange.Trade
+	if trades, err = hitbtc.GetTradesSince(market, since); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	var out model.Fills
+	for _, trade := range trades {
+		out = append(out, model.Fill{
+			ID:            strconv.FormatUint(trade.Id, 10),
+			OrderID:       strconv.FormatUint(trade.OrderId, 10),
+			ClientOrderID: trade.ClientOrderId,
+			Side:          self.getTradeSide(&trade),
+			Market:        trade.Symbol,
+			Price:         trade.Price,
+			Size:          trade.Quantity,
+			Fee:           trade.Fee,
+			FeeAsset:      symbol.FeeCurrency,
+			CreatedAt:     trade.Timestamp,
 		})
 	}
 
 	return out, nil
 }
 
-func (self *HitBTC) GetBook(client interface{}, market string, side model.BookSide) (interface{}, error) {
+func (self *HitBTC) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (interface{}, error) {
 	var err error
 
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
 
 	var book exchange.Book
 	if book, err = hitbtc.GetOrderBook(market, 0); err != nil {
@@ -637,13 +885,13 @@ func (self *HitBTC) GetBook(client interface{}, market string, side model.BookSi
 	return out, nil
 }
 
-func (self *HitBTC) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *HitBTC) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]exchange.BookEntry)
 	if !ok {
 		return nil, errors.New("invalid argument: book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -667,11 +915,12 @@ func (self *HitBTC) Aggregate(client, book interface{}, market string, agg float
 	return out, nil
 }
 
-func (self *HitBTC) GetTicker(client interface{}, market string) (float64, error) {
+func (self *HitBTC) GetTicker(ctx context.Context, client model.Client, market string) (float64, error) {
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
 
 	ticker, err := hitbtc.GetTicker(market)
 	if err != nil {
@@ -681,11 +930,16 @@ func (self *HitBTC) GetTicker(client interface{}, market string) (float64, error
 	return ticker.Last, nil
 }
 
-func (self *HitBTC) Get24h(client interface{}, market string) (*model.Stats, error) {
+func (self *HitBTC) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (self *HitBTC) Get24h(ctx context.Context, client model.Client, market string) (*model.Stats, error) {
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
 
 	ticker, err := hitbtc.GetTicker(market)
 	if err != nil {
@@ -708,11 +962,52 @@ func (self *HitBTC) Get24h(client interface{}, market string) (*model.Stats, err
 	}, nil
 }
 
-func (self *HitBTC) GetPricePrec(client interface{}, market string) (int, error) {
+func (self *HitBTC) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	hitbtc, ok := client.(*exchange.HitBtc)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	hitbtc = hitbtc.WithContext(ctx)
+	symbol, err := self.getSymbol(hitbtc, market)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Fees{
+		Maker: symbol.ProvideLiquidityRate,
+		Taker: symbol.TakeLiquidityRate,
+	}, nil
+}
+
+func (self *HitBTC) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	hitbtc, ok := client.(*exchange.HitBtc)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	hitbtc = hitbtc.WithContext(ctx)
+	balances, err := hitbtc.GetBalances()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Balances
+	for _, balance := range balances {
+		if balance.Available+balance.Reserved > 0 {
+			out = append(out, model.Balance{
+				Asset:     balance.Currency,
+				Total:     balance.Available + balance.Reserved,
+				Available: balance.Available,
+				Locked:    balance.Reserved,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (self *HitBTC) GetPricePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return 8, errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
 	symbol, err := self.getSymbol(hitbtc, market)
 	if err != nil {
 		return 8, err
@@ -720,11 +1015,12 @@ func (self *HitBTC) GetPricePrec(client interface{}, market string) (int, error)
 	return precision.Parse(strconv.FormatFloat(symbol.TickSize, 'f', -1, 64), 8), nil
 }
 
-func (self *HitBTC) GetSizePrec(client interface{}, market string) (int, error) {
+func (self *HitBTC) GetSizePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
 	symbol, err := self.getSymbol(hitbtc, market)
 	if err != nil {
 		return 0, err
@@ -732,14 +1028,14 @@ func (self *HitBTC) GetSizePrec(client interface{}, market string) (int, error)
 	return precision.Parse(strconv.FormatFloat(symbol.QuantityIncrement, 'f', -1, 64), 0), nil
 }
 
-func (self *HitBTC) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+func (self *HitBTC) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
 	if hold {
 		if base == "HIT" {
 			return 0
 		}
 	}
-	return model.GetSizeMax(hold, earn, def, mult, func() int {
-		prec, err := self.GetSizePrec(client, self.FormatMarket(base, quote))
+	return model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, self.FormatMarket(base, quote)), func() int {
+		prec, err := self.GetSizePrec(ctx, client, self.FormatMarket(base, quote))
 		if err != nil {
 			return 0
 		}
@@ -747,13 +1043,14 @@ func (self *HitBTC) GetMaxSize(client interface{}, base, quote string, hold, ear
 	})
 }
 
-func (self *HitBTC) Cancel(client interface{}, market string, side model.OrderSide) error {
+func (self *HitBTC) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
 	var err error
 
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
 
 	var orders []exchange.Order
 	if orders, err = hitbtc.GetOpenOrders(market); err != nil {
@@ -771,13 +1068,42 @@ func (self *HitBTC) Cancel(client interface{}, market string, side model.OrderSi
 	return nil
 }
 
-func (self *HitBTC) Buy(client interface{}, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
+// CancelOrder cancels an order by (exchange) order ID or by client order ID
+func (self *HitBTC) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	var err error
+
+	hitbtc, ok := client.(*exchange.HitBtc)
+	if !ok {
+		return errors.New("invalid argument: client")
+	}
+	hitbtc = hitbtc.WithContext(ctx)
+
+	// HitBTC cancels orders by client order ID only
+	var orders []exchange.Order
+	if orders, err = hitbtc.GetOpenOrders(market); err != nil {
+		return errors.Wrap(err, 1)
+	}
+
+	for _, order := range orders {
+		if order.ClientOrderId == id || strconv.FormatUint(order.Id, 10) == id {
+			if _, err = hitbtc.CancelClientOrderId(order.ClientOrderId); err != nil {
+				return errors.Wrap(err, 1)
+			}
+			return nil
+		}
+	}
+
+	return errors.Errorf("order %s does not exist", id)
+}
+
+func (self *HitBTC) Buy(ctx context.Context, client model.Client, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	var err error
 
 	hitbtc, ok := client.(*exchange.HitBtc)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	hitbtc = hitbtc.WithContext(ctx)
 
 	// step #1: delete the buy order(s) that are open in your book
 	if cancel {
@@ -806,14 +1132,14 @@ func (self *HitBTC) Buy(client interface{}, cancel bool, market string, calls mo
 		if !call.Skip {
 			limit := call.Price
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
-			_, _, err = self.Order(client,
+			_, _, err = self.Order(ctx, client,
 				model.BUY,
 				market,
 				call.Size,
 				limit,
-				kind, "",
+				kind, nil, "",
 			)
 			if err != nil {
 				return err
@@ -828,7 +1154,7 @@ func (self *HitBTC) IsLeveragedToken(name string) bool {
 	return false
 }
 
-func (self *HitBTC) HasAlgoOrder(client interface{}, market string) (bool, error) {
+func (self *HitBTC) HasAlgoOrder(ctx context.Context, client model.Client, market string) (bool, error) {
 	return false, nil
 }
 
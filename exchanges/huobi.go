@@ -2,6 +2,7 @@
 package exchanges
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -12,6 +13,7 @@ import (
 
 	filemutex "github.com/alexflint/go-filemutex"
 	"github.com/svanas/nefertiti/aggregation"
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	exchange "github.com/svanas/nefertiti/huobi"
@@ -22,6 +24,7 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
+	"github.com/svanas/nefertiti/shutdown"
 )
 
 var (
@@ -77,23 +80,67 @@ func init() {
 	}
 }
 
+// huobiCodes maps the Huobi err-code to our error classes
+var huobiCodes = errors.Codes{
+	{Code: "insufficient", Class: errors.INSUFFICIENT_FUNDS},
+	{Code: "value-min", Class: errors.INVALID_SIZE},
+	{Code: "trade-disabled", Class: errors.MARKET_CLOSED},
+	{Code: "api-signature", Class: errors.AUTH},
+	{Code: "login-required", Class: errors.AUTH},
+	// the generic codes go last
+	{Code: "price", Class: errors.INVALID_PRICE},
+	{Code: "amount", Class: errors.INVALID_SIZE},
+}
+
+// huobiClassify maps the Huobi error codes to our error classes
+func huobiClassify(err error) errors.Class {
+	if resp, ok := err.(*exchange.Response); ok {
+		return huobiCodes.Match(resp.ErrCode)
+	}
+	return errors.UNCLASSIFIED
+}
+
 type Huobi struct {
 	*model.ExchangeInfo
-	symbols []exchange.Symbol
+	symbols    []exchange.Symbol
+	allSymbols []exchange.Symbol // including the offline symbols, and the symbols outside of the --partition
 }
 
+const huobiBroker = "AAf68ef084"
+
 func (self *Huobi) getBrokerId() string {
-	const (
-		MAX_LEN = 54
-		BROKER  = "AAf68ef084"
-	)
-	out := BROKER
+	const MAX_LEN = 54
+	out := huobiBroker
 	for len(out) < MAX_LEN {
 		out += strconv.Itoa(rand.Intn(10))
 	}
 	return out
 }
 
+// getClientOrderId returns a client order ID that starts with our broker ID. If metadata is not empty, then the
+// client order ID includes the metadata (for example: the price we paid for the base asset of a sell order).
+func (self *Huobi) getClientOrderId(metadata string) string {
+	if metadata != "" {
+		return huobiBroker + "-" + metadata
+	}
+	return self.getBrokerId()
+}
+
+// huobiOrderBot returns true if the order was opened by one of our bots
+func huobiOrderBot(clientOrderId string) bool {
+	return strings.HasPrefix(clientOrderId, huobiBroker)
+}
+
+// huobiOrderBought returns the price we paid for the base asset of a sell order, or zero if the client order ID doesn't tell us
+func huobiOrderBought(side model.OrderSide, clientOrderId string) float64 {
+	if side == model.SELL {
+		if out, err := strconv.ParseFloat(strings.TrimPrefix(clientOrderId, huobiBroker+"-"), 64); err == nil {
+			return out
+		}
+	}
+	return 0
+}
+
 func (self *Huobi) getBaseURL(sandbox bool) string {
 	return self.ExchangeInfo.REST.URI
 }
@@ -115,6 +162,7 @@ func (self *Huobi) getSymbols(client *exchange.Client, cached bool) ([]exchange.
 		}
 
 		self.symbols = nil
+		self.allSymbols = symbols
 		partition := flag.Get("partition")
 
 		for _, symbol := range symbols {
@@ -152,12 +200,21 @@ func (self *Huobi) getSymbolsEx(client *exchange.Client, quotes []string, cached
 }
 
 func (self *Huobi) getSymbol(client *exchange.Client, market string) (*exchange.Symbol, error) {
+	return self.findSymbol(client, market, false)
+}
+
+// findSymbol returns a symbol. If all is true, then the offline symbols and the symbols outside of the --partition
+// are included, so we can still read our orders in those markets.
+func (self *Huobi) findSymbol(client *exchange.Client, market string, all bool) (*exchange.Symbol, error) {
 	cached := true
 	for {
 		symbols, err := self.getSymbols(client, cached)
 		if err != nil {
 			return nil, err
 		}
+		if all {
+			symbols = self.allSymbols
+		}
 		for _, symbol := range symbols {
 			if symbol.Symbol == market {
 				return &symbol, nil
@@ -183,7 +240,23 @@ func (self *Huobi) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
-func (self *Huobi) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *Huobi) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        false,
+		MarketOrders:     true,
+		MarketBuyByQuote: false,
+		PostOnly:         true,
+		StopLoss:         false,
+		StopLossStrategy: false,
+		OCO:              false,
+		Trailing:         false,
+		BotOrders:        true,
+		Candles:          []time.Duration{model.CANDLE_15M, model.CANDLE_1H, model.CANDLE_4H, model.CANDLE_1D},
+	}
+}
+
+func (self *Huobi) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	if permission != model.PRIVATE {
 		return exchange.New(self.getBaseURL(sandbox), "", ""), nil
 	}
@@ -200,10 +273,10 @@ func (self *Huobi) GetClient(permission model.Permission, sandbox bool) (interfa
 	return exchange.New(self.getBaseURL(sandbox), apiKey, apiSecret), nil
 }
 
-func (self *Huobi) GetMarkets(cached, sandbox bool, blacklist []string) ([]model.Market, error) {
+func (self *Huobi) GetMarkets(ctx context.Context, cached, sandbox bool, blacklist []string) ([]model.Market, error) {
 	var out []model.Market
 
-	symbols, err := self.getSymbols(exchange.New(self.getBaseURL(sandbox), "", ""), cached)
+	symbols, err := self.getSymbols(exchange.New(self.getBaseURL(sandbox), "", "").WithContext(ctx), cached)
 	if err != nil {
 		return nil, err
 	}
@@ -306,11 +379,13 @@ func (self *Huobi) listen(
 
 // listen to the filled orders, look for newly filled orders, automatically place new LIMIT SELL orders.
 func (self *Huobi) sell(
+	ctx context.Context,
 	client *exchange.Client,
 	quotes []string,
 	mult multiplier.Mult,
 	hold, earn model.Markets,
 	service model.Notify,
+	values *fillValuer,
 	level int64,
 	old []exchange.Order,
 ) ([]exchange.Order, error) {
@@ -345,7 +420,7 @@ func (self *Huobi) sell(
 		} else {
 			log.Println("[FILLED] " + string(data))
 			if notify.CanSend(level, notify.FILLED) && service != nil {
-				err := service.SendMessage(order, fmt.Sprintf("Huobi - Done %v (Reason: Filled)", order.Side()), model.ALWAYS)
+				err := service.SendMessage(order, fmt.Sprintf("Huobi - Done %v (Reason: Filled)%s", order.Side(), values.value(ctx, order.Symbol, order.FilledAmount, order.Price)), model.ALWAYS)
 				if err != nil {
 					log.Printf("[ERROR] %v", err)
 				}
@@ -381,7 +456,7 @@ func (self *Huobi) sell(
 				}
 			}
 
-			prec, err := self.GetSizePrec(client, new[i].Symbol)
+			prec, err := self.GetSizePrec(ctx, client, new[i].Symbol)
 			if err == nil {
 				qty = precision.Floor(qty, prec)
 				// get base currency and desired size, calculate price, place sell order
@@ -391,15 +466,16 @@ func (self *Huobi) sell(
 				)
 				base, quote, err = self.parseSymbol(symbols, new[i].Symbol)
 				if err == nil {
-					qty = self.GetMaxSize(client, base, quote, hold.HasMarket(new[i].Symbol), earn.HasMarket(new[i].Symbol), qty, mult)
+					fees := model.GetFees(ctx, self, client, new[i].Symbol)
+					qty = self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(new[i].Symbol), earn.HasMarket(new[i].Symbol), qty, fees.Mult(mult))
 					if qty > 0 {
-						prec, err = self.GetPricePrec(client, new[i].Symbol)
+						prec, err = self.GetPricePrec(ctx, client, new[i].Symbol)
 						if err == nil {
 							_, err = client.PlaceOrder(
 								new[i].Symbol,
 								exchange.OrderTypeSellLimit,
 								qty,
-								pricing.Multiply(new[i].Price, mult, prec),
+								pricing.Multiply(new[i].Price, fees.Mult(mult), prec),
 								self.getBrokerId(),
 							)
 						}
@@ -422,6 +498,7 @@ func (self *Huobi) sell(
 }
 
 func (self *Huobi) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -441,7 +518,8 @@ func (self *Huobi) Sell(
 		return err
 	}
 
-	client := exchange.New(self.getBaseURL(sandbox), apiKey, apiSecret)
+	client := exchange.New(self.getBaseURL(sandbox), apiKey, apiSecret).WithContext(ctx)
+	values := newFillValuer(self, client)
 
 	var (
 		quotes []string = []string{model.BTC}
@@ -483,7 +561,10 @@ func (self *Huobi) Sell(
 		return err
 	}
 
-	for {
+	for !shutdown.Stopping() {
+		if !breaker.Get(self.Name).Wait() {
+			break
+		}
 		// read the dynamic settings
 		var (
 			level  int64 = notify.LEVEL_DEFAULT
@@ -496,70 +577,135 @@ func (self *Huobi) Sell(
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listen to the filled orders, look for newly filled orders, automatically place new LIMIT SELL orders.
-		if filled, err = self.sell(client, quotes, mult, hold, earn, service, level, filled); err != nil {
+		if filled, err = self.sell(ctx, client, quotes, mult, hold, earn, service, values, level, filled); err != nil {
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listen to the opened orders, look for cancelled orders, send a notification.
 		if opened, err = self.listen(client, quotes, service, level, opened, filled); err != nil {
 			logger.Error(self.Name, err, level, service)
 		}
+		breaker.Get(self.Name).Done(err, service, level)
 	}
+
+	return nil
 }
 
 func (self *Huobi) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, nil, errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
+
+	if err = opts.Validate(kind); err != nil {
+		return nil, nil, err
+	}
+	if kind == model.STOP_LIMIT {
+		return nil, nil, orderNotSupported(self.Name, kind.String())
+	}
+	if opts.GetTimeInForce() == model.GTT {
+		return nil, nil, orderNotSupported(self.Name, "GTT")
+	}
 
 	if oid, err = huobiClient.PlaceOrder(market, func() exchange.OrderType {
 		if side == model.BUY {
-			if kind == model.LIMIT {
-				return exchange.OrderTypeBuyLimit
-			} else {
+			if kind == model.MARKET {
 				return exchange.OrderTypeBuyMarket
+			} else if opts.IsPostOnly() {
+				return exchange.OrderTypeBuyLimitMaker
+			} else if opts.GetTimeInForce() == model.IOC {
+				return exchange.OrderTypeBuyIOC
+			} else if opts.GetTimeInForce() == model.FOK {
+				return exchange.OrderTypeBuyLimitFOK
+			} else {
+				return exchange.OrderTypeBuyLimit
 			}
 		} else {
-			if kind == model.LIMIT {
-				return exchange.OrderTypeSellLimit
-			} else {
+			if kind == model.MARKET {
 				return exchange.OrderTypeSellMarket
+			} else if opts.IsPostOnly() {
+				return exchange.OrderTypeSellLimitMaker
+			} else if opts.GetTimeInForce() == model.IOC {
+				return exchange.OrderTypeSellIOC
+			} else if opts.GetTimeInForce() == model.FOK {
+				return exchange.OrderTypeSellLimitFOK
+			} else {
+				return exchange.OrderTypeSellLimit
 			}
 		}
-	}(), size, price, func() string {
-		if metadata != "" {
-			return metadata
-		} else {
-			return self.getBrokerId()
-		}
-	}()); err != nil {
+	}(), size, price, self.getClientOrderId(metadata)); err != nil {
 		return nil, nil, errors.Wrap(err, 1)
 	}
 
 	return oid, nil, nil
 }
 
-func (self *Huobi) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *Huobi) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *Huobi) OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *Huobi) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *Huobi) GetClosed(client interface{}, market string) (model.Orders, error) {
+func (self *Huobi) toOrder(order *exchange.Order, symbol *exchange.Symbol) model.Order {
+	out := model.Order{
+		ID:            strconv.FormatInt(order.Id, 10),
+		ClientOrderID: order.ClientOrderId,
+		Side:          model.BUY,
+		Type:          model.LIMIT,
+		Market:        symbol.Symbol,
+		Size:          order.Amount,
+		Price:         order.Price,
+		Filled:        order.FilledAmount,
+		Fee:           order.FilledFees,
+		FeeAsset:      symbol.BaseCurrency, // we pay the fee in the asset we receive
+		CreatedAt:     order.GetCreatedAt(),
+	}
+	if order.IsSell() {
+		out.Side = model.SELL
+		out.FeeAsset = symbol.QuoteCurrency
+	}
+	switch order.OrderType {
+	case exchange.OrderTypeBuyMarket, exchange.OrderTypeSellMarket:
+		out.Type = model.MARKET
+	case exchange.OrderTypeBuyStopLimit, exchange.OrderTypeSellStopLimit, exchange.OrderTypeBuyStopLimitFOK, exchange.OrderTypeSellStopLimitFOK:
+		out.Type = model.STOP_LIMIT
+	}
+	if out.Filled > 0 {
+		out.AvgPrice = order.FilledCashAmount / out.Filled
+	}
+	switch order.State {
+	case exchange.OrderStateCreated, exchange.OrderStateSubmitted:
+		out.Status = model.OPEN
+	case exchange.OrderStatePartialFilled:
+		out.Status = model.PARTIALLY_FILLED
+	case exchange.OrderStateFilled:
+		out.Status = model.FILLED
+	case exchange.OrderStatePartialCanceled, exchange.OrderStateCanceling, exchange.OrderStateCanceled:
+		out.Status = model.CANCELLED
+	}
+	out.Bought = huobiOrderBought(out.Side, order.ClientOrderId)
+	out.Bot = huobiOrderBot(order.ClientOrderId)
+	return out
+}
+
+func (self *Huobi) GetClosed(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
 
 	var (
 		err    error
@@ -571,29 +717,24 @@ func (self *Huobi) GetClosed(client interface{}, market string) (model.Orders, e
 		return nil, errors.Wrap(err, 1)
 	}
 
+	var symbol *exchange.Symbol
+	if symbol, err = self.findSymbol(huobiClient, market, true); err != nil {
+		return nil, err
+	}
+
 	for _, order := range orders {
-		output = append(output, model.Order{
-			Side: func() model.OrderSide {
-				if order.IsSell() {
-					return model.SELL
-				}
-				return model.BUY
-			}(),
-			Market:    market,
-			Size:      order.Amount,
-			Price:     order.Price,
-			CreatedAt: order.GetCreatedAt(),
-		})
+		output = append(output, self.toOrder(&order, symbol))
 	}
 
 	return output, nil
 }
 
-func (self *Huobi) GetOpened(client interface{}, market string) (model.Orders, error) {
+func (self *Huobi) GetOpened(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
 
 	var (
 		err    error
@@ -605,29 +746,89 @@ func (self *Huobi) GetOpened(client interface{}, market string) (model.Orders, e
 		return nil, errors.Wrap(err, 1)
 	}
 
+	var symbol *exchange.Symbol
+	if symbol, err = self.findSymbol(huobiClient, market, true); err != nil {
+		return nil, err
+	}
+
 	for _, order := range orders {
-		output = append(output, model.Order{
+		output = append(output, self.toOrder(&order, symbol))
+	}
+
+	return output, nil
+}
+
+func (self *Huobi) GetOrder(ctx context.Context, client model.Client, market string, id string) (*model.Order, error) {
+	huobiClient, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	huobiClient = huobiClient.WithContext(ctx)
+
+	orderId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("order ID %s is invalid", id)
+	}
+
+	order, err := huobiClient.GetOrder(orderId)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	symbol, err := self.findSymbol(huobiClient, market, true)
+	if err != nil {
+		return nil, err
+	}
+
+	out := self.toOrder(order, symbol)
+	return &out, nil
+}
+
+func (self *Huobi) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (model.Fills, error) {
+	huobiClient, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	huobiClient = huobiClient.WithContext(ctx)
+
+	var (
+		err     error
+		matches []exchange.MatchResult
+		output  model.Fills
+	)
+
+	if matches, err = huobiClient.MatchResults(market, since); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	for _, match := range matches {
+		output = append(output, model.Fill{
+			ID:      strconv.FormatInt(match.TradeId, 10),
+			OrderID: strconv.FormatInt(match.OrderId, 10),
 			Side: func() model.OrderSide {
-				if order.IsSell() {
-					return model.SELL
+				if match.IsBuy() {
+					return model.BUY
 				}
-				return model.BUY
+				return model.SELL
 			}(),
 			Market:    market,
-			Size:      order.Amount,
-			Price:     order.Price,
-			CreatedAt: order.GetCreatedAt(),
+			Price:     match.Price,
+			Size:      match.FilledAmount,
+			Fee:       match.FilledFees,
+			FeeAsset:  match.FeeCurrency,
+			CreatedAt: match.GetCreatedAt(),
 		})
 	}
 
 	return output, nil
 }
 
-func (self *Huobi) GetBook(client interface{}, market string, side model.BookSide) (interface{}, error) {
+func (self *Huobi) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (interface{}, error) {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
 
 	book, err := huobiClient.OrderBook(market)
 	if err != nil {
@@ -643,13 +844,13 @@ func (self *Huobi) GetBook(client interface{}, market string, side model.BookSid
 	}(), nil
 }
 
-func (self *Huobi) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *Huobi) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]exchange.BookEntry)
 	if !ok {
 		return nil, errors.New("invalid argument: book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -673,11 +874,12 @@ func (self *Huobi) Aggregate(client, book interface{}, market string, agg float6
 	return out, nil
 }
 
-func (self *Huobi) GetTicker(client interface{}, market string) (float64, error) {
+func (self *Huobi) GetTicker(ctx context.Context, client model.Client, market string) (float64, error) {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
 
 	ticker, err := huobiClient.Ticker(market)
 	if err != nil {
@@ -687,11 +889,54 @@ func (self *Huobi) GetTicker(client interface{}, market string) (float64, error)
 	return ticker.Price, nil
 }
 
-func (self *Huobi) Get24h(client interface{}, market string) (*model.Stats, error) {
+func (self *Huobi) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
+	huobiClient, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	huobiClient = huobiClient.WithContext(ctx)
+
+	var period string
+	switch interval {
+	case model.CANDLE_15M:
+		period = "15min"
+	case model.CANDLE_1H:
+		period = "60min"
+	case model.CANDLE_4H:
+		period = "4hour"
+	case model.CANDLE_1D:
+		period = "1day"
+	default:
+		return nil, errors.Errorf("interval %v is not supported", interval)
+	}
+
+	klines, err := huobiClient.Klines(market, period, 1000)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	// huobi returns the newest kline first
+	var out model.Candles
+	for i := len(klines) - 1; i >= 0; i-- {
+		out = append(out, model.Candle{
+			Time:   time.Unix(klines[i].Id, 0),
+			Open:   klines[i].Open,
+			High:   klines[i].High,
+			Low:    klines[i].Low,
+			Close:  klines[i].Close,
+			Volume: klines[i].Amount,
+		})
+	}
+
+	return out, nil
+}
+
+func (self *Huobi) Get24h(ctx context.Context, client model.Client, market string) (*model.Stats, error) {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
 
 	var (
 		err error
@@ -725,11 +970,44 @@ func (self *Huobi) Get24h(client interface{}, market string) (*model.Stats, erro
 	}, nil
 }
 
-func (self *Huobi) GetPricePrec(client interface{}, market string) (int, error) {
+func (self *Huobi) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	return lowestTier(0.002, 0.002), nil
+}
+
+func (self *Huobi) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	huobiClient, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	huobiClient = huobiClient.WithContext(ctx)
+	balances, err := huobiClient.Balances()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Balances
+	for _, balance := range balances {
+		if balance.Balance > 0 {
+			entry := model.Balance{
+				Asset: strings.ToUpper(balance.Currency),
+				Total: balance.Balance,
+			}
+			if balance.Type == "frozen" {
+				entry.Locked = balance.Balance
+			} else {
+				entry.Available = balance.Balance
+			}
+			out.Add(entry)
+		}
+	}
+	return out, nil
+}
+
+func (self *Huobi) GetPricePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return 8, errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
 
 	symbol, err := self.getSymbol(huobiClient, market)
 	if err != nil {
@@ -739,11 +1017,12 @@ func (self *Huobi) GetPricePrec(client interface{}, market string) (int, error)
 	return symbol.PricePrecision, nil
 }
 
-func (self *Huobi) GetSizePrec(client interface{}, market string) (int, error) {
+func (self *Huobi) GetSizePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
 
 	symbol, err := self.getSymbol(huobiClient, market)
 	if err != nil {
@@ -753,9 +1032,9 @@ func (self *Huobi) GetSizePrec(client interface{}, market string) (int, error) {
 	return symbol.AmountPrecision, nil
 }
 
-func (self *Huobi) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
-	return model.GetSizeMax(hold, earn, def, mult, func() int {
-		prec, err := self.GetSizePrec(client, self.FormatMarket(base, quote))
+func (self *Huobi) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+	return model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, self.FormatMarket(base, quote)), func() int {
+		prec, err := self.GetSizePrec(ctx, client, self.FormatMarket(base, quote))
 		if err != nil {
 			return 0
 		}
@@ -763,11 +1042,12 @@ func (self *Huobi) GetMaxSize(client interface{}, base, quote string, hold, earn
 	})
 }
 
-func (self *Huobi) Cancel(client interface{}, market string, side model.OrderSide) error {
+func (self *Huobi) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
 
 	orders, err := huobiClient.OpenOrders(market)
 	if err != nil {
@@ -785,11 +1065,31 @@ func (self *Huobi) Cancel(client interface{}, market string, side model.OrderSid
 	return nil
 }
 
-func (self *Huobi) Buy(client interface{}, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
+func (self *Huobi) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	huobiClient, ok := client.(*exchange.Client)
+	if !ok {
+		return errors.New("invalid argument: client")
+	}
+	huobiClient = huobiClient.WithContext(ctx)
+
+	orderId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return errors.Errorf("order ID %s is invalid", id)
+	}
+
+	if err = huobiClient.CancelOrder(orderId); err != nil {
+		return errors.Wrap(err, 1)
+	}
+
+	return nil
+}
+
+func (self *Huobi) Buy(ctx context.Context, client model.Client, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	huobiClient, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	huobiClient = huobiClient.WithContext(ctx)
 
 	// step #1: delete the buy order(s) that are open in your book
 	if cancel {
@@ -820,7 +1120,7 @@ func (self *Huobi) Buy(client interface{}, cancel bool, market string, calls mod
 				limit float64 = call.Price
 			)
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
 			// --- BEGIN --- order value should be greater or equal to X ------
 			symbol, err := self.getSymbol(huobiClient, market)
@@ -829,12 +1129,12 @@ func (self *Huobi) Buy(client interface{}, cancel bool, market string, calls mod
 			}
 			if symbol.MinOrderValue > 0 {
 				if limit == 0 {
-					if limit, err = self.GetTicker(client, market); err != nil {
+					if limit, err = self.GetTicker(ctx, client, market); err != nil {
 						return err
 					}
 				}
 				if (qty * limit) < symbol.MinOrderValue {
-					prec, err := self.GetSizePrec(client, market)
+					prec, err := self.GetSizePrec(ctx, client, market)
 					if err != nil {
 						return err
 					}
@@ -861,7 +1161,7 @@ func (self *Huobi) IsLeveragedToken(name string) bool {
 	return false
 }
 
-func (self *Huobi) HasAlgoOrder(client interface{}, market string) (bool, error) {
+func (self *Huobi) HasAlgoOrder(ctx context.Context, client model.Client, market string) (bool, error) {
 	return false, nil
 }
 
@@ -2,15 +2,20 @@
 package exchanges
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	filemutex "github.com/alexflint/go-filemutex"
 	"github.com/svanas/nefertiti/aggregation"
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	exchange "github.com/svanas/nefertiti/kucoin"
@@ -21,7 +26,7 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
-	"github.com/svanas/nefertiti/uuid"
+	"github.com/svanas/nefertiti/shutdown"
 )
 
 var (
@@ -82,6 +87,36 @@ const (
 	kucoinPartnerSecret = "1fac7c63-33d6-4662-801f-4a0900ecbd88"
 )
 
+// kucoinCode extracts the KuCoin error code from an error message, for example: respond code=200004
+var kucoinCode = regexp.MustCompile(`(?:code=|"code":")(\d{6})`)
+
+// kucoinClassify maps the KuCoin error codes to our error classes
+func kucoinClassify(err error) errors.Class {
+	match := kucoinCode.FindStringSubmatch(err.Error())
+	if match == nil {
+		return errors.UNCLASSIFIED
+	}
+	switch match[1] {
+	case "200004": // balance insufficient
+		return errors.INSUFFICIENT_FUNDS
+	case "429000": // too many requests
+		return errors.RATE_LIMITED
+	case "400002": // invalid KC-API-TIMESTAMP
+		return errors.TRANSIENT
+	case "400001", "400003", "400004", "400005", "400006", "400007": // missing key, unknown key, wrong passphrase, wrong signature, IP, access denied
+		return errors.AUTH
+	case "400100": // parameter error
+		return kucoinCodes.Classify(err)
+	}
+	return errors.UNCLASSIFIED
+}
+
+// kucoinCodes maps the KuCoin parameter errors to our error classes
+var kucoinCodes = errors.Codes{
+	{Code: "Order size", Class: errors.INVALID_SIZE},
+	{Code: "Price increment", Class: errors.INVALID_PRICE},
+}
+
 type Kucoin struct {
 	*model.ExchangeInfo
 	symbols exchange.SymbolsModel
@@ -266,7 +301,23 @@ func (self *Kucoin) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
-func (self *Kucoin) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *Kucoin) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        false,
+		MarketOrders:     true,
+		MarketBuyByQuote: false,
+		PostOnly:         true,
+		StopLoss:         true,
+		StopLossStrategy: true,
+		OCO:              false,
+		Trailing:         false,
+		BotOrders:        true,
+		Candles:          []time.Duration{model.CANDLE_15M, model.CANDLE_1H, model.CANDLE_4H, model.CANDLE_1D},
+	}
+}
+
+func (self *Kucoin) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	// starting 04/26/21, the KuCoin order book endpoints require authentication.
 	if permission == model.PUBLIC {
 		return exchange.NewApiService(
@@ -289,12 +340,12 @@ func (self *Kucoin) GetClient(permission model.Permission, sandbox bool) (interf
 	), nil
 }
 
-func (self *Kucoin) GetMarkets(cached, sandbox bool, blacklist []string) ([]model.Market, error) {
+func (self *Kucoin) GetMarkets(ctx context.Context, cached, sandbox bool, blacklist []string) ([]model.Market, error) {
 	var out []model.Market
 
 	client := exchange.NewApiService(
 		exchange.ApiBaseURIOption(self.baseURI(sandbox)),
-	)
+	).WithContext(ctx)
 
 	symbols, err := self.getSymbols(client, cached)
 	if err != nil {
@@ -327,11 +378,13 @@ func (self *Kucoin) FormatMarket(base, quote string) string {
 
 // listens to the filled orders, look for newly filled orders, automatically place new sell orders.
 func (self *Kucoin) sell(
+	ctx context.Context,
 	client *exchange.ApiService,
 	strategy model.Strategy,
 	mult, stop multiplier.Mult,
 	hold, earn model.Markets,
 	service model.Notify,
+	values *fillValuer,
 	twitter *notify.TwitterKeys,
 	level int64,
 	old exchange.FillsModel,
@@ -363,7 +416,7 @@ func (self *Kucoin) sell(
 
 	// get the markets
 	var markets []model.Market
-	if markets, err = self.GetMarkets(false, sandbox, nil); err != nil {
+	if markets, err = self.GetMarkets(ctx, false, sandbox, nil); err != nil {
 		return old, err
 	}
 
@@ -444,6 +497,7 @@ func (self *Kucoin) sell(
 								title = fmt.Sprintf("%s %s", title, multiplier.Format(mult))
 							}
 						}
+						title += values.value(ctx, order.Symbol, order.ParseSize(), order.ParsePrice())
 						if err = service.SendMessage(order, title, model.ALWAYS); err != nil {
 							log.Printf("[ERROR] %v", err)
 						}
@@ -472,11 +526,11 @@ func (self *Kucoin) sell(
 					} else {
 						prec := 0
 						size := 2.2 * stop.Size
-						if prec, err = self.GetSizePrec(client, symbol); err == nil {
-							_, _, err = self.Order(client,
+						if prec, err = self.GetSizePrec(ctx, client, symbol); err == nil {
+							_, _, err = self.Order(ctx, client,
 								model.BUY, symbol,
 								precision.Round(size, prec),
-								0, model.MARKET, "",
+								0, model.MARKET, nil, "",
 							)
 						}
 						if err != nil {
@@ -494,13 +548,13 @@ func (self *Kucoin) sell(
 		bought := avg(buy)
 
 		var sp int
-		if sp, err = self.GetSizePrec(client, symbol); err != nil {
+		if sp, err = self.GetSizePrec(ctx, client, symbol); err != nil {
 			return new, err
 		}
 		amount = precision.Floor(amount, sp)
 
 		if bought == 0 {
-			if bought, err = self.GetTicker(client, symbol); err != nil {
+			if bought, err = self.GetTicker(ctx, client, symbol); err != nil {
 				return new, err
 			}
 		}
@@ -512,12 +566,13 @@ func (self *Kucoin) sell(
 		)
 		base, quote, err = model.ParseMarket(markets, symbol)
 		if err == nil {
-			amount = self.GetMaxSize(client, base, quote, hold.HasMarket(symbol), earn.HasMarket(symbol), amount, mult)
+			fees := model.GetFees(ctx, self, client, symbol)
+			amount = self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(symbol), earn.HasMarket(symbol), amount, fees.Mult(mult))
 			if amount > 0 {
 				var pp int
-				if pp, err = self.GetPricePrec(client, symbol); err == nil {
+				if pp, err = self.GetPricePrec(ctx, client, symbol); err == nil {
 					if strategy == model.STRATEGY_STOP_LOSS {
-						_, err = self.StopLoss(client,
+						_, err = self.StopLoss(ctx, client,
 							symbol,
 							amount,
 							pricing.Multiply(bought, stop, pp),
@@ -525,12 +580,13 @@ func (self *Kucoin) sell(
 							strconv.FormatFloat(bought, 'f', -1, 64),
 						)
 					} else {
-						_, _, err = self.Order(client,
+						_, _, err = self.Order(ctx, client,
 							model.SELL,
 							symbol,
 							amount,
-							pricing.Multiply(bought, mult, pp),
+							pricing.Multiply(bought, fees.Mult(mult), pp),
 							model.LIMIT,
+							nil,
 							strconv.FormatFloat(bought, 'f', -1, 64),
 						)
 					}
@@ -620,6 +676,7 @@ func (self *Kucoin) listen(
 }
 
 func (self *Kucoin) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -660,7 +717,8 @@ func (self *Kucoin) Sell(
 		exchange.ApiPassPhraseOption(apiPassphrase),
 		exchange.ApiPartnerIdOption(kucoinPartnerID),
 		exchange.ApiPartnerIdOption(kucoinPartnerSecret),
-	)
+	).WithContext(ctx)
+	values := newFillValuer(self, client)
 
 	// get my filled orders
 	var filled exchange.FillsModel
@@ -678,7 +736,10 @@ func (self *Kucoin) Sell(
 		return err
 	}
 
-	for {
+	for !shutdown.Stopping() {
+		if !breaker.Get(self.Name).Wait() {
+			break
+		}
 		// read the dynamic settings
 		var (
 			level int64 = notify.LEVEL_DEFAULT
@@ -693,7 +754,7 @@ func (self *Kucoin) Sell(
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listens to the filled orders, look for newly filled orders, automatically place new sell orders.
-		if filled, err = self.sell(client, strategy, mult, stop, hold, earn, service, twitter, level, filled, sandbox, debug); err != nil {
+		if filled, err = self.sell(ctx, client, strategy, mult, stop, hold, earn, service, values, twitter, level, filled, sandbox, debug); err != nil {
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listens to the open orders, look for cancelled orders, send a notification on newly opened orders.
@@ -708,21 +769,21 @@ func (self *Kucoin) Sell(
 				if order.Stop == "loss" {
 					ticker, ok := cache[order.Symbol]
 					if !ok {
-						if ticker, err = self.GetTicker(client, order.Symbol); err == nil {
+						if ticker, err = self.GetTicker(ctx, client, order.Symbol); err == nil {
 							cache[order.Symbol] = ticker
 						}
 					}
 					if ticker > 0 {
 						var prec int
-						if prec, err = self.GetPricePrec(client, order.Symbol); err == nil {
+						if prec, err = self.GetPricePrec(ctx, client, order.Symbol); err == nil {
 							bought := order.ParseStopPrice() / float64(stop)
-							if ticker >= pricing.Multiply(bought, mult, prec) {
+							if ticker >= pricing.Multiply(bought, model.GetFees(ctx, self, client, order.Symbol).Mult(mult), prec) {
 								if _, err = client.CancelStopOrder(order.Id); err == nil {
-									_, _, err = self.Order(client,
+									_, _, err = self.Order(ctx, client,
 										model.SELL,
 										order.Symbol,
 										order.ParseSize(),
-										0, model.MARKET, "",
+										0, model.MARKET, nil, "",
 									)
 								}
 							}
@@ -739,16 +800,21 @@ func (self *Kucoin) Sell(
 				}
 			}
 		}
+		breaker.Get(self.Name).Done(err, service, level)
 	}
+
+	return nil
 }
 
 func (self *Kucoin) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	var (
@@ -760,19 +826,48 @@ func (self *Kucoin) Order(
 	if !ok {
 		return nil, nil, errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
+
+	if err = opts.Validate(kind); err != nil {
+		return nil, nil, err
+	}
 
 	var params = map[string]string{
-		"clientOid": uuid.New().Long(),
+		"clientOid": exchange.NewClientOid(metadata),
 		"side":      side.String(),
 		"symbol":    market,
 		"type":      kind.String(),
 		"size":      strconv.FormatFloat(size, 'f', -1, 64),
 	}
-	if kind == model.LIMIT {
+	if kind == model.LIMIT || kind == model.STOP_LIMIT {
+		params["type"] = model.OrderTypeString[model.LIMIT]
 		params["price"] = strconv.FormatFloat(price, 'f', -1, 64)
+		switch opts.GetTimeInForce() {
+		case model.IOC:
+			params["timeInForce"] = "IOC"
+		case model.FOK:
+			params["timeInForce"] = "FOK"
+		case model.GTT:
+			params["timeInForce"] = "GTT"
+			params["cancelAfter"] = strconv.FormatInt(int64(math.Ceil(time.Until(opts.ExpireAt).Seconds())), 10)
+		}
+		if opts.IsPostOnly() {
+			params["postOnly"] = "true"
+		}
 	}
 
-	if resp, err = kucoin.CreateOrder(params); err != nil {
+	if kind == model.STOP_LIMIT {
+		if side == model.BUY {
+			params["stop"] = "entry"
+		} else {
+			params["stop"] = "loss"
+		}
+		params["stopPrice"] = strconv.FormatFloat(opts.StopPrice, 'f', -1, 64)
+		resp, err = kucoin.CreateStopOrder(params)
+	} else {
+		resp, err = kucoin.CreateOrder(params)
+	}
+	if err != nil {
 		return nil, nil, errors.Wrap(err, 1)
 	}
 	if err = resp.ReadData(&order); err != nil {
@@ -785,7 +880,7 @@ func (self *Kucoin) Order(
 	return []byte(order.OrderId), raw, nil
 }
 
-func (self *Kucoin) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *Kucoin) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	var (
 		err   error
 		out   []byte
@@ -797,9 +892,10 @@ func (self *Kucoin) StopLoss(client interface{}, market string, size float64, pr
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
 
 	var params = map[string]string{
-		"clientOid": uuid.New().Long(),
+		"clientOid": exchange.NewClientOid(metadata),
 		"side":      model.OrderSideString[model.SELL],
 		"symbol":    market,
 		"type":      kind.String(),
@@ -824,11 +920,79 @@ func (self *Kucoin) StopLoss(client interface{}, market string, size float64, pr
 	return out, nil
 }
 
-func (self *Kucoin) OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *Kucoin) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	return nil, errors.New("Not implemented")
 }
 
-func (self *Kucoin) GetClosed(client interface{}, market string) (model.Orders, error) {
+func (self *Kucoin) toOrder(order *exchange.OrderModel) model.Order {
+	out := model.Order{
+		ID:            order.Id,
+		ClientOrderID: order.ClientOid,
+		Side:          model.NewOrderSide(order.Side),
+		Type:          model.NewOrderType(order.Type),
+		Market:        order.Symbol,
+		Size:          order.ParseSize(),
+		Price:         order.ParsePrice(),
+		Filled:        order.ParseDealSize(),
+		Fee:           order.ParseFee(),
+		FeeAsset:      order.FeeCurrency,
+		CreatedAt:     order.ParseCreatedAt(),
+	}
+	if order.Stop != "" && out.Type == model.LIMIT {
+		out.Type = model.STOP_LIMIT
+	}
+	if out.Filled > 0 {
+		out.AvgPrice = order.ParseDealFunds() / out.Filled
+	}
+	out.Status = model.GetOrderStatus(out.Size, out.Filled, !order.IsActive)
+	out.Bought = kucoinOrderBought(out.Side, order.ClientOid)
+	out.Bot = exchange.IsClientOid(order.ClientOid)
+	return out
+}
+
+// kucoinOrderBought returns the price we paid for the base asset of a sell order, or zero if the clientOid doesn't tell us
+func kucoinOrderBought(side model.OrderSide, clientOid string) float64 {
+	if side == model.SELL {
+		if metadata, err := exchange.ParseClientOid(clientOid); err == nil {
+			if out, err := strconv.ParseFloat(metadata, 64); err == nil {
+				return out
+			}
+		}
+	}
+	return 0
+}
+
+var (
+	kucoinClientOids      = make(map[string]string) // order ID -> clientOid, so we ask for the clientOid of a closed order only once
+	kucoinClientOidsMutex sync.Mutex
+)
+
+// getClientOid returns the clientOid of an order. The fills do not include one.
+func (self *Kucoin) getClientOid(client *exchange.ApiService, id string) (string, error) {
+	kucoinClientOidsMutex.Lock()
+	out, ok := kucoinClientOids[id]
+	kucoinClientOidsMutex.Unlock()
+	if ok {
+		return out, nil
+	}
+
+	resp, err := client.Order(id)
+	if err != nil {
+		return "", errors.Wrap(err, 1)
+	}
+	var order exchange.OrderModel
+	if err = resp.ReadData(&order); err != nil {
+		return "", errors.Wrap(err, 1)
+	}
+
+	kucoinClientOidsMutex.Lock()
+	kucoinClientOids[id] = order.ClientOid
+	kucoinClientOidsMutex.Unlock()
+
+	return order.ClientOid, nil
+}
+
+func (self *Kucoin) GetClosed(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var (
 		err   error
 		resp  *exchange.ApiResponse
@@ -841,6 +1005,7 @@ func (self *Kucoin) GetClosed(client interface{}, market string) (model.Orders,
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
 
 	var curr int64 = 1
 	for {
@@ -852,14 +1017,31 @@ func (self *Kucoin) GetClosed(client interface{}, market string) (model.Orders,
 		if page, err = resp.ReadPaginationData(&fills); err != nil {
 			return nil, errors.Wrap(err, 1)
 		}
+		// every fill is a (filled) order
 		for _, fill := range fills {
-			out = append(out, model.Order{
+			order := model.Order{
+				ID:        fill.OrderId,
 				Side:      model.NewOrderSide(fill.Side),
+				Type:      model.NewOrderType(fill.Type),
+				Status:    model.FILLED,
 				Market:    fill.Symbol,
 				Size:      fill.ParseSize(),
 				Price:     fill.ParsePrice(),
+				Filled:    fill.ParseSize(),
+				AvgPrice:  fill.ParsePrice(),
+				Fee:       fill.ParseFee(),
+				FeeAsset:  fill.FeeCurrency,
 				CreatedAt: fill.ParseCreatedAt(),
-			})
+			}
+			// the clientOid of a sell order is the price we paid
+			if order.Side == model.SELL {
+				if order.ClientOrderID, err = self.getClientOid(kucoin, fill.OrderId); err != nil {
+					return nil, err
+				}
+				order.Bought = kucoinOrderBought(order.Side, order.ClientOrderID)
+				order.Bot = exchange.IsClientOid(order.ClientOrderID)
+			}
+			out = append(out, order)
 		}
 		if page.CurrentPage >= page.TotalPage {
 			break
@@ -871,7 +1053,7 @@ func (self *Kucoin) GetClosed(client interface{}, market string) (model.Orders,
 	return out, nil
 }
 
-func (self *Kucoin) GetOpened(client interface{}, market string) (model.Orders, error) {
+func (self *Kucoin) GetOpened(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	var (
 		err    error
 		out    model.Orders
@@ -882,25 +1064,108 @@ func (self *Kucoin) GetOpened(client interface{}, market string) (model.Orders,
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
 
 	if orders, err = self.getOrders(kucoin, map[string]string{"status": "active", "symbol": market}); err != nil {
 		return nil, err
 	}
 
 	for _, order := range orders {
-		out = append(out, model.Order{
-			Side:      model.NewOrderSide(order.Side),
-			Market:    order.Symbol,
-			Size:      order.ParseSize(),
-			Price:     order.ParsePrice(),
-			CreatedAt: order.ParseCreatedAt(),
+		out = append(out, self.toOrder(order))
+	}
+
+	return out, nil
+}
+
+func (self *Kucoin) GetOrder(ctx context.Context, client model.Client, market string, id string) (*model.Order, error) {
+	var (
+		err   error
+		resp  *exchange.ApiResponse
+		order exchange.OrderModel
+	)
+
+	kucoin, ok := client.(*exchange.ApiService)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	kucoin = kucoin.WithContext(ctx)
+
+	if resp, err = kucoin.Order(id); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	if err = resp.ReadData(&order); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	out := self.toOrder(&order)
+	return &out, nil
+}
+
+func (self *Kucoin) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (model.Fills, error) {
+	var (
+		err   error
+		resp  *exchange.ApiResponse
+		page  *exchange.PaginationModel
+		fills exchange.FillsModel
+		out   model.Fills
+	)
+
+	kucoin, ok := client.(*exchange.ApiService)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	kucoin = kucoin.WithContext(ctx)
+
+	// the fills endpoint returns one week at a time, and no more than one year ago. without a since, we walk the full
+	// year (the complete history that KuCoin keeps) week by week.
+	const week = 7 * 24 * time.Hour
+	var windows []map[string]string
+	start := since
+	if since.IsZero() || time.Since(start) > 365*24*time.Hour {
+		start = time.Now().AddDate(-1, 0, 0)
+	}
+	for ; start.Before(time.Now()); start = start.Add(week) {
+		windows = append(windows, map[string]string{
+			"symbol":  market,
+			"startAt": strconv.FormatInt(start.UnixNano()/int64(time.Millisecond), 10),
+			"endAt":   strconv.FormatInt(start.Add(week).UnixNano()/int64(time.Millisecond), 10),
 		})
 	}
 
+	for _, params := range windows {
+		var curr int64 = 1
+		for {
+			if resp, err = kucoin.Fills(params, &exchange.PaginationParam{CurrentPage: curr, PageSize: 500}); err != nil {
+				return nil, errors.Wrap(err, 1)
+			}
+			if page, err = resp.ReadPaginationData(&fills); err != nil {
+				return nil, errors.Wrap(err, 1)
+			}
+			for _, fill := range fills {
+				out = append(out, model.Fill{
+					ID:        fill.TradeId,
+					OrderID:   fill.OrderId,
+					Side:      model.NewOrderSide(fill.Side),
+					Market:    fill.Symbol,
+					Price:     fill.ParsePrice(),
+					Size:      fill.ParseSize(),
+					Fee:       fill.ParseFee(),
+					FeeAsset:  fill.FeeCurrency,
+					CreatedAt: fill.ParseCreatedAt(),
+				})
+			}
+			if page.CurrentPage >= page.TotalPage {
+				break
+			} else {
+				curr++
+			}
+		}
+	}
+
 	return out, nil
 }
 
-func (self *Kucoin) GetBook(client interface{}, market string, side model.BookSide) (interface{}, error) {
+func (self *Kucoin) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (interface{}, error) {
 	var (
 		err  error
 		out  []exchange.BookEntry
@@ -912,6 +1177,7 @@ func (self *Kucoin) GetBook(client interface{}, market string, side model.BookSi
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
 
 	if resp, err = kucoin.AggregatedFullOrderBook(market); err != nil {
 		return nil, errors.Wrap(err, 1)
@@ -929,13 +1195,13 @@ func (self *Kucoin) GetBook(client interface{}, market string, side model.BookSi
 	return out, nil
 }
 
-func (self *Kucoin) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *Kucoin) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]exchange.BookEntry)
 	if !ok {
 		return nil, errors.New("invalid argument: book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -959,7 +1225,7 @@ func (self *Kucoin) Aggregate(client, book interface{}, market string, agg float
 	return out, nil
 }
 
-func (self *Kucoin) GetTicker(client interface{}, market string) (float64, error) {
+func (self *Kucoin) GetTicker(ctx context.Context, client model.Client, market string) (float64, error) {
 	var (
 		err    error
 		out    float64
@@ -971,6 +1237,7 @@ func (self *Kucoin) GetTicker(client interface{}, market string) (float64, error
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
 
 	if resp, err = kucoin.TickerLevel1(market); err != nil {
 		return 0, errors.Wrap(err, 1)
@@ -985,11 +1252,72 @@ func (self *Kucoin) GetTicker(client interface{}, market string) (float64, error
 	return out, nil
 }
 
-func (self *Kucoin) Get24h(client interface{}, market string) (*model.Stats, error) {
+func (self *Kucoin) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
+	var (
+		err    error
+		resp   *exchange.ApiResponse
+		klines exchange.KLinesModel
+	)
+
 	kucoin, ok := client.(*exchange.ApiService)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
+
+	var typo string
+	switch interval {
+	case model.CANDLE_15M:
+		typo = "15min"
+	case model.CANDLE_1H:
+		typo = "1hour"
+	case model.CANDLE_4H:
+		typo = "4hour"
+	case model.CANDLE_1D:
+		typo = "1day"
+	default:
+		return nil, errors.Errorf("interval %v is not supported", interval)
+	}
+
+	if resp, err = kucoin.KLines(market, typo); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	if err = resp.ReadData(&klines); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	// kucoin returns the newest k line first: [time, open, close, high, low, volume, turnover]
+	var out model.Candles
+	for i := len(klines) - 1; i >= 0; i-- {
+		kline := *klines[i]
+		if len(kline) < 6 {
+			continue
+		}
+		var values [6]float64
+		for n := range values {
+			if values[n], err = strconv.ParseFloat(kline[n], 64); err != nil {
+				return nil, errors.Wrap(err, 1)
+			}
+		}
+		out = append(out, model.Candle{
+			Time:   time.Unix(int64(values[0]), 0),
+			Open:   values[1],
+			Close:  values[2],
+			High:   values[3],
+			Low:    values[4],
+			Volume: values[5],
+		})
+	}
+
+	return out, nil
+}
+
+func (self *Kucoin) Get24h(ctx context.Context, client model.Client, market string) (*model.Stats, error) {
+	kucoin, ok := client.(*exchange.ApiService)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	kucoin = kucoin.WithContext(ctx)
 
 	var (
 		err  error
@@ -1033,11 +1361,81 @@ func (self *Kucoin) Get24h(client interface{}, market string) (*model.Stats, err
 	}, nil
 }
 
-func (self *Kucoin) GetPricePrec(client interface{}, market string) (int, error) {
+func (self *Kucoin) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	kucoin, ok := client.(*exchange.ApiService)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	kucoin = kucoin.WithContext(ctx)
+
+	var (
+		err  error
+		resp *exchange.ApiResponse
+		fees exchange.TradeFeesModel
+	)
+	if resp, err = kucoin.TradeFees(market); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	if err = resp.ReadData(&fees); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	for _, fee := range fees {
+		if fee.Symbol == market {
+			var out model.Fees
+			if out.Maker, err = strconv.ParseFloat(fee.MakerFeeRate, 64); err != nil {
+				return nil, errors.Wrap(err, 1)
+			}
+			if out.Taker, err = strconv.ParseFloat(fee.TakerFeeRate, 64); err != nil {
+				return nil, errors.Wrap(err, 1)
+			}
+			return &out, nil
+		}
+	}
+
+	return nil, errors.Errorf("market %s not found", market)
+}
+
+func (self *Kucoin) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	var (
+		err      error
+		resp     *exchange.ApiResponse
+		accounts exchange.AccountsModel
+	)
+	kucoin, ok := client.(*exchange.ApiService)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	kucoin = kucoin.WithContext(ctx)
+	if resp, err = kucoin.Accounts("", "trade"); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	if err = resp.ReadData(&accounts); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Balances
+	for _, account := range accounts {
+		total, _ := strconv.ParseFloat(account.Balance, 64)
+		if total > 0 {
+			available, _ := strconv.ParseFloat(account.Available, 64)
+			holds, _ := strconv.ParseFloat(account.Holds, 64)
+			out = append(out, model.Balance{
+				Asset:     account.Currency,
+				Total:     total,
+				Available: available,
+				Locked:    holds,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (self *Kucoin) GetPricePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	kucoin, ok := client.(*exchange.ApiService)
 	if !ok {
 		return 8, errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
 	symbol, err := self.getSymbol(kucoin, market)
 	if err != nil {
 		return 8, err
@@ -1045,11 +1443,12 @@ func (self *Kucoin) GetPricePrec(client interface{}, market string) (int, error)
 	return precision.Parse(symbol.PriceIncrement, 8), nil
 }
 
-func (self *Kucoin) GetSizePrec(client interface{}, market string) (int, error) {
+func (self *Kucoin) GetSizePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	kucoin, ok := client.(*exchange.ApiService)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
 	symbol, err := self.getSymbol(kucoin, market)
 	if err != nil {
 		return 0, err
@@ -1057,14 +1456,14 @@ func (self *Kucoin) GetSizePrec(client interface{}, market string) (int, error)
 	return precision.Parse(symbol.BaseIncrement, 0), nil
 }
 
-func (self *Kucoin) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+func (self *Kucoin) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
 	if hold {
 		if base == "KCS" {
 			return 0
 		}
 	}
-	return model.GetSizeMax(hold, earn, def, mult, func() int {
-		prec, err := self.GetSizePrec(client, self.FormatMarket(base, quote))
+	return model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, self.FormatMarket(base, quote)), func() int {
+		prec, err := self.GetSizePrec(ctx, client, self.FormatMarket(base, quote))
 		if err != nil {
 			return 0
 		}
@@ -1072,7 +1471,7 @@ func (self *Kucoin) GetMaxSize(client interface{}, base, quote string, hold, ear
 	})
 }
 
-func (self *Kucoin) Cancel(client interface{}, market string, side model.OrderSide) error {
+func (self *Kucoin) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
 	var (
 		err    error
 		orders exchange.OrdersModel
@@ -1082,6 +1481,7 @@ func (self *Kucoin) Cancel(client interface{}, market string, side model.OrderSi
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
 
 	if orders, err = self.getOrders(kucoin, map[string]string{
 		"status": "active",
@@ -1100,13 +1500,26 @@ func (self *Kucoin) Cancel(client interface{}, market string, side model.OrderSi
 	return nil
 }
 
-func (self *Kucoin) Buy(client interface{}, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
+func (self *Kucoin) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	kucoin, ok := client.(*exchange.ApiService)
+	if !ok {
+		return errors.New("invalid argument: client")
+	}
+	kucoin = kucoin.WithContext(ctx)
+	if _, err := kucoin.CancelOrder(id); err != nil {
+		return errors.Wrap(err, 1)
+	}
+	return nil
+}
+
+func (self *Kucoin) Buy(ctx context.Context, client model.Client, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	var err error
 
 	kucoin, ok := client.(*exchange.ApiService)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	kucoin = kucoin.WithContext(ctx)
 
 	// delete the buy order(s) that are open in your book
 	if cancel {
@@ -1148,15 +1561,15 @@ func (self *Kucoin) Buy(client interface{}, cancel bool, market string, calls mo
 
 			limit := call.Price
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
 
-			if _, _, err = self.Order(client,
+			if _, _, err = self.Order(ctx, client,
 				model.BUY,
 				market,
 				qty,
 				limit,
-				kind, "",
+				kind, nil, "",
 			); err != nil {
 				return err
 			}
@@ -1170,7 +1583,7 @@ func (self *Kucoin) IsLeveragedToken(name string) bool {
 	return len(name) > 2 && (strings.HasSuffix(strings.ToUpper(name), "3L") || strings.HasSuffix(strings.ToUpper(name), "3S"))
 }
 
-func (self *Kucoin) HasAlgoOrder(client interface{}, market string) (bool, error) {
+func (self *Kucoin) HasAlgoOrder(ctx context.Context, client model.Client, market string) (bool, error) {
 	return false, nil
 }
 
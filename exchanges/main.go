@@ -1,6 +1,13 @@
 package exchanges
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
@@ -9,6 +16,21 @@ import (
 
 type Exchanges []model.Exchange
 
+func init() {
+	// every exchange maps its own error codes (and nobody else's) to our error classes
+	errors.Register("Binance", binanceClassify)
+	errors.Register("BinanceUS", binanceClassify)
+	errors.Register("Bitstamp", bitstampCodes.Classify)
+	errors.Register("Bittrex", bittrexCodes.Classify)
+	errors.Register("CEX.IO", cexIoCodes.Classify)
+	errors.Register("crypto.com", cryptoDotComCodes.Classify)
+	errors.Register("Coinbase Pro", gdaxCodes.Classify)
+	errors.Register("HitBTC", hitbtcCodes.Classify)
+	errors.Register("Huobi", huobiClassify)
+	errors.Register("KuCoin", kucoinClassify)
+	errors.Register("Woo", wooClassify)
+}
+
 func (exchanges *Exchanges) findByName(name string) model.Exchange {
 	for _, exchange := range *exchanges {
 		if exchange.GetInfo().Equals(name) {
@@ -20,17 +42,21 @@ func (exchanges *Exchanges) findByName(name string) model.Exchange {
 
 func New() *Exchanges {
 	var out Exchanges
-	out = append(out, newGdax())
-	out = append(out, newBittrex())
-	out = append(out, newBitstamp())
-	out = append(out, newCexIo())
-	out = append(out, newBinance())
-	out = append(out, newBinanceUS())
-	out = append(out, newHitBTC())
-	out = append(out, newKucoin())
-	out = append(out, newCryptoDotCom())
-	out = append(out, newWoo())
-	out = append(out, newHuobi())
+	for _, exchange := range []model.Exchange{
+		newGdax(),
+		newBittrex(),
+		newBitstamp(),
+		newCexIo(),
+		newBinance(),
+		newBinanceUS(),
+		newHitBTC(),
+		newKucoin(),
+		newCryptoDotCom(),
+		newWoo(),
+		newHuobi(),
+	} {
+		out = append(out, newRetrying(exchange))
+	}
 	return &out
 }
 
@@ -43,57 +69,208 @@ func GetExchange() (model.Exchange, error) {
 	if out == nil {
 		return nil, errors.Errorf("exchange %v does not exist", arg)
 	}
+	if err := checkSandbox(out); err != nil {
+		return nil, err
+	}
+	if err := overrideEndpoints(out); err != nil {
+		return nil, err
+	}
 	return out, nil
 }
 
-func promptForApiKeys(exchange string) (apiKey, apiSecret string, err error) {
-	apiKey = flag.Get("api-key").String()
-	if apiKey == "" {
-		if flag.Listen() {
-			return "", "", errors.New("missing argument: api-key")
+// GetExchanges returns the exchanges in the --exchanges=X,Y,Z arg (or the --exchange=X,Y,Z arg)
+func GetExchanges() (Exchanges, error) {
+	arg := flag.Get("exchanges")
+	if !arg.Exists {
+		arg = flag.Get("exchange")
+	}
+	if !arg.Exists {
+		return nil, errors.New("missing argument: exchanges")
+	}
+	var out Exchanges
+	for _, name := range arg.Split() {
+		exchange := New().findByName(name)
+		if exchange == nil {
+			return nil, errors.Errorf("exchange %s does not exist", name)
+		}
+		if err := checkSandbox(exchange); err != nil {
+			return nil, err
 		}
-		var data []byte
-		if data, err = passphrase.Read(exchange + " API key"); err != nil {
-			return "", "", errors.Wrap(err, 1)
+		if err := overrideEndpoints(exchange); err != nil {
+			return nil, err
 		}
-		apiKey = string(data)
-		flag.Set("api-key", apiKey)
+		out = append(out, exchange)
 	}
+	return out, nil
+}
 
-	apiSecret = flag.Get("api-secret").String()
-	if apiSecret == "" {
+// checkSandbox returns an error if the --sandbox arg is included, but the exchange does not have a sandbox
+func checkSandbox(exchange model.Exchange) error {
+	if flag.Sandbox() && !exchange.Capabilities().Sandbox {
+		return errors.Errorf("%s does not have a sandbox", exchange.GetInfo().Name)
+	}
+	return nil
+}
+
+var (
+	apiKeys      = make(map[string]string) // the API keys we have read (or prompted for) per exchange
+	apiKeysMutex sync.Mutex
+)
+
+// apiKeyArg returns the --api-key, --api-secret or --api-passphrase arg for one exchange. The arg is either one value that
+// applies to every exchange, or a comma-separated list of exchange=value pairs, for example: --api-key=Binance=X,KuCoin=Y
+func apiKeyArg(exchange, name string) string {
+	arg := flag.Get(name)
+	if !arg.Exists {
+		return ""
+	}
+	all := New()
+	for _, value := range arg.Split() {
+		if i := strings.Index(value, "="); i > 0 {
+			if other := all.findByName(value[:i]); other != nil {
+				if other.GetInfo().Equals(exchange) {
+					return value[i+1:]
+				}
+				continue
+			}
+		}
+		return arg.String()
+	}
+	return ""
+}
+
+// getApiKey returns the --api-key, --api-secret or --api-passphrase arg for one exchange. If the arg is missing, then we
+// prompt for it (once per exchange) unless we are listening.
+func getApiKey(exchange, name string) (string, error) {
+	apiKeysMutex.Lock()
+	defer apiKeysMutex.Unlock()
+
+	key := strings.ToLower(exchange + "/" + name)
+	if out, ok := apiKeys[key]; ok {
+		return out, nil
+	}
+
+	out := apiKeyArg(exchange, name)
+	if out == "" {
 		if flag.Listen() {
-			return "", "", errors.New("missing argument: api-secret")
+			return "", errors.Errorf("missing argument: %s", name)
 		}
-		var data []byte
-		if data, err = passphrase.Read(exchange + " API secret"); err != nil {
-			return "", "", errors.Wrap(err, 1)
+		data, err := passphrase.Read(exchange + " API " + strings.TrimPrefix(name, "api-"))
+		if err != nil {
+			return "", errors.Wrap(err, 1)
 		}
-		apiSecret = string(data)
-		flag.Set("api-secret", apiSecret)
+		out = string(data)
 	}
 
+	apiKeys[key] = out
+	return out, nil
+}
+
+func promptForApiKeys(exchange string) (apiKey, apiSecret string, err error) {
+	if apiKey, err = getApiKey(exchange, "api-key"); err != nil {
+		return "", "", err
+	}
+	if apiSecret, err = getApiKey(exchange, "api-secret"); err != nil {
+		return "", "", err
+	}
 	return apiKey, apiSecret, nil
 }
 
 func promptForApiKeysEx(exchange string) (apiKey, apiSecret, apiPassphrase string, err error) {
-	apiKey, apiSecret, err = promptForApiKeys(exchange)
+	if apiKey, apiSecret, err = promptForApiKeys(exchange); err != nil {
+		return "", "", "", err
+	}
+	if apiPassphrase, err = getApiKey(exchange, "api-passphrase"); err != nil {
+		return "", "", "", err
+	}
+	return apiKey, apiSecret, apiPassphrase, nil
+}
 
-	if err != nil {
-		return apiKey, apiSecret, "", err
+// lowestTier returns the fees of the lowest volume tier. We use this for the exchanges that don't tell us what fees we pay.
+func lowestTier(maker, taker float64) *model.Fees {
+	return &model.Fees{Maker: maker, Taker: taker}
+}
+
+// fillValuer values the fills of a sell loop in the --reference currency. We build one per sell loop, so we get the
+// markets once, and the tickers once per minute, instead of once per fill.
+type fillValuer struct {
+	exchange  model.MarketData
+	client    model.Client
+	reference string
+	markets   []model.Market
+	prices    *model.Prices
+	updated   time.Time
+}
+
+// newFillValuer returns nil if the --reference arg is not included
+func newFillValuer(exchange model.MarketData, client model.Client) *fillValuer {
+	flg := flag.Get("reference")
+	if !flg.Exists {
+		return nil
+	}
+	return &fillValuer{
+		exchange:  exchange,
+		client:    client,
+		reference: strings.ToUpper(flg.String()),
 	}
+}
 
-	apiPassphrase = flag.Get("api-passphrase").String()
-	if apiPassphrase == "" {
-		if flag.Listen() {
-			return "", "", "", errors.New("missing argument: api-passphrase")
-		}
-		var data []byte
-		if data, err = passphrase.Read(exchange + " API passphrase"); err != nil {
-			return "", "", "", errors.Wrap(err, 1)
+// value returns the value of a fill in the --reference currency, for example: " (123.45 USD)"
+// returns an empty string if the --reference arg is not included, or if we cannot value this fill.
+func (self *fillValuer) value(ctx context.Context, market string, size, price float64) string {
+	if self == nil || size == 0 {
+		return ""
+	}
+
+	if self.markets == nil {
+		markets, err := self.exchange.GetMarkets(ctx, true, flag.Sandbox(), nil)
+		if err != nil {
+			return ""
 		}
-		apiPassphrase = string(data)
+		self.markets = markets
+	}
+	base, quote, err := model.ParseMarket(self.markets, market)
+	if err != nil {
+		return ""
 	}
 
-	return apiKey, apiSecret, apiPassphrase, nil
+	if self.prices == nil || time.Since(self.updated) > time.Minute {
+		self.prices = model.NewPrices(ctx, self.exchange, self.client, self.markets)
+		self.updated = time.Now()
+	}
+
+	var value float64
+	if price > 0 {
+		value, err = self.prices.Convert(size*price, quote, self.reference)
+	} else {
+		value, err = self.prices.Convert(size, base, self.reference)
+	}
+	if err != nil {
+		log.Printf("[WARN] %v\n", err)
+		return ""
+	}
+
+	return fmt.Sprintf(" (%.2f %s)", value, self.reference)
+}
+
+// orderNotSupported returns the error an exchange returns when it does not support an order type or an order option
+func orderNotSupported(exchange, what string) error {
+	return errors.Errorf("%s does not support %s orders", exchange, what)
+}
+
+// plainOrder returns an error if the order is anything other than a limit (or market) order that is good till cancelled
+func plainOrder(exchange string, kind model.OrderType, opts *model.OrderOptions) error {
+	if err := opts.Validate(kind); err != nil {
+		return err
+	}
+	if kind == model.STOP_LIMIT {
+		return orderNotSupported(exchange, kind.String())
+	}
+	if opts.IsPostOnly() {
+		return orderNotSupported(exchange, "post-only")
+	}
+	if tif := opts.GetTimeInForce(); tif != model.GTC {
+		return orderNotSupported(exchange, strings.ToUpper(tif.String()))
+	}
+	return nil
 }
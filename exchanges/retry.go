@@ -0,0 +1,187 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package exchanges
+
+import (
+	"context"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/model"
+)
+
+// retrying tries again (with our errors.DefaultBackoff policy) when an exchange method fails for a retryable reason.
+// Every attempt calls the exchange again, so the request gets rebuilt with a fresh timestamp and signature. Buy and Sell
+// open more than one order, so we never call them twice.
+type retrying struct {
+	model.Exchange
+}
+
+func newRetrying(exchange model.Exchange) model.Exchange {
+	return &retrying{exchange}
+}
+
+// read retries a method that doesn't change anything on the exchange, until ctx is done
+func (self *retrying) read(ctx context.Context, fn func() error) error {
+	name := self.GetInfo().Name
+	return errors.DefaultBackoff.RetryIf(fn, func(err error) bool {
+		return ctx.Err() == nil && errors.ClassIn(name, err).Retryable()
+	})
+}
+
+// write retries a method that opens or cancels orders, but only if the exchange has rejected our request (for example:
+// because of a stale timestamp or a rate limit). We don't know what happened to a request that timed out or hit a 502
+// Bad Gateway, so we don't try again then.
+func (self *retrying) write(ctx context.Context, fn func() error) error {
+	name := self.GetInfo().Name
+	return errors.DefaultBackoff.RetryIf(fn, func(err error) bool {
+		return ctx.Err() == nil && errors.ClassOf(err) != errors.TRANSIENT && errors.ClassIn(name, err).Retryable()
+	})
+}
+
+func (self *retrying) GetMarkets(ctx context.Context, cached, sandbox bool, ignore []string) (out []model.Market, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetMarkets(ctx, cached, sandbox, ignore)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (out interface{}, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetBook(ctx, client, market, side)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetTicker(ctx context.Context, client model.Client, market string) (out float64, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetTicker(ctx, client, market)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (out model.Candles, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetCandles(ctx, client, market, interval)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) Get24h(ctx context.Context, client model.Client, market string) (out *model.Stats, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.Get24h(ctx, client, market)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetPricePrec(ctx context.Context, client model.Client, market string) (out int, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetPricePrec(ctx, client, market)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetSizePrec(ctx context.Context, client model.Client, market string) (out int, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetSizePrec(ctx, client, market)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) Order(ctx context.Context, client model.Client, side model.OrderSide, market string, size float64, price float64, kind model.OrderType, opts *model.OrderOptions, metadata string) (oid []byte, raw []byte, err error) {
+	err = self.write(ctx, func() error {
+		oid, raw, err = self.Exchange.Order(ctx, client, side, market, size, price, kind, opts, metadata)
+		return err
+	})
+	return oid, raw, err
+}
+
+func (self *retrying) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) (out []byte, err error) {
+	err = self.write(ctx, func() error {
+		out, err = self.Exchange.StopLoss(ctx, client, market, size, price, kind, metadata)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) (out []byte, err error) {
+	err = self.write(ctx, func() error {
+		out, err = self.Exchange.OCO(ctx, client, market, size, price, stop, metadata)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetClosed(ctx context.Context, client model.Client, market string) (out model.Orders, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetClosed(ctx, client, market)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetOpened(ctx context.Context, client model.Client, market string) (out model.Orders, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetOpened(ctx, client, market)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetOrder(ctx context.Context, client model.Client, market string, id string) (out *model.Order, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetOrder(ctx, client, market, id)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
+	return self.write(ctx, func() error {
+		return self.Exchange.Cancel(ctx, client, market, side)
+	})
+}
+
+func (self *retrying) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	return self.write(ctx, func() error {
+		return self.Exchange.CancelOrder(ctx, client, market, id)
+	})
+}
+
+func (self *retrying) HasAlgoOrder(ctx context.Context, client model.Client, market string) (out bool, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.HasAlgoOrder(ctx, client, market)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (out model.Fills, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetFills(ctx, client, market, since)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetFees(ctx context.Context, client model.Client, market string) (out *model.Fees, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetFees(ctx, client, market)
+		return err
+	})
+	return out, err
+}
+
+func (self *retrying) GetBalances(ctx context.Context, client model.Client) (out model.Balances, err error) {
+	err = self.read(ctx, func() error {
+		out, err = self.Exchange.GetBalances(ctx, client)
+		return err
+	})
+	return out, err
+}
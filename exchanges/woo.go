@@ -2,6 +2,7 @@
 package exchanges
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -11,6 +12,7 @@ import (
 
 	filemutex "github.com/alexflint/go-filemutex"
 	"github.com/svanas/nefertiti/aggregation"
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/logger"
@@ -20,6 +22,7 @@ import (
 	"github.com/svanas/nefertiti/precision"
 	"github.com/svanas/nefertiti/pricing"
 	"github.com/svanas/nefertiti/session"
+	"github.com/svanas/nefertiti/shutdown"
 	exchange "github.com/svanas/nefertiti/woo"
 )
 
@@ -76,6 +79,29 @@ func init() {
 	}
 }
 
+// wooClassify maps the WOO X error codes to our error classes
+func wooClassify(err error) errors.Class {
+	wooError, ok := err.(*exchange.Error)
+	if !ok {
+		return errors.UNCLASSIFIED
+	}
+	switch wooError.Code {
+	case -1003: // TOO_MANY_REQUEST
+		return errors.RATE_LIMITED
+	case -1011: // RPC_NOT_CONNECT
+		return errors.TRANSIENT
+	case -1001, -1002: // INVALID_SIGNATURE, UNAUTHORIZED
+		return errors.AUTH
+	case -1101: // RISK_TOO_HIGH, aka not enough collateral
+		return errors.INSUFFICIENT_FUNDS
+	case -1008, -1102: // QUANTITY_TOO_HIGH, MIN_NOTIONAL
+		return errors.INVALID_SIZE
+	case -1103, -1104, -1105: // PRICE_FILTER, PRICE_RANGE, PRICE_LIMIT
+		return errors.INVALID_PRICE
+	}
+	return errors.UNCLASSIFIED
+}
+
 type Woo struct {
 	*model.ExchangeInfo
 	symbols []exchange.Symbol
@@ -126,7 +152,22 @@ func (self *Woo) GetInfo() *model.ExchangeInfo {
 	return self.ExchangeInfo
 }
 
-func (self *Woo) GetClient(permission model.Permission, sandbox bool) (interface{}, error) {
+func (self *Woo) Capabilities() *model.Capabilities {
+	return &model.Capabilities{
+		Sandbox:          self.REST.Sandbox != "",
+		WebSocket:        false,
+		MarketOrders:     true,
+		MarketBuyByQuote: false,
+		PostOnly:         true,
+		StopLoss:         false,
+		StopLossStrategy: false,
+		OCO:              false,
+		Trailing:         false,
+		BotOrders:        false,
+	}
+}
+
+func (self *Woo) GetClient(permission model.Permission, sandbox bool) (model.Client, error) {
 	if permission == model.PUBLIC {
 		return exchange.New(self.getBaseURL(sandbox), "", ""), nil
 	}
@@ -139,10 +180,10 @@ func (self *Woo) GetClient(permission model.Permission, sandbox bool) (interface
 	return exchange.New(self.getBaseURL(sandbox), apiKey, apiSecret), nil
 }
 
-func (self *Woo) GetMarkets(cached, sandbox bool, blacklist []string) ([]model.Market, error) {
+func (self *Woo) GetMarkets(ctx context.Context, cached, sandbox bool, blacklist []string) ([]model.Market, error) {
 	var out []model.Market
 
-	symbols, err := self.getSymbols(exchange.New(self.getBaseURL(sandbox), "", ""), cached)
+	symbols, err := self.getSymbols(exchange.New(self.getBaseURL(sandbox), "", "").WithContext(ctx), cached)
 	if err != nil {
 		return nil, err
 	}
@@ -252,10 +293,12 @@ func (self *Woo) listen(
 
 // listen to the filled orders, look for newly filled orders, automatically place new LIMIT SELL orders.
 func (self *Woo) sell(
+	ctx context.Context,
 	client *exchange.Client,
 	mult multiplier.Mult,
 	hold, earn model.Markets,
 	service model.Notify,
+	values *fillValuer,
 	level int64,
 	old []exchange.Order,
 ) ([]exchange.Order, error) {
@@ -290,7 +333,7 @@ func (self *Woo) sell(
 		} else {
 			log.Println("[FILLED] " + string(data))
 			if notify.CanSend(level, notify.FILLED) && service != nil {
-				err := service.SendMessage(order, fmt.Sprintf("Woo - Done %s (Reason: Filled)", order.Side), model.ALWAYS)
+				err := service.SendMessage(order, fmt.Sprintf("Woo - Done %s (Reason: Filled)%s", order.Side, values.value(ctx, order.Symbol, order.Executed, order.ExecutedAt())), model.ALWAYS)
 				if err != nil {
 					log.Printf("[ERROR] %v", err)
 				}
@@ -316,7 +359,7 @@ func (self *Woo) sell(
 
 			// round to precision if (a) fees got deducted, or (b) we added up partial matches
 			if qty < new[i].Quantity || qty > new[i].QuantityMinusFee() {
-				prec, err := self.GetSizePrec(client, new[i].Symbol)
+				prec, err := self.GetSizePrec(ctx, client, new[i].Symbol)
 				if err != nil {
 					logger.Error(self.Name, err, level, service)
 				} else {
@@ -327,17 +370,18 @@ func (self *Woo) sell(
 			// get base currency and desired size, calculate price, place sell order
 			base, quote, err := self.parseMarket(new[i].Symbol)
 			if err == nil {
-				qty = self.GetMaxSize(client, base, quote, hold.HasMarket(new[i].Symbol), earn.HasMarket(new[i].Symbol), qty, mult)
+				fees := model.GetFees(ctx, self, client, new[i].Symbol)
+				qty = self.GetMaxSize(ctx, client, base, quote, hold.HasMarket(new[i].Symbol), earn.HasMarket(new[i].Symbol), qty, fees.Mult(mult))
 				if qty > 0 {
 					var prec int
-					prec, err = self.GetPricePrec(client, new[i].Symbol)
+					prec, err = self.GetPricePrec(ctx, client, new[i].Symbol)
 					if err == nil {
 						_, err = client.Order(
 							new[i].Symbol,
 							exchange.OrderSideSell,
 							exchange.OrderTypeLimit,
 							qty,
-							pricing.Multiply(new[i].ExecutedAt(), mult, prec),
+							pricing.Multiply(new[i].ExecutedAt(), fees.Mult(mult), prec),
 							"NEF2021xxxxxxx",
 						)
 					}
@@ -359,6 +403,7 @@ func (self *Woo) sell(
 }
 
 func (self *Woo) Sell(
+	ctx context.Context,
 	strategy model.Strategy,
 	hold, earn model.Markets,
 	sandbox, tweet, debug bool,
@@ -378,7 +423,8 @@ func (self *Woo) Sell(
 		return err
 	}
 
-	client := exchange.New(self.getBaseURL(sandbox), apiKey, apiSecret)
+	client := exchange.New(self.getBaseURL(sandbox), apiKey, apiSecret).WithContext(ctx)
+	values := newFillValuer(self, client)
 
 	symbols, err := self.getSymbols(client, true)
 	if err != nil {
@@ -412,7 +458,10 @@ func (self *Woo) Sell(
 		return err
 	}
 
-	for {
+	for !shutdown.Stopping() {
+		if !breaker.Get(self.Name).Wait() {
+			break
+		}
 		// read the dynamic settings
 		var (
 			level int64 = notify.LEVEL_DEFAULT
@@ -424,29 +473,45 @@ func (self *Woo) Sell(
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listen to the filled orders, look for newly filled orders, automatically place new LIMIT SELL orders.
-		if filled, err = self.sell(client, mult, hold, earn, service, level, filled); err != nil {
+		if filled, err = self.sell(ctx, client, mult, hold, earn, service, values, level, filled); err != nil {
 			logger.Error(self.Name, err, level, service)
 		} else
 		// listen to the opened orders, look for cancelled orders, send a notification.
 		if opened, err = self.listen(client, service, level, opened, filled); err != nil {
 			logger.Error(self.Name, err, level, service)
 		}
+		breaker.Get(self.Name).Done(err, service, level)
 	}
+
+	return nil
 }
 
 func (self *Woo) Order(
-	client interface{},
+	ctx context.Context,
+	client model.Client,
 	side model.OrderSide,
 	market string,
 	size float64,
 	price float64,
 	kind model.OrderType,
+	opts *model.OrderOptions,
 	metadata string,
 ) (oid []byte, raw []byte, err error) {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, nil, errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
+
+	if err = opts.Validate(kind); err != nil {
+		return nil, nil, err
+	}
+	if kind == model.STOP_LIMIT {
+		return nil, nil, orderNotSupported(self.Name, kind.String())
+	}
+	if opts.GetTimeInForce() == model.GTT {
+		return nil, nil, orderNotSupported(self.Name, "GTT")
+	}
 
 	var order *exchange.NewOrder
 	if order, err = wooClient.Order(market, func() exchange.OrderSide {
@@ -458,6 +523,12 @@ func (self *Woo) Order(
 	}(), func() exchange.OrderType {
 		if kind == model.MARKET {
 			return exchange.OrderTypeMarket
+		} else if opts.IsPostOnly() {
+			return exchange.OrderTypePostOnly
+		} else if opts.GetTimeInForce() == model.IOC {
+			return exchange.OrderTypeIOC
+		} else if opts.GetTimeInForce() == model.FOK {
+			return exchange.OrderTypeFOK
 		} else {
 			return exchange.OrderTypeLimit
 		}
@@ -472,19 +543,60 @@ func (self *Woo) Order(
 	return []byte(strconv.FormatInt(order.ID, 10)), raw, nil
 }
 
-func (self *Woo) StopLoss(client interface{}, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
+func (self *Woo) StopLoss(ctx context.Context, client model.Client, market string, size float64, price float64, kind model.OrderType, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *Woo) OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
+func (self *Woo) OCO(ctx context.Context, client model.Client, market string, size float64, price, stop float64, metadata string) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (self *Woo) GetClosed(client interface{}, market string) (model.Orders, error) {
+func (self *Woo) toOrder(order *exchange.Order) (*model.Order, error) {
+	base, quote, err := exchange.ParseSymbol(order.Symbol)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	out := &model.Order{
+		ID:        strconv.FormatInt(order.OrderID, 10),
+		Side:      model.BUY,
+		Type:      model.LIMIT,
+		Market:    order.Symbol,
+		Size:      order.Quantity,
+		Price:     order.Price,
+		Filled:    order.Executed,
+		AvgPrice:  order.AverageExecutedPrice,
+		Fee:       order.TotalFee,
+		FeeAsset:  base, // buy orders pay their fees in base currency
+		CreatedAt: order.CreatedAt(),
+	}
+	if order.Side == exchange.OrderSideSell {
+		out.Side = model.SELL
+		out.FeeAsset = quote // sell orders pay their fees in quote currency
+	}
+	if order.Type == exchange.OrderTypeMarket {
+		out.Type = model.MARKET
+	}
+	switch order.Status {
+	case exchange.OrderStatusNew:
+		out.Status = model.OPEN
+	case exchange.OrderStatusPartialFilled:
+		out.Status = model.PARTIALLY_FILLED
+	case exchange.OrderStatusFilled:
+		out.Status = model.FILLED
+	case exchange.OrderStatusCancelled:
+		out.Status = model.CANCELLED
+	case exchange.OrderStatusRejected:
+		out.Status = model.REJECTED
+	}
+	return out, nil
+}
+
+func (self *Woo) GetClosed(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
 
 	var (
 		err    error
@@ -497,28 +609,24 @@ func (self *Woo) GetClosed(client interface{}, market string) (model.Orders, err
 	}
 
 	for _, order := range orders {
-		output = append(output, model.Order{
-			Side: func() model.OrderSide {
-				if order.Side == exchange.OrderSideSell {
-					return model.SELL
-				}
-				return model.BUY
-			}(),
-			Market:    market,
-			Size:      order.QuantityMinusFee(),
-			Price:     order.ExecutedAt(),
-			CreatedAt: order.CreatedAt(),
-		})
+		closed, err := self.toOrder(&order)
+		if err != nil {
+			return nil, err
+		}
+		closed.Size = order.QuantityMinusFee()
+		closed.Price = order.ExecutedAt()
+		output = append(output, *closed)
 	}
 
 	return output, nil
 }
 
-func (self *Woo) GetOpened(client interface{}, market string) (model.Orders, error) {
+func (self *Woo) GetOpened(ctx context.Context, client model.Client, market string) (model.Orders, error) {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
 
 	var (
 		err    error
@@ -531,28 +639,93 @@ func (self *Woo) GetOpened(client interface{}, market string) (model.Orders, err
 	}
 
 	for _, order := range orders {
-		output = append(output, model.Order{
-			Side: func() model.OrderSide {
-				if order.Side == exchange.OrderSideSell {
-					return model.SELL
-				}
-				return model.BUY
-			}(),
+		opened, err := self.toOrder(&order)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, *opened)
+	}
+
+	return output, nil
+}
+
+func (self *Woo) GetOrder(ctx context.Context, client model.Client, market string, id string) (*model.Order, error) {
+	wooClient, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	wooClient = wooClient.WithContext(ctx)
+
+	orderID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("order ID %s is invalid", id)
+	}
+
+	order, err := wooClient.GetOrder(orderID)
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	return self.toOrder(order)
+}
+
+func (self *Woo) GetFills(ctx context.Context, client model.Client, market string, since time.Time) (model.Fills, error) {
+	wooClient, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	wooClient = wooClient.WithContext(ctx)
+
+	var (
+		err    error
+		orders []exchange.Order
+		output model.Fills
+	)
+
+	var base, quote string
+	if base, quote, err = exchange.ParseSymbol(market); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	// the fills of an order are aggregated into the order
+	if orders, err = wooClient.Orders(market, exchange.OrderStatusFilled); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+
+	for _, order := range orders {
+		if order.CreatedAt().Before(since) {
+			continue
+		}
+		fill := model.Fill{
+			ID:        strconv.FormatInt(order.OrderID, 10),
+			OrderID:   strconv.FormatInt(order.OrderID, 10),
+			Side:      model.BUY,
 			Market:    market,
-			Size:      order.Quantity,
-			Price:     order.Price,
+			Price:     order.ExecutedAt(),
+			Size:      order.Executed,
+			Fee:       order.TotalFee,
+			FeeAsset:  base, // buy orders pay their fees in base currency
 			CreatedAt: order.CreatedAt(),
-		})
+		}
+		if order.Side == exchange.OrderSideSell {
+			fill.Side = model.SELL
+			fill.FeeAsset = quote // sell orders pay their fees in quote currency
+		}
+		if fill.Size == 0 {
+			fill.Size = order.Quantity
+		}
+		output = append(output, fill)
 	}
 
 	return output, nil
 }
 
-func (self *Woo) GetBook(client interface{}, market string, side model.BookSide) (interface{}, error) {
+func (self *Woo) GetBook(ctx context.Context, client model.Client, market string, side model.BookSide) (interface{}, error) {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
 
 	book, err := wooClient.OrderBook(market)
 	if err != nil {
@@ -568,13 +741,13 @@ func (self *Woo) GetBook(client interface{}, market string, side model.BookSide)
 	}(), nil
 }
 
-func (self *Woo) Aggregate(client, book interface{}, market string, agg float64) (model.Book, error) {
+func (self *Woo) Aggregate(ctx context.Context, client model.Client, book interface{}, market string, agg float64) (model.Book, error) {
 	bids, ok := book.([]exchange.BookEntry)
 	if !ok {
 		return nil, errors.New("invalid argument: book")
 	}
 
-	prec, err := self.GetPricePrec(client, market)
+	prec, err := self.GetPricePrec(ctx, client, market)
 	if err != nil {
 		return nil, err
 	}
@@ -598,11 +771,12 @@ func (self *Woo) Aggregate(client, book interface{}, market string, agg float64)
 	return out, nil
 }
 
-func (self *Woo) GetTicker(client interface{}, market string) (float64, error) {
+func (self *Woo) GetTicker(ctx context.Context, client model.Client, market string) (float64, error) {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return 0, errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
 
 	ticker, err := wooClient.Ticker(market)
 	if err != nil {
@@ -612,11 +786,16 @@ func (self *Woo) GetTicker(client interface{}, market string) (float64, error) {
 	return ticker.LastPrice, nil
 }
 
-func (self *Woo) Get24h(client interface{}, market string) (*model.Stats, error) {
+func (self *Woo) GetCandles(ctx context.Context, client model.Client, market string, interval time.Duration) (model.Candles, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (self *Woo) Get24h(ctx context.Context, client model.Client, market string) (*model.Stats, error) {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return nil, errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
 
 	ticker, err := wooClient.Ticker(market)
 	if err != nil {
@@ -643,11 +822,40 @@ func (self *Woo) Get24h(client interface{}, market string) (*model.Stats, error)
 	}, nil
 }
 
-func (self *Woo) GetPricePrec(client interface{}, market string) (int, error) {
+func (self *Woo) GetFees(ctx context.Context, client model.Client, market string) (*model.Fees, error) {
+	return lowestTier(0.0002, 0.0005), nil
+}
+
+func (self *Woo) GetBalances(ctx context.Context, client model.Client) (model.Balances, error) {
+	wooClient, ok := client.(*exchange.Client)
+	if !ok {
+		return nil, errors.New("invalid argument: client")
+	}
+	wooClient = wooClient.WithContext(ctx)
+	holding, err := wooClient.Holding()
+	if err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	var out model.Balances
+	for _, token := range holding {
+		if token.Holding > 0 {
+			out = append(out, model.Balance{
+				Asset:     token.Token,
+				Total:     token.Holding,
+				Available: token.Holding - token.Frozen,
+				Locked:    token.Frozen,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (self *Woo) GetPricePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return 8, errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
 
 	symbol, err := self.getSymbol(wooClient, market, true)
 	if err != nil {
@@ -657,11 +865,12 @@ func (self *Woo) GetPricePrec(client interface{}, market string) (int, error) {
 	return precision.Parse(strconv.FormatFloat(symbol.QuoteTick, 'f', -1, 64), 8), nil
 }
 
-func (self *Woo) GetSizePrec(client interface{}, market string) (int, error) {
+func (self *Woo) GetSizePrec(ctx context.Context, client model.Client, market string) (int, error) {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return 8, errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
 
 	symbol, err := self.getSymbol(wooClient, market, true)
 	if err != nil {
@@ -671,14 +880,14 @@ func (self *Woo) GetSizePrec(client interface{}, market string) (int, error) {
 	return precision.Parse(strconv.FormatFloat(symbol.BaseTick, 'f', -1, 64), 0), nil
 }
 
-func (self *Woo) GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
+func (self *Woo) GetMaxSize(ctx context.Context, client model.Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64 {
 	if hold {
 		if base == "WOO" {
 			return 0
 		}
 	}
-	return model.GetSizeMax(hold, earn, def, mult, func() int {
-		prec, err := self.GetSizePrec(client, self.FormatMarket(base, quote))
+	return model.GetSizeMax(hold, earn, def, mult, model.GetFees(ctx, self, client, self.FormatMarket(base, quote)), func() int {
+		prec, err := self.GetSizePrec(ctx, client, self.FormatMarket(base, quote))
 		if err != nil {
 			return 0
 		}
@@ -686,11 +895,12 @@ func (self *Woo) GetMaxSize(client interface{}, base, quote string, hold, earn b
 	})
 }
 
-func (self *Woo) Cancel(client interface{}, market string, side model.OrderSide) error {
+func (self *Woo) Cancel(ctx context.Context, client model.Client, market string, side model.OrderSide) error {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
 
 	orders, err := wooClient.Orders(market, exchange.OrderStatusIncomplete)
 	if err != nil {
@@ -708,11 +918,31 @@ func (self *Woo) Cancel(client interface{}, market string, side model.OrderSide)
 	return nil
 }
 
-func (self *Woo) Buy(client interface{}, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
+func (self *Woo) CancelOrder(ctx context.Context, client model.Client, market string, id string) error {
+	wooClient, ok := client.(*exchange.Client)
+	if !ok {
+		return errors.New("invalid argument: client")
+	}
+	wooClient = wooClient.WithContext(ctx)
+
+	orderID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return errors.Errorf("order ID %s is invalid", id)
+	}
+
+	if err = wooClient.CancelOrder(market, orderID); err != nil {
+		return errors.Wrap(err, 1)
+	}
+
+	return nil
+}
+
+func (self *Woo) Buy(ctx context.Context, client model.Client, cancel bool, market string, calls model.Calls, deviation float64, kind model.OrderType) error {
 	wooClient, ok := client.(*exchange.Client)
 	if !ok {
 		return errors.New("invalid argument: client")
 	}
+	wooClient = wooClient.WithContext(ctx)
 
 	// step #1: delete the buy order(s) that are open in your book
 	if cancel {
@@ -743,7 +973,7 @@ func (self *Woo) Buy(client interface{}, cancel bool, market string, calls model
 				limit float64 = call.Price
 			)
 			if deviation != 1.0 {
-				kind, limit = call.Deviate(self, client, kind, deviation)
+				kind, limit = call.Deviate(ctx, self, client, kind, deviation)
 			}
 			// --- BEGIN --- svanas 2021-07-25 --- order value should be greater or equal to X ----
 			symbol, err := self.getSymbol(wooClient, market, true)
@@ -752,12 +982,12 @@ func (self *Woo) Buy(client interface{}, cancel bool, market string, calls model
 			}
 			if symbol.MinNotional > 0 {
 				if limit == 0 {
-					if limit, err = self.GetTicker(client, market); err != nil {
+					if limit, err = self.GetTicker(ctx, client, market); err != nil {
 						return err
 					}
 				}
 				if (qty * limit) < symbol.MinNotional {
-					prec, err := self.GetSizePrec(client, market)
+					prec, err := self.GetSizePrec(ctx, client, market)
 					if err != nil {
 						return err
 					}
@@ -786,7 +1016,7 @@ func (self *Woo) IsLeveragedToken(name string) bool {
 	return false
 }
 
-func (self *Woo) HasAlgoOrder(client interface{}, market string) (bool, error) {
+func (self *Woo) HasAlgoOrder(ctx context.Context, client model.Client, market string) (bool, error) {
 	return false, nil
 }
 
@@ -2,9 +2,10 @@
 package gdax
 
 import (
+	"context"
+
 	exchange "github.com/svanas/go-coinbasepro"
-	"net/http"
-	"time"
+	"github.com/svanas/nefertiti/transport"
 )
 
 const (
@@ -16,6 +17,18 @@ type Client struct {
 	*exchange.Client
 }
 
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (self *Client) Exchange() string {
+	return "Coinbase Pro"
+}
+
+// WithContext returns a copy of the client whose requests are cancelled once ctx is done
+func (self *Client) WithContext(ctx context.Context) *Client {
+	inner := *self.Client
+	inner.HTTPClient = transport.WithContext(self.Client.HTTPClient, ctx)
+	return &Client{&inner}
+}
+
 func (self *Client) CreateOrder(neworder *Order) (*Order, error) {
 	var (
 		err       error
@@ -49,9 +62,7 @@ func (self *Client) GetOrder(id string) (*Order, error) {
 func New(sandbox bool) *Client {
 	client := exchange.NewClient()
 
-	client.HTTPClient = &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	client.HTTPClient = transport.New()
 
 	if sandbox {
 		client.UpdateConfig(&exchange.ClientConfig{
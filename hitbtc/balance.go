@@ -0,0 +1,28 @@
+package hitbtc
+
+import (
+	"encoding/json"
+)
+
+type Balance struct {
+	Currency  string  `json:"currency"`
+	Available float64 `json:"available,string"`
+	Reserved  float64 `json:"reserved,string"`
+}
+
+// GetBalances returns the balances of our trading account.
+func (b *HitBtc) GetBalances() (balances []Balance, err error) {
+	r, err := b.client.do("GET", "trading/balance", nil, true)
+	if err != nil {
+		return
+	}
+	var response interface{}
+	if err = json.Unmarshal(r, &response); err != nil {
+		return
+	}
+	if err = handleErr(response); err != nil {
+		return
+	}
+	err = json.Unmarshal(r, &balances)
+	return
+}
@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/svanas/nefertiti/transport"
 )
 
 type client struct {
@@ -49,7 +51,7 @@ func NewClientWithCustomHttpConfig(apiKey, apiSecret string, httpClient *http.Cl
 
 // NewClient returns a new HitBtc HTTP client with custom timeout
 func NewClientWithCustomTimeout(apiKey, apiSecret string, timeout time.Duration) (c *client) {
-	return NewClientWithCustomHttpConfig(apiKey, apiSecret, &http.Client{Timeout: timeout})
+	return NewClientWithCustomHttpConfig(apiKey, apiSecret, &http.Client{Timeout: timeout, Transport: &transport.Transport{}})
 }
 
 // do prepare and process HTTP request to HitBtc API
@@ -2,6 +2,7 @@
 package hitbtc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/svanas/nefertiti/transport"
 )
 
 const (
@@ -61,6 +64,18 @@ type HitBtc struct {
 	client *client
 }
 
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (b *HitBtc) Exchange() string {
+	return "HitBTC"
+}
+
+// WithContext returns a copy of the client whose requests are cancelled once ctx is done
+func (b *HitBtc) WithContext(ctx context.Context) *HitBtc {
+	inner := *b.client
+	inner.httpClient = transport.WithContext(b.client.httpClient, ctx)
+	return &HitBtc{client: &inner}
+}
+
 // GetSymbols is used to get the open and available trading markets at HitBtc along with other meta data.
 func (b *HitBtc) GetSymbols() (symbols []Symbol, err error) {
 	r, err := b.client.do("GET", "public/symbol", nil, false)
@@ -117,6 +132,42 @@ func (b *HitBtc) GetTrades(currencyPair string) (trades []Trade, err error) {
 	return
 }
 
+// GetTradesSince is like GetTrades, but pages through the entire trade history since a point in time (zero for everything).
+func (b *HitBtc) GetTradesSince(currencyPair string, from time.Time) (trades []Trade, err error) {
+	const limit = 1000
+	for offset := 0; ; offset += limit {
+		payload := make(map[string]string)
+		if currencyPair != "all" {
+			payload["symbol"] = currencyPair
+		}
+		payload["sort"] = "ASC"
+		payload["limit"] = strconv.Itoa(limit)
+		payload["offset"] = strconv.Itoa(offset)
+		if !from.IsZero() {
+			payload["from"] = from.UTC().Format(time.RFC3339)
+		}
+		var r []byte
+		if r, err = b.client.do("GET", "history/trades", payload, true); err != nil {
+			return
+		}
+		var response interface{}
+		if err = json.Unmarshal(r, &response); err != nil {
+			return
+		}
+		if err = handleErr(response); err != nil {
+			return
+		}
+		var page []Trade
+		if err = json.Unmarshal(r, &page); err != nil {
+			return
+		}
+		trades = append(trades, page...)
+		if len(page) < limit {
+			return
+		}
+	}
+}
+
 func (b *HitBtc) CancelOrder(currencyPair string) (orders []Order, err error) {
 	payload := make(map[string]string)
 	if currencyPair != "all" {
@@ -171,6 +222,40 @@ func (b *HitBtc) GetOrder(orderId string) (orders []Order, err error) {
 	return
 }
 
+// GetActiveOrder returns an open order by its client order ID
+func (b *HitBtc) GetActiveOrder(clientOrderId string) (order Order, err error) {
+	r, err := b.client.do("GET", "order/"+clientOrderId, nil, true)
+	if err != nil {
+		return
+	}
+	var response interface{}
+	if err = json.Unmarshal(r, &response); err != nil {
+		return
+	}
+	if err = handleErr(response); err != nil {
+		return
+	}
+	err = json.Unmarshal(r, &order)
+	return
+}
+
+// GetOrderTrades returns the trades (aka fills) of one order
+func (b *HitBtc) GetOrderTrades(orderId uint64) (trades []Trade, err error) {
+	r, err := b.client.do("GET", fmt.Sprintf("history/order/%d/trades", orderId), nil, true)
+	if err != nil {
+		return
+	}
+	var response interface{}
+	if err = json.Unmarshal(r, &response); err != nil {
+		return
+	}
+	if err = handleErr(response); err != nil {
+		return
+	}
+	err = json.Unmarshal(r, &trades)
+	return
+}
+
 func (b *HitBtc) GetOrderHistory() (orders []Order, err error) {
 	r, err := b.client.do("GET", "history/order", nil, true)
 	if err != nil {
@@ -20,6 +20,15 @@ const (
 	GTD = "GTD"
 )
 
+const (
+	ORDER_STATUS_NEW              = "new"
+	ORDER_STATUS_SUSPENDED        = "suspended"
+	ORDER_STATUS_PARTIALLY_FILLED = "partiallyFilled"
+	ORDER_STATUS_FILLED           = "filled"
+	ORDER_STATUS_CANCELED         = "canceled"
+	ORDER_STATUS_EXPIRED          = "expired"
+)
+
 type Order struct {
 	Id            uint64    `json:"id"`
 	ClientOrderId string    `json:"clientOrderId"`
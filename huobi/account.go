@@ -65,3 +65,39 @@ func (client *Client) Account(accountType AccountType, accountState AccountState
 	}
 	return nil, fmt.Errorf("account not found")
 }
+
+type Balance struct {
+	Currency string  `json:"currency"`
+	Type     string  `json:"type"` // trade (available) or frozen (reserved for our open orders)
+	Balance  float64 `json:"balance,string"`
+}
+
+// Balances returns the balances of the spot account, two per currency: one for trade, one for frozen.
+func (client *Client) Balances() ([]Balance, error) {
+	type Response struct {
+		Data struct {
+			List []Balance `json:"list"`
+		} `json:"data"`
+	}
+
+	var (
+		err     error
+		body    []byte
+		resp    Response
+		account *Account
+	)
+
+	if account, err = client.Account(AccountTypeSpot, AccountStateWorking); err != nil {
+		return nil, err
+	}
+
+	if body, err = client.get(fmt.Sprintf("/v1/account/accounts/%d/balance", account.Id), nil, true); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data.List, nil
+}
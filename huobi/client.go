@@ -2,12 +2,15 @@ package huobi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/svanas/nefertiti/transport"
 )
 
 var (
@@ -37,14 +40,24 @@ type Client struct {
 	httpClient *http.Client
 }
 
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (client *Client) Exchange() string {
+	return "Huobi"
+}
+
+// WithContext returns a copy of the client whose requests are cancelled once ctx is done
+func (client *Client) WithContext(ctx context.Context) *Client {
+	out := *client
+	out.httpClient = transport.WithContext(client.httpClient, ctx)
+	return &out
+}
+
 func New(URL, apiKey, apiSecret string) *Client {
 	return &Client{
 		URL,
 		apiKey,
 		apiSecret,
-		&http.Client{
-			Timeout: 30 * time.Second,
-		},
+		transport.New(),
 	}
 }
 
@@ -60,8 +73,11 @@ func (client *Client) do(req *http.Request) ([]byte, error) {
 		return nil, err
 	}
 
-	if err, msg := IsError(body); err {
-		return nil, errors.New(msg)
+	if err, ok := IsError(body); ok {
+		if isTimestampError(err) {
+			Clock.Invalidate()
+		}
+		return nil, err
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
@@ -72,6 +88,11 @@ func (client *Client) do(req *http.Request) ([]byte, error) {
 }
 
 func (client *Client) get(path string, query url.Values, auth bool) ([]byte, error) {
+	// sign our request with a timestamp that Huobi agrees with
+	if auth {
+		client.syncTime()
+	}
+
 	// respect the rate limit
 	err := BeforeRequest(http.MethodGet, func() string {
 		if query == nil {
@@ -102,7 +123,7 @@ func (client *Client) get(path string, query url.Values, auth bool) ([]byte, err
 		query.Add("AccessKeyId", client.apiKey)
 		query.Add("SignatureMethod", "HmacSHA256")
 		query.Add("SignatureVersion", "2")
-		query.Add("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05"))
+		query.Add("Timestamp", Clock.Now().UTC().Format("2006-01-02T15:04:05"))
 		query.Add("Signature", sign(client.apiSecret, http.MethodGet, endpoint.Host, path, query))
 	}
 
@@ -122,6 +143,9 @@ func (client *Client) get(path string, query url.Values, auth bool) ([]byte, err
 }
 
 func (client *Client) post(path string, params interface{}) ([]byte, error) {
+	// sign our request with a timestamp that Huobi agrees with
+	client.syncTime()
+
 	// respect the rate limit
 	err := BeforeRequest(http.MethodPost, path)
 	if err != nil {
@@ -143,7 +167,7 @@ func (client *Client) post(path string, params interface{}) ([]byte, error) {
 	query.Add("AccessKeyId", client.apiKey)
 	query.Add("SignatureMethod", "HmacSHA256")
 	query.Add("SignatureVersion", "2")
-	query.Add("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05"))
+	query.Add("Timestamp", Clock.Now().UTC().Format("2006-01-02T15:04:05"))
 	query.Add("Signature", sign(client.apiSecret, http.MethodPost, endpoint.Host, path, query))
 	endpoint.RawQuery = query.Encode()
 
@@ -0,0 +1,45 @@
+package huobi
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+type Kline struct {
+	Id     int64   `json:"id"` // unix time in seconds
+	Open   float64 `json:"open"`
+	Close  float64 `json:"close"`
+	Low    float64 `json:"low"`
+	High   float64 `json:"high"`
+	Amount float64 `json:"amount"` // volume in base currency
+	Vol    float64 `json:"vol"`    // volume in quote currency
+}
+
+// Klines returns the candlestick data, newest kline first. Period is one of 1min, 5min, 15min, 30min, 60min, 4hour, 1day, 1mon, 1week, 1year
+func (client *Client) Klines(symbol, period string, size int) ([]Kline, error) {
+	type Response struct {
+		Data []Kline `json:"data"`
+	}
+
+	var (
+		err  error
+		body []byte
+		resp Response
+	)
+
+	params := url.Values{}
+	params.Add("symbol", symbol)
+	params.Add("period", period)
+	params.Add("size", strconv.Itoa(size))
+
+	if body, err = client.get("/market/history/kline", params, false); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data, nil
+}
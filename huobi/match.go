@@ -0,0 +1,103 @@
+package huobi
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MatchResult is a fill of one of our orders
+type MatchResult struct {
+	Id           int64     `json:"id"`
+	OrderId      int64     `json:"order-id"`
+	TradeId      int64     `json:"trade-id"`
+	Symbol       string    `json:"symbol"`
+	OrderType    OrderType `json:"type"`
+	Price        float64   `json:"price,string"`
+	FilledAmount float64   `json:"filled-amount,string"`
+	FilledFees   float64   `json:"filled-fees,string"`
+	FeeCurrency  string    `json:"fee-currency"`
+	CreatedAt    int64     `json:"created-at"` // the timestamp in milliseconds when the order was matched
+}
+
+func (match *MatchResult) IsBuy() bool {
+	order := Order{OrderType: match.OrderType}
+	return order.IsBuy()
+}
+
+func (match *MatchResult) GetCreatedAt() time.Time {
+	return time.Unix(0, match.CreatedAt*int64(time.Millisecond))
+}
+
+// MatchResults returns the fills of our orders since a point in time. Huobi keeps the fills of the last 120 days.
+func (client *Client) MatchResults(symbol string, since time.Time) ([]MatchResult, error) {
+	type Response struct {
+		Data []MatchResult `json:"data"`
+	}
+
+	const (
+		size   = 500
+		window = 48 * time.Hour // the max time range per request
+	)
+
+	var (
+		err  error
+		out  []MatchResult
+		seen = make(map[int64]bool) // the windows share their edges, and "from" is inclusive
+	)
+
+	oldest := time.Now().UTC().AddDate(0, 0, -120)
+	if since.After(oldest) {
+		oldest = since
+	}
+
+	end := time.Now().UTC()
+	for end.After(oldest) {
+		start := end.Add(-window)
+		if start.Before(oldest) {
+			start = oldest
+		}
+
+		var from int64
+		for {
+			var (
+				body []byte
+				resp Response
+			)
+
+			params := url.Values{}
+			params.Add("symbol", symbol)
+			params.Add("start-time", strconv.FormatInt(start.UnixNano()/1000000, 10))
+			params.Add("end-time", strconv.FormatInt(end.UnixNano()/1000000, 10))
+			params.Add("size", strconv.Itoa(size))
+			if from > 0 {
+				params.Add("from", strconv.FormatInt(from, 10))
+				params.Add("direct", "prev")
+			}
+
+			if body, err = client.get("/v1/order/matchresults", params, true); err != nil {
+				return nil, err
+			}
+
+			if err = json.Unmarshal(body, &resp); err != nil {
+				return nil, err
+			}
+
+			for _, match := range resp.Data {
+				if !seen[match.Id] {
+					seen[match.Id] = true
+					out = append(out, match)
+				}
+			}
+			if len(resp.Data) < size {
+				break
+			}
+			from = resp.Data[len(resp.Data)-1].Id
+		}
+
+		end = start
+	}
+
+	return out, nil
+}
@@ -164,6 +164,28 @@ func (client *Client) OpenOrders(symbol string) ([]Order, error) {
 	return resp.Data, nil
 }
 
+func (client *Client) GetOrder(orderId int64) (*Order, error) {
+	type Response struct {
+		Data Order `json:"data"`
+	}
+
+	var (
+		err  error
+		body []byte
+		resp Response
+	)
+
+	if body, err = client.get(fmt.Sprintf("/v1/order/orders/%d", orderId), url.Values{}, true); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
 func (client *Client) CancelOrder(orderId int64) error {
 	_, err := client.post(fmt.Sprintf("/v1/order/orders/%d/submitcancel", orderId), nil)
 	return err
@@ -5,14 +5,19 @@ import (
 )
 
 type Response struct {
-	Status string `json:"status"`
-	ErrMsg string `json:"err-msg"`
+	Status  string `json:"status"`
+	ErrCode string `json:"err-code"`
+	ErrMsg  string `json:"err-msg"`
 }
 
-func IsError(body []byte) (bool, string) {
+func (resp *Response) Error() string {
+	return resp.ErrMsg
+}
+
+func IsError(body []byte) (*Response, bool) {
 	var resp Response
-	if json.Unmarshal(body, &resp) == nil {
-		return resp.Status != "ok", resp.ErrMsg
+	if json.Unmarshal(body, &resp) == nil && resp.Status != "ok" {
+		return &resp, true
 	}
-	return false, ""
+	return nil, false
 }
@@ -0,0 +1,40 @@
+package huobi
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/clock"
+)
+
+// Clock keeps track of the offset between our clock and the Huobi clock
+var Clock = clock.New("Huobi")
+
+// ServerTime returns the API server time.
+func (client *Client) ServerTime() (time.Time, error) {
+	body, err := client.get("/v1/common/timestamp", nil, false)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var resp struct {
+		Data int64 `json:"data"`
+	}
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, resp.Data*int64(time.Millisecond)), nil
+}
+
+// syncTime measures the offset between our clock and the Huobi clock, unless we did so lately.
+func (client *Client) syncTime() {
+	if err := Clock.Sync(client.ServerTime); err != nil {
+		log.Printf("[WARNING] %v\n", err)
+	}
+}
+
+// isTimestampError returns true if Huobi rejected our request because of its timestamp
+func isTimestampError(resp *Response) bool {
+	return strings.Contains(strings.ToLower(resp.ErrCode+" "+resp.ErrMsg), "timestamp")
+}
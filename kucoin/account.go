@@ -30,3 +30,19 @@ func (as *ApiService) Accounts(currency, typo string) (*ApiResponse, error) {
 	req := NewRequest(http.MethodGet, "/api/v1/accounts", p)
 	return as.call(req, requestsPerSecond)
 }
+
+// A TradeFeeModel represents the fee rates of a symbol.
+type TradeFeeModel struct {
+	Symbol       string `json:"symbol"`
+	TakerFeeRate string `json:"takerFeeRate"`
+	MakerFeeRate string `json:"makerFeeRate"`
+}
+
+// A TradeFeesModel is the set of *TradeFeeModel.
+type TradeFeesModel []*TradeFeeModel
+
+// TradeFees returns the actual fee rates of the trading pair(s). Symbols is a comma-separated list, max 10 symbols.
+func (as *ApiService) TradeFees(symbols string) (*ApiResponse, error) {
+	req := NewRequest(http.MethodGet, "/api/v1/trade-fees", map[string]string{"symbols": symbols})
+	return as.call(req, requestsPerSecond)
+}
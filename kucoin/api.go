@@ -2,6 +2,7 @@ package kucoin
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -45,6 +46,22 @@ type ApiService struct {
 	signer           *KcSigner
 }
 
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (as *ApiService) Exchange() string {
+	return "KuCoin"
+}
+
+// WithContext returns a copy of the service whose requests are cancelled once ctx is done
+func (as *ApiService) WithContext(ctx context.Context) *ApiService {
+	out := *as
+	if br, ok := as.requester.(*BasicRequester); ok {
+		clone := *br
+		clone.ctx = ctx
+		out.requester = &clone
+	}
+	return &out
+}
+
 // ProductionApiBaseURI is api base uri for production.
 const ProductionApiBaseURI = "https://api.kucoin.com"
 
@@ -110,6 +127,10 @@ func NewApiService(opts ...ApiServiceOption) *ApiService {
 
 // Call calls the API by passing *Request and returns *ApiResponse.
 func (as *ApiService) call(request *Request, rps float64) (*ApiResponse, error) {
+	// sign our request with a timestamp that KuCoin agrees with
+	if as.signer != nil && request.Path != timestampPath {
+		as.syncTime()
+	}
 	// --- BEGIN --- svanas 2019-02-13 --- satisfy the rate limiter -------
 	if err := BeforeRequest(as, request, rps); err != nil {
 		return nil, err
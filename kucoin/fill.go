@@ -44,6 +44,15 @@ func (fill *FillModel) ParseSize() float64 {
 	return 0
 }
 
+// ParseFee returns the fee as float64
+func (fill *FillModel) ParseFee() float64 {
+	out, err := strconv.ParseFloat(fill.Fee, 64)
+	if err == nil {
+		return out
+	}
+	return 0
+}
+
 // ParseCreatedAt returns the creation time as time.Time
 func (fill *FillModel) ParseCreatedAt() time.Time {
 	return time.Unix(fill.CreatedAt/1000, 0)
@@ -2,6 +2,7 @@ package kucoin
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/svanas/nefertiti/transport"
 )
 
 // A Request represents a HTTP request.
@@ -119,12 +122,14 @@ type Requester interface {
 
 // A BasicRequester represents a basic implement of Requester by http.Client.
 type BasicRequester struct {
+	ctx context.Context // if not nil, the requests are cancelled once this context is done
 }
 
 // Request makes a http request.
 func (br *BasicRequester) Request(request *Request, timeout time.Duration) (*Response, error) {
 	cli := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: &transport.Transport{Context: br.ctx},
 	}
 
 	req, err := request.HttpRequest()
@@ -203,6 +208,11 @@ func (ar *ApiResponse) ApiSuccessful() bool {
 
 // ReadData read the api response `data` as JSON into v.
 func (ar *ApiResponse) ReadData(v interface{}) error {
+	// KC-API-TIMESTAMP is invalid? measure the offset between our clock and the KuCoin clock again
+	if ar.Code == "400002" {
+		Clock.Invalidate()
+	}
+
 	if !ar.HttpSuccessful() {
 		rsb, _ := ar.response.ReadBody()
 		m := fmt.Sprintf("[HTTP]Failure: status code is NOT 200, %s %s with body=%s, respond code=%d body=%s",
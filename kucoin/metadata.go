@@ -0,0 +1,44 @@
+package kucoin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/svanas/nefertiti/uuid"
+)
+
+const (
+	CLIENT_OID_MAX_LEN = 40
+	CLIENT_OID_PREFIX  = "nefertiti"
+)
+
+// NewClientOid returns a clientOid that tells us this order was opened by us. If metadata is not empty, then the
+// clientOid includes the metadata (for example: the price we paid for the base asset of a sell order).
+func NewClientOid(metadata string) string {
+	random := uuid.New().LongEx("")
+	if metadata != "" {
+		out := fmt.Sprintf("%s-%s-", CLIENT_OID_PREFIX, strings.Replace(metadata, ".", "_", -1))
+		if len(out) <= CLIENT_OID_MAX_LEN-8 {
+			return out + random[:CLIENT_OID_MAX_LEN-len(out)]
+		}
+	}
+	return fmt.Sprintf("%s--%s", CLIENT_OID_PREFIX, random)[:CLIENT_OID_MAX_LEN]
+}
+
+// IsClientOid returns true if the clientOid was created by NewClientOid
+func IsClientOid(clientOid string) bool {
+	return strings.HasPrefix(clientOid, CLIENT_OID_PREFIX+"-")
+}
+
+// ParseClientOid returns the metadata in a clientOid that was created by NewClientOid
+func ParseClientOid(clientOid string) (string, error) {
+	const MIN_LEN = 3
+	subs := strings.Split(clientOid, "-")
+	if !IsClientOid(clientOid) || len(subs) < MIN_LEN {
+		return "", fmt.Errorf("clientOid %s does not have metadata", clientOid)
+	}
+	if subs[1] == "" {
+		return "", fmt.Errorf("clientOid %s does not have metadata", clientOid)
+	}
+	return strings.Replace(subs[1], "_", ".", -1), nil
+}
@@ -103,6 +103,33 @@ func (o *OrderModel) ParseSize() float64 {
 	return 0
 }
 
+// ParseDealSize returns the filled quantity as float64
+func (o *OrderModel) ParseDealSize() float64 {
+	out, err := strconv.ParseFloat(o.DealSize, 64)
+	if err == nil {
+		return out
+	}
+	return 0
+}
+
+// ParseDealFunds returns the filled funds as float64
+func (o *OrderModel) ParseDealFunds() float64 {
+	out, err := strconv.ParseFloat(o.DealFunds, 64)
+	if err == nil {
+		return out
+	}
+	return 0
+}
+
+// ParseFee returns the fee as float64
+func (o *OrderModel) ParseFee() float64 {
+	out, err := strconv.ParseFloat(o.Fee, 64)
+	if err == nil {
+		return out
+	}
+	return 0
+}
+
 // ParseCreatedAt returns the creation time as time.Time
 func (o *OrderModel) ParseCreatedAt() time.Time {
 	return time.Unix(o.CreatedAt/1000, 0)
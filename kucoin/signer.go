@@ -5,7 +5,6 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"strconv"
-	"time"
 )
 
 // KcSigner is the implement of Signer for KuCoin.
@@ -39,7 +38,7 @@ func (ks *KcSigner) signPartner(timestamp string) string {
 
 // Headers returns a map of signature header.
 func (ks *KcSigner) Headers(plain string) map[string]string {
-	t := IntToString(time.Now().UnixNano() / 1000000)
+	t := IntToString(Clock.Now().UnixNano() / 1000000)
 	p := []byte(t + plain)
 	s := string(ks.sign(p))
 	pp := ks.apiPassPhrase
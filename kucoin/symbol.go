@@ -144,3 +144,18 @@ func (as *ApiService) AggregatedFullOrderBook(symbol string) (*ApiResponse, erro
 	req := NewRequest(http.MethodGet, "/api/v3/market/orderbook/level2", map[string]string{"symbol": symbol})
 	return as.call(req, 10)
 }
+
+// A KLineModel represents the k lines for a symbol: [time, open, close, high, low, volume, turnover]
+type KLineModel []string
+
+// A KLinesModel is the set of *KLineModel, newest k line first.
+type KLinesModel []*KLineModel
+
+// KLines returns the k lines for a symbol. Type is one of 1min, 3min, 5min, 15min, 30min, 1hour, 2hour, 4hour, 6hour, 8hour, 12hour, 1day, 1week
+func (as *ApiService) KLines(symbol, typo string) (*ApiResponse, error) {
+	req := NewRequest(http.MethodGet, "/api/v1/market/candles", map[string]string{
+		"symbol": symbol,
+		"type":   typo,
+	})
+	return as.call(req, requestsPerSecond)
+}
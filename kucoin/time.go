@@ -1,11 +1,37 @@
 package kucoin
 
 import (
+	"log"
 	"net/http"
+	"time"
+
+	"github.com/svanas/nefertiti/clock"
 )
 
+const timestampPath = "/api/v1/timestamp"
+
+// Clock keeps track of the offset between our clock and the KuCoin clock
+var Clock = clock.New("KuCoin")
+
 // ServerTime returns the API server time.
 func (as *ApiService) ServerTime() (*ApiResponse, error) {
-	req := NewRequest(http.MethodGet, "/api/v1/timestamp", nil)
+	req := NewRequest(http.MethodGet, timestampPath, nil)
 	return as.call(req, requestsPerSecond)
 }
+
+// syncTime measures the offset between our clock and the KuCoin clock, unless we did so lately.
+func (as *ApiService) syncTime() {
+	if err := Clock.Sync(func() (time.Time, error) {
+		resp, err := as.ServerTime()
+		if err != nil {
+			return time.Time{}, err
+		}
+		var ms int64
+		if err = resp.ReadData(&ms); err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(0, ms*int64(time.Millisecond)), nil
+	}); err != nil {
+		log.Printf("[WARNING] %v\n", err)
+	}
+}
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"log"
 	"runtime"
-	"strings"
 
+	"github.com/svanas/nefertiti/breaker"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
@@ -19,8 +19,8 @@ func Error(title string, err error, level int64, notifier model.Notify) {
 	prefix := errors.FormatCaller(pc, file, line)
 	msg := fmt.Sprintf("%s %v", prefix, err)
 
-	// exclude common TCP/IP errors that we don't want to notify the user about
-	if err.Error() == "502 Bad Gateway" || strings.Contains(err.Error(), "no such host") || strings.Contains(err.Error(), "network is unreachable") {
+	// exclude transient (TCP/IP) errors that we don't want to notify the user about
+	if errors.ClassIn(title, err) == errors.TRANSIENT {
 		log.Printf("[ERROR] %s", msg)
 		return
 	}
@@ -33,8 +33,8 @@ func Error(title string, err error, level int64, notifier model.Notify) {
 		log.Printf("[ERROR] %s", msg)
 	}
 
-	// send notification to Pushover or Telegram
-	if notifier != nil && notify.CanSend(level, notify.ERROR) {
+	// send notification to Pushover or Telegram, unless the exchange is down and we have said so already
+	if notifier != nil && notify.CanSend(level, notify.ERROR) && !breaker.IsOpen(title) {
 		err := notifier.SendMessage(msg, fmt.Sprintf("%s - ERROR", title), model.ONCE_PER_MINUTE)
 		if err != nil {
 			log.Printf("[ERROR] %v", err)
@@ -49,7 +49,7 @@ func ErrorEx(title string, err error, order interface{}, level int64, notifier m
 	prefix := errors.FormatCaller(pc, file, line)
 	msg := fmt.Sprintf("%s %v", prefix, err)
 
-	if notifier != nil && notify.CanSend(level, notify.ERROR) {
+	if notifier != nil && notify.CanSend(level, notify.ERROR) && !breaker.IsOpen(title) {
 		err := notifier.SendMessage(msg, fmt.Sprintf("%s - ERROR", title), model.ONCE_PER_MINUTE)
 		if err != nil {
 			log.Printf("[ERROR] %v", err)
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/svanas/nefertiti/breaker"
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/notify"
+)
+
+// notifier counts the notifications we send, per title
+type notifier map[string]int
+
+func (self notifier) PromptForKeys(interactive, verify bool) (bool, error) {
+	return true, nil
+}
+
+func (self notifier) SendMessage(message interface{}, title string, frequency model.Frequency) error {
+	self[title]++
+	return nil
+}
+
+// TestErrorBreaker runs the error handling of our loops (logger.Error, then breaker.Done) through an outage
+func TestErrorBreaker(t *testing.T) {
+	const name = "TestErrorBreaker"
+	service := make(notifier)
+	level := int64(notify.LEVEL_DEFAULT)
+
+	iterate := func(err error) {
+		if err != nil {
+			Error(name, err, level, service)
+		}
+		breaker.Get(name).Done(err, service, level)
+	}
+
+	// the transient errors do not get notified, until the breaker opens
+	for i := 0; i < breaker.DEFAULT_THRESHOLD; i++ {
+		iterate(errors.New("502 Bad Gateway"))
+	}
+	if !breaker.IsOpen(name) {
+		t.Fatalf("TestErrorBreaker failed, got: closed, want: open after %d transient errors.", breaker.DEFAULT_THRESHOLD)
+	}
+	if got := service[name+" - ERROR"]; got != 1 {
+		t.Errorf("TestErrorBreaker failed, got: %d notifications, want: 1 when the breaker opens.", got)
+	}
+
+	// while the breaker is open, we don't notify about the other errors either
+	iterate(errors.New("market BTC-EUR does not exist"))
+	if got := service[name+" - ERROR"]; got != 1 {
+		t.Errorf("TestErrorBreaker failed, got: %d notifications, want: 1 while the breaker is open.", got)
+	}
+
+	iterate(nil)
+	if breaker.IsOpen(name) {
+		t.Errorf("TestErrorBreaker failed, got: open, want: closed after a success.")
+	}
+	if got := service[name+" - INFO"]; got != 1 {
+		t.Errorf("TestErrorBreaker failed, got: %d notifications, want: 1 when the breaker closes.", got)
+	}
+
+	// once the exchange is up, the other errors get notified again
+	iterate(errors.New("market BTC-EUR does not exist"))
+	if got := service[name+" - ERROR"]; got != 2 {
+		t.Errorf("TestErrorBreaker failed, got: %d notifications, want: 2 after the breaker closed.", got)
+	}
+}
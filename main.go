@@ -6,13 +6,18 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/mitchellh/cli"
 	"github.com/svanas/nefertiti/command"
 	"github.com/svanas/nefertiti/errors"
 	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/shutdown"
+	"github.com/svanas/nefertiti/transport"
 )
 
 var (
@@ -23,6 +28,7 @@ var (
 var (
 	console *cli.CLI
 	port    int64 = 38700
+	sig           = make(chan os.Signal, 1)
 )
 
 func main() {
@@ -53,15 +59,39 @@ func main() {
 		"exchanges": func() (cli.Command, error) {
 			return &command.ExchangesCommand{CommandMeta: &cm}, nil
 		},
+		"history": func() (cli.Command, error) {
+			return &command.HistoryCommand{CommandMeta: &cm}, nil
+		},
+		"make": func() (cli.Command, error) {
+			return &command.MakeCommand{CommandMeta: &cm}, nil
+		},
 		"markets": func() (cli.Command, error) {
 			return &command.MarketsCommand{CommandMeta: &cm}, nil
 		},
+		"pnl": func() (cli.Command, error) {
+			return &command.PnLCommand{CommandMeta: &cm}, nil
+		},
+		"tax": func() (cli.Command, error) {
+			return &command.TaxCommand{CommandMeta: &cm}, nil
+		},
+		"portfolio": func() (cli.Command, error) {
+			return &command.PortfolioCommand{CommandMeta: &cm}, nil
+		},
+		"rebalance": func() (cli.Command, error) {
+			return &command.RebalanceCommand{CommandMeta: &cm}, nil
+		},
 		"sell": func() (cli.Command, error) {
 			return &command.SellCommand{CommandMeta: &cm}, nil
 		},
 		"order": func() (cli.Command, error) {
 			return &command.OrderCommand{CommandMeta: &cm}, nil
 		},
+		"arbitrage": func() (cli.Command, error) {
+			return &command.ArbitrageCommand{CommandMeta: &cm}, nil
+		},
+		"balances": func() (cli.Command, error) {
+			return &command.BalancesCommand{CommandMeta: &cm}, nil
+		},
 		"book": func() (cli.Command, error) {
 			return &command.BookCommand{CommandMeta: &cm}, nil
 		},
@@ -95,9 +125,15 @@ func main() {
 		"listen": func() (cli.Command, error) {
 			return &command.ListenCommand{CommandMeta: &cm}, nil
 		},
+		"enter": func() (cli.Command, error) {
+			return &command.EnterCommand{CommandMeta: &cm}, nil
+		},
 		"exit": func() (cli.Command, error) {
 			return &command.ExitCommand{CommandMeta: &cm}, nil
 		},
+		"signals report": func() (cli.Command, error) {
+			return &command.SignalsReportCommand{CommandMeta: &cm}, nil
+		},
 	}
 
 	if flag.Listen() {
@@ -140,7 +176,41 @@ func main() {
 		}()
 	}
 
+	// on ctrl+c (or kill), let the command finish its current iteration and return. on a second ctrl+c, or if the
+	// command is taking too long, cancel the requests in flight and give the command a moment to return.
+	returned := make(chan struct{})
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		log.Println("[INFO] Stopping...")
+		shutdown.Stop()
+		select {
+		case <-sig:
+		case <-returned:
+			// the command returned, and the hooks are running. no more timer: only a second ctrl+c stops them.
+			<-sig
+			os.Exit(1)
+		case <-time.After(2 * transport.Timeout()):
+			// the command is taking too long. run the hooks while our requests still work, then cancel them.
+			shutdown.Run()
+		}
+		transport.Cancel()
+		select {
+		case <-sig:
+		case <-time.After(5 * time.Second):
+		}
+		os.Exit(1)
+	}()
+
 	code, _ := console.Run()
+	close(returned)
+
+	if shutdown.Stopping() {
+		shutdown.Run()
+		if err == nil {
+			os.Exit(shutdown.EXIT_STOPPED)
+		}
+	}
 
 	if err != nil {
 		prefix := errors.FormatCaller(cnt, file, line)
@@ -210,5 +280,9 @@ func post(resp http.ResponseWriter, req *http.Request) {
 
 func delete(resp http.ResponseWriter, req *http.Request) {
 	resp.Write([]byte(""))
-	defer os.Exit(0)
+	// stop the same way we do on ctrl+c
+	select {
+	case sig <- syscall.SIGTERM:
+	default:
+	}
 }
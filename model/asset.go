@@ -7,14 +7,17 @@ import (
 const (
 	EUR = "EUR"
 	USD = "USD"
+	GBP = "GBP"
 	BTC = "BTC"
 	ETH = "ETH"
 	LTC = "LTC"
 	XRP = "XRP"
 )
 
+var fiat = Assets{EUR, USD, GBP, "AUD", "BRL", "CAD", "CHF", "JPY", "KRW", "NGN", "PLN", "RUB", "TRY", "UAH", "ZAR"}
+
 func Fiat(asset string) bool {
-	return strings.EqualFold(asset, EUR) || strings.EqualFold(asset, USD)
+	return fiat.HasAsset(asset)
 }
 
 type (
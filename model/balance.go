@@ -0,0 +1,44 @@
+package model
+
+import (
+	"sort"
+	"strings"
+)
+
+// Balance is what we hold of an asset. Locked is what is reserved for our open orders.
+type Balance struct {
+	Asset     string  `json:"asset"`
+	Total     float64 `json:"total"`
+	Available float64 `json:"available"`
+	Locked    float64 `json:"locked"`
+}
+
+type Balances []Balance
+
+func (balances Balances) IndexByAsset(asset string) int {
+	for i, balance := range balances {
+		if strings.EqualFold(balance.Asset, asset) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Add adds a balance to the balances, or adds to the balance of the same asset
+func (balances *Balances) Add(balance Balance) {
+	i := balances.IndexByAsset(balance.Asset)
+	if i == -1 {
+		*balances = append(*balances, balance)
+		return
+	}
+	(*balances)[i].Total += balance.Total
+	(*balances)[i].Available += balance.Available
+	(*balances)[i].Locked += balance.Locked
+}
+
+// Sort sorts the balances by asset
+func (balances Balances) Sort() {
+	sort.SliceStable(balances, func(i, j int) bool {
+		return strings.ToUpper(balances[i].Asset) < strings.ToUpper(balances[j].Asset)
+	})
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -120,17 +121,17 @@ func (c *Call) Corrupt(orderType OrderType) (bool, string) { // (corrupt, reason
 }
 
 // Multiply the buy target. Returns (new order type, deviated buy target). Does not modify the buy signal itself.
-func (c *Call) Deviate(exchange Exchange, client interface{}, kind OrderType, mult float64) (OrderType, float64) {
+func (c *Call) Deviate(ctx context.Context, exchange MarketData, client Client, kind OrderType, mult float64) (OrderType, float64) {
 	if mult != 1.0 {
 		limit := c.Price
 		if limit == 0 {
-			ticker, err := exchange.GetTicker(client, c.Market)
+			ticker, err := exchange.GetTicker(ctx, client, c.Market)
 			if err == nil {
 				limit = ticker
 			}
 		}
 		if limit > 0 {
-			prec, err := exchange.GetPricePrec(client, c.Market)
+			prec, err := exchange.GetPricePrec(ctx, client, c.Market)
 			if err == nil {
 				limit = precision.Round((limit * mult), prec)
 				return LIMIT, limit
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// CancelFilter selects the opened orders that the cancel command cancels. The zero value matches every order.
+type CancelFilter struct {
+	Side      OrderSide     // buy or sell (ORDER_SIDE_NONE for both)
+	MinPrice  float64       // orders priced below this price do not match (zero for no minimum)
+	MaxPrice  float64       // orders priced above this price do not match (zero for no maximum)
+	OlderThan time.Duration // orders younger than this do not match (zero for any age)
+	BotOnly   bool          // if true, only the orders that were opened by our bots match
+	Prefix    string        // if not empty, only the orders with a client order ID that starts with this prefix match
+}
+
+func (filter *CancelFilter) Match(order *Order, now time.Time) bool {
+	if filter.Side != ORDER_SIDE_NONE && order.Side != filter.Side {
+		return false
+	}
+	if filter.MinPrice > 0 && order.Price < filter.MinPrice {
+		return false
+	}
+	if filter.MaxPrice > 0 && order.Price > filter.MaxPrice {
+		return false
+	}
+	if filter.OlderThan > 0 && (order.CreatedAt.IsZero() || now.Sub(order.CreatedAt) < filter.OlderThan) {
+		return false
+	}
+	if filter.BotOnly && !order.Bot {
+		return false
+	}
+	if filter.Prefix != "" && !strings.HasPrefix(order.ClientOrderID, filter.Prefix) {
+		return false
+	}
+	return true
+}
+
+// Filter returns the orders that match the filter
+func (orders Orders) Filter(filter *CancelFilter, now time.Time) Orders {
+	var out Orders
+	for _, order := range orders {
+		if filter.Match(&order, now) {
+			out = append(out, order)
+		}
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCancelFilter(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	orders := Orders{
+		{ID: "1", Side: BUY, Price: 100, CreatedAt: now.Add(-48 * time.Hour), ClientOrderID: "x-J6MCRYME-1", Bot: true},
+		{ID: "2", Side: BUY, Price: 200, CreatedAt: now.Add(-1 * time.Hour)},
+		{ID: "3", Side: SELL, Price: 300, CreatedAt: now.Add(-72 * time.Hour), ClientOrderID: "manual-3"},
+	}
+	tests := []struct {
+		name   string
+		filter CancelFilter
+		want   []string
+	}{
+		{"everything", CancelFilter{}, []string{"1", "2", "3"}},
+		{"side", CancelFilter{Side: BUY}, []string{"1", "2"}},
+		{"min price", CancelFilter{MinPrice: 150}, []string{"2", "3"}},
+		{"max price", CancelFilter{MaxPrice: 250}, []string{"1", "2"}},
+		{"price range", CancelFilter{MinPrice: 150, MaxPrice: 250}, []string{"2"}},
+		{"older than", CancelFilter{OlderThan: 24 * time.Hour}, []string{"1", "3"}},
+		{"bot only", CancelFilter{BotOnly: true}, []string{"1"}},
+		{"prefix", CancelFilter{Prefix: "manual-"}, []string{"3"}},
+		{"combined", CancelFilter{Side: SELL, BotOnly: true}, nil},
+	}
+	for _, test := range tests {
+		got := orders.Filter(&test.filter, now)
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %d orders, want %d", test.name, len(got), len(test.want))
+			continue
+		}
+		for i, order := range got {
+			if order.ID != test.want[i] {
+				t.Errorf("%s: got order %s, want %s", test.name, order.ID, test.want[i])
+			}
+		}
+	}
+}
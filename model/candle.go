@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+)
+
+// Candles are sorted by time, oldest candle first.
+type (
+	Candle struct {
+		Time   time.Time `json:"time"`
+		Open   float64   `json:"open"`
+		High   float64   `json:"high"`
+		Low    float64   `json:"low"`
+		Close  float64   `json:"close"`
+		Volume float64   `json:"volume"`
+	}
+	Candles []Candle
+)
+
+// common candle intervals. exchanges may support (a subset of) these intervals.
+const (
+	CANDLE_15M = 15 * time.Minute
+	CANDLE_1H  = time.Hour
+	CANDLE_4H  = 4 * time.Hour
+	CANDLE_1D  = 24 * time.Hour
+)
+
+// Last returns the most recent candle, or nil if we don't have any.
+func (candles Candles) Last() *Candle {
+	if len(candles) == 0 {
+		return nil
+	}
+	return &candles[len(candles)-1]
+}
+
+// At returns the candle that was open at the given point in time, or nil if t is outside of our candles.
+func (candles Candles) At(t time.Time) *Candle {
+	if len(candles) == 0 || t.Before(candles[0].Time) {
+		return nil
+	}
+	for i := len(candles) - 1; i >= 0; i-- {
+		if !candles[i].Time.After(t) {
+			if i == len(candles)-1 && len(candles) > 1 && t.Sub(candles[i].Time) > candles[i].Time.Sub(candles[i-1].Time) {
+				return nil
+			}
+			return &candles[i]
+		}
+	}
+	return nil
+}
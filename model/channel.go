@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"time"
 )
 
@@ -10,6 +11,11 @@ type Channel interface {
 	GetOrderType() OrderType
 	GetRateLimit() time.Duration
 	GetValidity() (time.Duration, error)
-	GetMarkets(exchange Exchange, quote Assets, btcVolumeMin float64, valid time.Duration, sandbox, debug bool, ignore []string) (Markets, error)
-	GetCalls(exchange Exchange, market string, sandbox, debug bool) (Calls, error)
+	GetMarkets(ctx context.Context, exchange Exchange, quote Assets, btcVolumeMin float64, valid time.Duration, sandbox, debug bool, ignore []string) (Markets, error)
+	GetCalls(ctx context.Context, exchange Exchange, market string, sandbox, debug bool) (Calls, error)
+}
+
+// ChannelCheck is implemented by the channels that depend on what the exchange supports, so we can fail at startup
+type ChannelCheck interface {
+	Check(exchange Exchange) error
 }
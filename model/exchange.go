@@ -1,7 +1,9 @@
 package model
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/svanas/nefertiti/multiplier"
 )
@@ -37,26 +39,86 @@ func (info *ExchangeInfo) Equals(name string) bool {
 	return strings.EqualFold(info.Code, name) || strings.EqualFold(info.Name, name)
 }
 
-type Exchange interface {
-	GetInfo() *ExchangeInfo
-	GetClient(permission Permission, sandbox bool) (interface{}, error)
-	GetMarkets(cached, sandbox bool, ignore []string) ([]Market, error)
+// Capabilities tells us what an exchange (or rather: our implementation of that exchange) supports.
+type Capabilities struct {
+	Sandbox          bool `json:"sandbox"`          // has a sandbox (aka testnet)
+	WebSocket        bool `json:"websocket"`        // listens to a websocket feed rather than polling
+	MarketOrders     bool `json:"marketOrders"`     // opens market orders
+	MarketBuyByQuote bool `json:"marketBuyByQuote"` // opens market buy orders for an amount of quote currency
+	PostOnly         bool `json:"postOnly"`         // opens post-only limit orders
+	StopLoss         bool `json:"stopLoss"`         // opens stop-loss orders (see the stoploss command)
+	StopLossStrategy bool `json:"stopLossStrategy"` // protects every sell order with a stop-loss (see sell --stoploss=Y)
+	OCO              bool `json:"oco"`              // opens OCO (aka one-cancels-the-other) orders
+	Trailing         bool `json:"trailing"`         // opens trailing stop-loss orders
+	BotOrders        bool `json:"botOrders"`        // tells the orders that were opened by one of our bots (see Order.Bot)
+
+	Candles []time.Duration `json:"-"` // the candle intervals that GetCandles supports (if any)
+}
+
+// HasCandles returns true if GetCandles supports an interval
+func (caps *Capabilities) HasCandles(interval time.Duration) bool {
+	for _, candle := range caps.Candles {
+		if candle == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// Client is the handle that GetClient returns. Every exchange has its own client, and
+// expects to get its own client back; the handle is opaque to everything but the exchange itself.
+type Client interface {
+	Exchange() string // the name of the exchange this client talks to
+}
+
+// MarketData is the public (read-only) part of an exchange. Every method that talks to the exchange takes a context:
+// the requests it makes are cancelled once that context is done.
+type MarketData interface {
+	GetClient(permission Permission, sandbox bool) (Client, error)
+	GetMarkets(ctx context.Context, cached, sandbox bool, ignore []string) ([]Market, error)
 	FormatMarket(base, quote string) string
-	Sell(stategy Strategy, hold, earn Markets, sandbox, tweet, debug bool, success OnSuccess) error
-	Order(client interface{}, side OrderSide, market string, size float64, price float64, kind OrderType, metadata string) (oid []byte, raw []byte, err error)
-	StopLoss(client interface{}, market string, size float64, price float64, kind OrderType, metadata string) ([]byte, error)
-	OCO(client interface{}, market string, size float64, price, stop float64, metadata string) ([]byte, error)
-	GetClosed(client interface{}, market string) (Orders, error)
-	GetOpened(client interface{}, market string) (Orders, error)
-	GetBook(client interface{}, market string, side BookSide) (interface{}, error)
-	Aggregate(client, book interface{}, market string, agg float64) (Book, error)
-	GetTicker(client interface{}, market string) (float64, error)
-	Get24h(client interface{}, market string) (*Stats, error)
-	GetPricePrec(client interface{}, market string) (int, error)
-	GetSizePrec(client interface{}, market string) (int, error)
-	GetMaxSize(client interface{}, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64
-	Cancel(client interface{}, market string, side OrderSide) error
-	Buy(client interface{}, cancel bool, market string, calls Calls, deviation float64, kind OrderType) error
+	GetBook(ctx context.Context, client Client, market string, side BookSide) (interface{}, error)
+	Aggregate(ctx context.Context, client Client, book interface{}, market string, agg float64) (Book, error)
+	GetTicker(ctx context.Context, client Client, market string) (float64, error)
+	GetCandles(ctx context.Context, client Client, market string, interval time.Duration) (Candles, error)
+	Get24h(ctx context.Context, client Client, market string) (*Stats, error)
+	GetPricePrec(ctx context.Context, client Client, market string) (int, error)
+	GetSizePrec(ctx context.Context, client Client, market string) (int, error)
 	IsLeveragedToken(name string) bool
-	HasAlgoOrder(client interface{}, market string) (bool, error)
+}
+
+// Trading opens, lists and cancels orders
+type Trading interface {
+	Order(ctx context.Context, client Client, side OrderSide, market string, size float64, price float64, kind OrderType, opts *OrderOptions, metadata string) (oid []byte, raw []byte, err error)
+	StopLoss(ctx context.Context, client Client, market string, size float64, price float64, kind OrderType, metadata string) ([]byte, error)
+	OCO(ctx context.Context, client Client, market string, size float64, price, stop float64, metadata string) ([]byte, error)
+	GetClosed(ctx context.Context, client Client, market string) (Orders, error)
+	GetOpened(ctx context.Context, client Client, market string) (Orders, error)
+	GetOrder(ctx context.Context, client Client, market string, id string) (*Order, error)
+	Cancel(ctx context.Context, client Client, market string, side OrderSide) error
+	CancelOrder(ctx context.Context, client Client, market string, id string) error
+	Buy(ctx context.Context, client Client, cancel bool, market string, calls Calls, deviation float64, kind OrderType) error
+	HasAlgoOrder(ctx context.Context, client Client, market string) (bool, error)
+}
+
+// Account is the private (read-only) part of an exchange
+type Account interface {
+	GetFills(ctx context.Context, client Client, market string, since time.Time) (Fills, error)
+	GetFees(ctx context.Context, client Client, market string) (*Fees, error)
+	GetBalances(ctx context.Context, client Client) (Balances, error)
+	GetMaxSize(ctx context.Context, client Client, base, quote string, hold, earn bool, def float64, mult multiplier.Mult) float64
+}
+
+// Streaming listens to the exchange (until ctx is done, or the process gets killed)
+type Streaming interface {
+	Sell(ctx context.Context, stategy Strategy, hold, earn Markets, sandbox, tweet, debug bool, success OnSuccess) error
+}
+
+type Exchange interface {
+	GetInfo() *ExchangeInfo
+	Capabilities() *Capabilities
+	MarketData
+	Trading
+	Account
+	Streaming
 }
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/multiplier"
+)
+
+// Fees are ratios, for example: 0.001 is 0.1%
+type Fees struct {
+	Maker float64 `json:"maker"`
+	Taker float64 `json:"taker"`
+}
+
+// Discount returns the fees after a discount (in %), for example: 25% off when you pay your fees in BNB on Binance.
+func (fees Fees) Discount(pct float64) Fees {
+	return Fees{
+		Maker: fees.Maker * (1 - (pct / 100)),
+		Taker: fees.Taker * (1 - (pct / 100)),
+	}
+}
+
+// Mult returns the multiplier we need to sell at for us to earn mult after fees. We assume we bought as a taker, and we sell as a maker.
+func (fees Fees) Mult(mult multiplier.Mult) multiplier.Mult {
+	if fees.Maker >= 1 {
+		return mult
+	}
+	return multiplier.Mult(float64(mult) * (1 + fees.Taker) / (1 - fees.Maker))
+}
+
+// Return is the ratio we earned after fees, for example: 0.05 is +5%
+func (fees Fees) Return(bought, sold float64) float64 {
+	if bought <= 0 || sold <= 0 {
+		return 0
+	}
+	cost := bought * (1 + fees.Taker)
+	return ((sold * (1 - fees.Maker)) - cost) / cost
+}
+
+type feesCacheEntry struct {
+	fees    Fees
+	updated time.Time
+}
+
+var (
+	feesMutex sync.Mutex
+	feesCache = make(map[string]feesCacheEntry)
+)
+
+// reads a fee (in %) from the command line, returns a ratio
+func getFeeArg(name string) (float64, bool, error) {
+	arg := flag.Get(name)
+	if !arg.Exists {
+		return 0, false, nil
+	}
+	out, err := arg.Float64()
+	if err != nil || out < 0 || out >= 100 {
+		return 0, true, errors.Errorf("%s %v is invalid", name, arg)
+	}
+	return out / 100, true, nil
+}
+
+// GetFees returns the fees you pay on a market. The --maker-fee and --taker-fee args (in %) override what the exchange tells us,
+// and the --fee-discount arg (in %) is applied on top of that. Returns zero fees (eg. the old behavior) if we don't know the fees.
+func GetFees(ctx context.Context, exchange Exchange, client Client, market string) Fees {
+	var (
+		err   error
+		ok    bool
+		maker float64
+		taker float64
+	)
+
+	key := exchange.GetInfo().Code + ":" + market
+
+	feesMutex.Lock()
+	entry, cached := feesCache[key]
+	feesMutex.Unlock()
+
+	out := entry.fees
+	if !cached || time.Since(entry.updated) > time.Hour {
+		var fees *Fees
+		if fees, err = exchange.GetFees(ctx, client, market); err != nil {
+			// remember we failed, so we don't ask (and warn) again for the next hour
+			log.Printf("[WARN] %v. Market: %s\n", err, market)
+			out = Fees{}
+		} else {
+			out = *fees
+		}
+		feesMutex.Lock()
+		feesCache[key] = feesCacheEntry{fees: out, updated: time.Now()}
+		feesMutex.Unlock()
+	}
+
+	if maker, ok, err = getFeeArg("maker-fee"); err != nil {
+		log.Printf("[WARN] %v\n", err)
+	} else if ok {
+		out.Maker = maker
+	}
+	if taker, ok, err = getFeeArg("taker-fee"); err != nil {
+		log.Printf("[WARN] %v\n", err)
+	} else if ok {
+		out.Taker = taker
+	}
+
+	arg := flag.Get("fee-discount")
+	if arg.Exists {
+		var discount float64
+		if discount, err = arg.Float64(); err != nil || discount < 0 || discount > 100 {
+			log.Printf("[WARN] fee-discount %v is invalid\n", arg)
+		} else {
+			out = out.Discount(discount)
+		}
+	}
+
+	return out
+}
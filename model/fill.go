@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Fill is a (partial) execution of one of our orders
+type Fill struct {
+	ID            string    `json:"id"`
+	OrderID       string    `json:"orderId"`
+	ClientOrderID string    `json:"clientOrderId,omitempty"`
+	Side          OrderSide `json:"-"`
+	Market        string    `json:"market"`
+	Price         float64   `json:"price"`
+	Size          float64   `json:"size"`
+	Fee           float64   `json:"fee"`
+	FeeAsset      string    `json:"feeAsset,omitempty"` // empty if the exchange doesn't tell us what fee we paid
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+func (fill *Fill) MarshalJSON() ([]byte, error) {
+	type Alias Fill
+	return json.Marshal(&struct {
+		Side string `json:"side"`
+		*Alias
+	}{
+		Side:  fill.Side.String(),
+		Alias: (*Alias)(fill),
+	})
+}
+
+type Fills []Fill
+
+// Sort sorts the fills by time, oldest first
+func (fills Fills) Sort() {
+	sort.SliceStable(fills, func(i, j int) bool {
+		return fills[i].CreatedAt.Before(fills[j].CreatedAt)
+	})
+}
+
+// OrdersToFills converts closed orders into fills, for exchanges that don't give us their fills.
+func OrdersToFills(orders Orders, since time.Time) Fills {
+	var out Fills
+	for _, order := range orders {
+		if order.CreatedAt.Before(since) {
+			continue
+		}
+		// a cancelled (or rejected, or expired) order that didn't get filled at all
+		if order.Status.Closed() && order.Status != FILLED && order.Filled == 0 {
+			continue
+		}
+		fill := Fill{
+			OrderID:       order.ID,
+			ClientOrderID: order.ClientOrderID,
+			Side:          order.Side,
+			Market:        order.Market,
+			Price:         order.Price,
+			Size:          order.Size,
+			Fee:           order.Fee,
+			FeeAsset:      order.FeeAsset,
+			CreatedAt:     order.CreatedAt,
+		}
+		if order.Filled > 0 {
+			fill.Size = order.Filled
+			if order.AvgPrice > 0 {
+				fill.Price = order.AvgPrice
+			}
+		}
+		out = append(out, fill)
+	}
+	return out
+}
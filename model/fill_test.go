@@ -0,0 +1,36 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrdersToFills(t *testing.T) {
+	since := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		order Order
+		ok    bool    // true if the order becomes a fill
+		size  float64 // the size of the fill
+		price float64 // the price of the fill
+	}{
+		{Order{Status: FILLED, Size: 1, Price: 10, CreatedAt: since}, true, 1, 10},
+		{Order{Status: FILLED, Size: 1, Price: 10, Filled: 1, AvgPrice: 9, CreatedAt: since}, true, 1, 9},
+		{Order{Status: FILLED, Size: 1, Price: 10, CreatedAt: since.Add(-time.Second)}, false, 0, 0},
+		{Order{Status: CANCELLED, Size: 1, Price: 10, Filled: 0.5, CreatedAt: since}, true, 0.5, 10},
+		{Order{Status: CANCELLED, Size: 1, Price: 10, Filled: 0.5, AvgPrice: 9, CreatedAt: since}, true, 0.5, 9},
+		{Order{Status: CANCELLED, Size: 1, Price: 10, CreatedAt: since}, false, 0, 0},
+		{Order{Status: REJECTED, Size: 1, Price: 10, CreatedAt: since}, false, 0, 0},
+		{Order{Status: EXPIRED, Size: 1, Price: 10, CreatedAt: since}, false, 0, 0},
+		{Order{Status: ORDER_STATUS_NONE, Size: 1, Price: 10, CreatedAt: since}, true, 1, 10},
+	}
+	for i, test := range tests {
+		fills := OrdersToFills(Orders{test.order}, since)
+		if (len(fills) == 1) != test.ok {
+			t.Errorf("test %d: got %d fills", i, len(fills))
+			continue
+		}
+		if test.ok && (fills[0].Size != test.size || fills[0].Price != test.price) {
+			t.Errorf("test %d: got %v @ %v, want %v @ %v", i, fills[0].Size, fills[0].Price, test.size, test.price)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"math"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/precision"
+)
+
+type LadderCurve int
+
+const (
+	LADDER_LINEAR      LadderCurve = iota // the same distance between every step
+	LADDER_EXPONENTIAL                    // the distance doubles with every step
+	LADDER_FIBONACCI                      // the distance follows the Fibonacci sequence
+)
+
+var LadderCurveString = map[LadderCurve]string{
+	LADDER_LINEAR:      "linear",
+	LADDER_EXPONENTIAL: "exponential",
+	LADDER_FIBONACCI:   "fibonacci",
+}
+
+func (curve *LadderCurve) String() string {
+	return LadderCurveString[*curve]
+}
+
+func NewLadderCurve(data string) (LadderCurve, error) {
+	for curve := range LadderCurveString {
+		if curve.String() == data {
+			return curve, nil
+		}
+	}
+	return LADDER_LINEAR, errors.Errorf("%s does not exist", data)
+}
+
+// At returns the position (0..1) of step i out of n steps
+func (curve LadderCurve) At(i, n int64) float64 {
+	if n < 1 {
+		return 0
+	}
+	switch curve {
+	case LADDER_EXPONENTIAL:
+		return (math.Pow(2, float64(i)) - 1) / (math.Pow(2, float64(n)) - 1)
+	case LADDER_FIBONACCI:
+		var (
+			a, b  float64 = 0, 1
+			sum   float64
+			total float64
+		)
+		for j := int64(1); j <= n; j++ {
+			a, b = b, a+b
+			if j <= i {
+				sum += a
+			}
+			total += a
+		}
+		return sum / total
+	}
+	return float64(i) / float64(n)
+}
+
+// Rung is a step on the ladder
+type Rung struct {
+	Price float64
+	Size  float64
+}
+
+// Ladder returns steps+1 rungs, from the price nearest to the ticker to the price furthest away from the ticker
+func Ladder(nearPrice, farPrice, nearSize, farSize float64, steps int64, curve LadderCurve, pricePrec, sizePrec int) []Rung {
+	var out []Rung
+	for i := int64(0); i <= steps; i++ {
+		t := curve.At(i, steps)
+		out = append(out, Rung{
+			Price: precision.Round(nearPrice+(farPrice-nearPrice)*t, pricePrec),
+			Size:  precision.Round(nearSize+(farSize-nearSize)*t, sizePrec),
+		})
+	}
+	return out
+}
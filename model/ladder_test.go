@@ -0,0 +1,40 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLadderCurve(t *testing.T) {
+	tests := []struct {
+		curve LadderCurve
+		want  []float64
+	}{
+		{LADDER_LINEAR, []float64{0, 0.25, 0.5, 0.75, 1}},
+		{LADDER_EXPONENTIAL, []float64{0, 1.0 / 15, 3.0 / 15, 7.0 / 15, 1}},
+		{LADDER_FIBONACCI, []float64{0, 1.0 / 7, 2.0 / 7, 4.0 / 7, 1}},
+	}
+	for _, test := range tests {
+		for i, want := range test.want {
+			if got := test.curve.At(int64(i), 4); math.Abs(got-want) > 1e-9 {
+				t.Errorf("%s: step %d = %v, want %v", test.curve.String(), i, got, want)
+			}
+		}
+	}
+}
+
+func TestLadder(t *testing.T) {
+	rungs := Ladder(100, 200, 1, 5, 4, LADDER_LINEAR, 2, 2)
+	if len(rungs) != 5 {
+		t.Fatalf("len = %d, want 5", len(rungs))
+	}
+	if rungs[0].Price != 100 || rungs[0].Size != 1 {
+		t.Errorf("first rung = %+v", rungs[0])
+	}
+	if rungs[2].Price != 150 || rungs[2].Size != 3 {
+		t.Errorf("middle rung = %+v", rungs[2])
+	}
+	if rungs[4].Price != 200 || rungs[4].Size != 5 {
+		t.Errorf("last rung = %+v", rungs[4])
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -17,7 +18,7 @@ type Market struct {
 }
 
 // get the --market=X arg. verifies whether the specific market exists (or not).
-func GetMarket(exchange Exchange) (string, error) {
+func GetMarket(ctx context.Context, exchange MarketData) (string, error) {
 	arg := flag.Get("market")
 	if !arg.Exists {
 		return "", errors.New("missing argument: market")
@@ -28,13 +29,13 @@ func GetMarket(exchange Exchange) (string, error) {
 		return market, nil
 	}
 
-	markets, err := exchange.GetMarkets(true, flag.Sandbox(), flag.Get("ignore").Split())
+	markets, err := exchange.GetMarkets(ctx, true, flag.Sandbox(), flag.Get("ignore").Split())
 	if err != nil {
 		return "", err
 	}
 
 	if !HasMarket(markets, market) {
-		markets, err = exchange.GetMarkets(false, flag.Sandbox(), flag.Get("ignore").Split())
+		markets, err = exchange.GetMarkets(ctx, false, flag.Sandbox(), flag.Get("ignore").Split())
 		if err != nil {
 			return "", err
 		}
@@ -111,14 +112,14 @@ func GetSizeMin(hold bool, asset string) float64 {
 }
 
 // GetSizeMax returns the maximum size we can SELL
-func GetSizeMax(hold, earn bool, def float64, mult multiplier.Mult, prec func() int) float64 {
+func GetSizeMax(hold, earn bool, def float64, mult multiplier.Mult, fees Fees, prec func() int) float64 {
 	if hold {
 		// when we hodl, we then sell 20% of the purchased amount
 		return precision.Round((def * 0.20), prec())
 	}
 	if earn {
-		// sell enough at `mult` to break even; hold the rest
-		return precision.Floor((def / float64(mult)), prec())
+		// sell enough at `mult` to break even (including the fees we paid when we bought, and the fees we pay when we sell); hold the rest
+		return precision.Floor((def * (1 + fees.Taker) / (float64(mult) * (1 - fees.Maker))), prec())
 	}
 	return def
 }
@@ -3,6 +3,8 @@ package model
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/svanas/nefertiti/errors"
 )
 
 type OrderSide int
@@ -49,12 +51,14 @@ const (
 	ORDER_TYPE_NONE OrderType = iota
 	LIMIT
 	MARKET
+	STOP_LIMIT // a limit order that gets placed once the stop price has been reached
 )
 
 var OrderTypeString = map[OrderType]string{
 	ORDER_TYPE_NONE: "",
 	LIMIT:           "limit",
 	MARKET:          "market",
+	STOP_LIMIT:      "stop-limit",
 }
 
 func (ot *OrderType) String() string {
@@ -70,13 +74,163 @@ func NewOrderType(data string) OrderType {
 	return ORDER_TYPE_NONE
 }
 
+type TimeInForce int
+
+const (
+	GTC TimeInForce = iota // good till cancelled
+	IOC                    // immediate or cancel: whatever cannot be filled immediately gets cancelled
+	FOK                    // fill or kill: the order gets filled immediately and completely, or not at all
+	GTT                    // good till time: the order gets cancelled at OrderOptions.ExpireAt
+)
+
+var TimeInForceString = map[TimeInForce]string{
+	GTC: "gtc",
+	IOC: "ioc",
+	FOK: "fok",
+	GTT: "gtt",
+}
+
+func (tif *TimeInForce) String() string {
+	return TimeInForceString[*tif]
+}
+
+func NewTimeInForce(data string) (TimeInForce, error) {
+	for tif := range TimeInForceString {
+		if tif.String() == data {
+			return tif, nil
+		}
+	}
+	return GTC, errors.Errorf("%s does not exist", data)
+}
+
+// OrderOptions are the optional arguments of Exchange.Order. A nil *OrderOptions is a good-till-cancelled order.
+type OrderOptions struct {
+	TimeInForce TimeInForce
+	ExpireAt    time.Time // required if TimeInForce is GTT
+	PostOnly    bool      // the order gets rejected if it would be filled immediately
+	StopPrice   float64   // required if the order type is STOP_LIMIT
+}
+
+func (opts *OrderOptions) GetTimeInForce() TimeInForce {
+	if opts == nil {
+		return GTC
+	}
+	return opts.TimeInForce
+}
+
+func (opts *OrderOptions) IsPostOnly() bool {
+	return opts != nil && opts.PostOnly
+}
+
+func (opts *OrderOptions) GetStopPrice() float64 {
+	if opts == nil {
+		return 0
+	}
+	return opts.StopPrice
+}
+
+// Validate rejects the combinations that no exchange supports. Exchanges reject what they do not support on top of that.
+func (opts *OrderOptions) Validate(kind OrderType) error {
+	if opts == nil {
+		if kind == STOP_LIMIT {
+			return errors.New("missing argument: stop price")
+		}
+		return nil
+	}
+	tif := opts.TimeInForce
+	if kind == MARKET && tif != GTC {
+		return errors.Errorf("a market order cannot be %s", tif.String())
+	}
+	if opts.PostOnly {
+		if kind == MARKET {
+			return errors.New("a market order cannot be post-only")
+		}
+		if tif == IOC || tif == FOK {
+			return errors.Errorf("a %s order cannot be post-only", tif.String())
+		}
+	}
+	if tif == GTT {
+		if opts.ExpireAt.IsZero() {
+			return errors.New("missing argument: expiry time")
+		}
+		if !opts.ExpireAt.After(time.Now()) {
+			return errors.New("the expiry time is in the past")
+		}
+	} else if !opts.ExpireAt.IsZero() {
+		return errors.Errorf("a %s order cannot have an expiry time", tif.String())
+	}
+	if kind == STOP_LIMIT {
+		if opts.StopPrice <= 0 {
+			return errors.New("missing argument: stop price")
+		}
+	} else if opts.StopPrice > 0 {
+		return errors.Errorf("a %s order cannot have a stop price", kind.String())
+	}
+	return nil
+}
+
+type OrderStatus int
+
+const (
+	ORDER_STATUS_NONE OrderStatus = iota // we don't know
+	OPEN                                 // nothing has been filled yet
+	PARTIALLY_FILLED                     // open, but some of it has been filled
+	FILLED                               // closed, everything has been filled
+	CANCELLED                            // closed, some of it might have been filled
+	REJECTED
+	EXPIRED
+)
+
+var OrderStatusString = map[OrderStatus]string{
+	ORDER_STATUS_NONE: "",
+	OPEN:              "open",
+	PARTIALLY_FILLED:  "partially-filled",
+	FILLED:            "filled",
+	CANCELLED:         "cancelled",
+	REJECTED:          "rejected",
+	EXPIRED:           "expired",
+}
+
+func (status *OrderStatus) String() string {
+	return OrderStatusString[*status]
+}
+
+// Closed returns true if the order is no longer on the book
+func (status OrderStatus) Closed() bool {
+	return status == FILLED || status == CANCELLED || status == REJECTED || status == EXPIRED
+}
+
+// GetOrderStatus returns the status of an order, for exchanges that do not tell us
+func GetOrderStatus(size, filled float64, closed bool) OrderStatus {
+	if closed {
+		if filled > 0 && filled >= size {
+			return FILLED
+		}
+		return CANCELLED
+	}
+	if filled > 0 {
+		return PARTIALLY_FILLED
+	}
+	return OPEN
+}
+
 type (
 	Order struct {
-		Side      OrderSide `json:"-"`
-		Market    string    `json:"market"`
-		Size      float64   `json:"size"`
-		Price     float64   `json:"price"`
-		CreatedAt time.Time `json:"createdAt"`
+		ID            string      `json:"id,omitempty"`
+		ClientOrderID string      `json:"clientOrderId,omitempty"`
+		Side          OrderSide   `json:"-"`
+		Type          OrderType   `json:"-"`
+		Status        OrderStatus `json:"-"`
+		Market        string      `json:"market"`
+		Size          float64     `json:"size"`
+		Price         float64     `json:"price"`
+		Filled        float64     `json:"filled"`             // the size that has been filled
+		AvgPrice      float64     `json:"avgPrice,omitempty"` // the average price of the fills
+		Fee           float64     `json:"fee,omitempty"`      // the fee we paid (if the exchange knows)
+		FeeAsset      string      `json:"feeAsset,omitempty"` // the asset we paid the fee in (if the exchange knows)
+		CreatedAt     time.Time   `json:"createdAt"`
+		Bought        float64     `json:"bought,omitempty"` // the price we paid for the base asset of this sell order (if the exchange knows)
+		Bot           bool        `json:"bot,omitempty"`    // true if this order was opened by one of our bots (if the exchange can tell)
 	}
 	Orders []Order
 )
@@ -84,14 +238,27 @@ type (
 func (order *Order) MarshalJSON() ([]byte, error) {
 	type Alias Order
 	return json.Marshal(&struct {
-		Side string `json:"side"`
+		Side   string `json:"side"`
+		Type   string `json:"type,omitempty"`
+		Status string `json:"status,omitempty"`
 		*Alias
 	}{
-		Side:  order.Side.String(),
-		Alias: (*Alias)(order),
+		Side:   order.Side.String(),
+		Type:   order.Type.String(),
+		Status: order.Status.String(),
+		Alias:  (*Alias)(order),
 	})
 }
 
+func (orders Orders) IndexByID(id string) int {
+	for i, order := range orders {
+		if order.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
 func (orders Orders) IndexByPrice(side OrderSide, market string, price float64) int {
 	for i, order := range orders {
 		if order.Side == side && order.Market == market && order.Price == price {
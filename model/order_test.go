@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrderOptionsValidate(t *testing.T) {
+	tomorrow := time.Now().Add(24 * time.Hour)
+	tests := []struct {
+		kind OrderType
+		opts *OrderOptions
+		ok   bool
+	}{
+		{LIMIT, nil, true},
+		{MARKET, nil, true},
+		{STOP_LIMIT, nil, false},
+		{STOP_LIMIT, &OrderOptions{StopPrice: 10}, true},
+		{LIMIT, &OrderOptions{StopPrice: 10}, false},
+		{LIMIT, &OrderOptions{PostOnly: true}, true},
+		{MARKET, &OrderOptions{PostOnly: true}, false},
+		{LIMIT, &OrderOptions{PostOnly: true, TimeInForce: IOC}, false},
+		{LIMIT, &OrderOptions{TimeInForce: FOK}, true},
+		{MARKET, &OrderOptions{TimeInForce: FOK}, false},
+		{LIMIT, &OrderOptions{TimeInForce: GTT}, false},
+		{LIMIT, &OrderOptions{TimeInForce: GTT, ExpireAt: tomorrow}, true},
+		{LIMIT, &OrderOptions{TimeInForce: GTT, ExpireAt: time.Now().Add(-time.Hour)}, false},
+		{LIMIT, &OrderOptions{ExpireAt: tomorrow}, false},
+	}
+	for i, test := range tests {
+		err := test.opts.Validate(test.kind)
+		if (err == nil) != test.ok {
+			t.Errorf("test %d: %s, %+v: got %v", i, test.kind.String(), test.opts, err)
+		}
+	}
+}
+
+func TestGetOrderStatus(t *testing.T) {
+	tests := []struct {
+		size   float64
+		filled float64
+		closed bool
+		want   OrderStatus
+	}{
+		{1, 0, false, OPEN},
+		{1, 0.5, false, PARTIALLY_FILLED},
+		{1, 1, false, PARTIALLY_FILLED},
+		{1, 1, true, FILLED},
+		{1, 1.5, true, FILLED},
+		{1, 0.5, true, CANCELLED},
+		{1, 0, true, CANCELLED},
+		{0, 0, true, CANCELLED},
+	}
+	for i, test := range tests {
+		if got := GetOrderStatus(test.size, test.filled, test.closed); got != test.want {
+			t.Errorf("test %d: GetOrderStatus(%v, %v, %v): got %s, want %s", i, test.size, test.filled, test.closed, got.String(), test.want.String())
+		}
+	}
+}
@@ -0,0 +1,153 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package model
+
+import (
+	"context"
+	"strings"
+
+	"github.com/svanas/nefertiti/errors"
+)
+
+// stablecoins that we value at one USD when an exchange doesn't have a USD market
+var pegged = map[string][]string{
+	USD: {"USDT", "USDC", "BUSD", "TUSD", "USDP", "DAI"},
+}
+
+// Pegged returns true if the asset is a stablecoin
+func Pegged(asset string) bool {
+	for _, stablecoins := range pegged {
+		if Assets(stablecoins).HasAsset(asset) {
+			return true
+		}
+	}
+	return false
+}
+
+// a step from one asset to another asset
+type hop struct {
+	market string // empty if we assume a 1:1 peg
+	invert bool   // true if we go from the quote asset to the base asset
+}
+
+// findPath returns the shortest path (the fewest markets) from one asset to another asset
+func findPath(markets []Market, from, to string, peg bool) ([]hop, bool) {
+	type edge struct {
+		to  string
+		hop hop
+	}
+
+	graph := make(map[string][]edge)
+	link := func(a, b string, h hop) {
+		graph[a] = append(graph[a], edge{to: b, hop: h})
+	}
+	for _, market := range markets {
+		base := strings.ToUpper(NormalizeAsset(market.Base))
+		quote := strings.ToUpper(NormalizeAsset(market.Quote))
+		link(base, quote, hop{market: market.Name})
+		link(quote, base, hop{market: market.Name, invert: true})
+	}
+	if peg {
+		for fiat, stablecoins := range pegged {
+			for _, stablecoin := range stablecoins {
+				link(fiat, stablecoin, hop{})
+				link(stablecoin, fiat, hop{})
+			}
+		}
+	}
+
+	from = strings.ToUpper(NormalizeAsset(from))
+	to = strings.ToUpper(NormalizeAsset(to))
+
+	// breadth-first search
+	prev := map[string]edge{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		if curr == to {
+			var out []hop
+			for curr != from {
+				out = append([]hop{prev[curr].hop}, out...)
+				curr = prev[curr].to
+			}
+			return out, true
+		}
+		for _, next := range graph[curr] {
+			if _, ok := prev[next.to]; !ok {
+				prev[next.to] = edge{to: curr, hop: next.hop}
+				queue = append(queue, next.to)
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Prices converts any asset into any other asset, by walking the markets of an exchange
+type Prices struct {
+	ctx      context.Context // the context we get the tickers in
+	exchange MarketData
+	client   Client
+	markets  []Market
+	tickers  map[string]float64
+}
+
+func NewPrices(ctx context.Context, exchange MarketData, client Client, markets []Market) *Prices {
+	return &Prices{
+		ctx:      ctx,
+		exchange: exchange,
+		client:   client,
+		markets:  markets,
+		tickers:  make(map[string]float64),
+	}
+}
+
+func (self *Prices) getTicker(market string) (float64, error) {
+	if ticker, ok := self.tickers[market]; ok {
+		return ticker, nil
+	}
+	ticker, err := self.exchange.GetTicker(self.ctx, self.client, market)
+	if err != nil {
+		return 0, err
+	}
+	if ticker == 0 {
+		return 0, errors.Errorf("ticker is zero. Market: %s", market)
+	}
+	self.tickers[market] = ticker
+	return ticker, nil
+}
+
+// Rate returns the price of one unit of an asset, in another asset
+func (self *Prices) Rate(from, to string) (float64, error) {
+	path, ok := findPath(self.markets, from, to, false)
+	if !ok {
+		if path, ok = findPath(self.markets, from, to, true); !ok {
+			return 0, errors.Errorf("cannot convert %s to %s", from, to)
+		}
+	}
+	out := 1.0
+	for _, hop := range path {
+		if hop.market == "" {
+			continue
+		}
+		ticker, err := self.getTicker(hop.market)
+		if err != nil {
+			return 0, err
+		}
+		if hop.invert {
+			out = out / ticker
+		} else {
+			out = out * ticker
+		}
+	}
+	return out, nil
+}
+
+// Convert returns the value of an amount of an asset, in another asset
+func (self *Prices) Convert(amount float64, from, to string) (float64, error) {
+	rate, err := self.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFindPath(t *testing.T) {
+	markets := []Market{
+		{Name: "ETHBTC", Base: "ETH", Quote: "BTC"},
+		{Name: "BTCUSDT", Base: "BTC", Quote: "USDT"},
+		{Name: "XBTEUR", Base: "XBT", Quote: "EUR"},
+	}
+
+	path, ok := findPath(markets, "ETH", "USDT", false)
+	if !ok || len(path) != 2 || path[0].market != "ETHBTC" || path[1].market != "BTCUSDT" {
+		t.Errorf("expected ETHBTC -> BTCUSDT, got %v", path)
+	}
+
+	path, ok = findPath(markets, "EUR", "ETH", false)
+	if !ok || len(path) != 2 || !path[0].invert || !path[1].invert {
+		t.Errorf("expected XBTEUR (inverted) -> ETHBTC (inverted), got %v", path)
+	}
+
+	if _, ok = findPath(markets, "ETH", "USD", false); ok {
+		t.Errorf("expected no path from ETH to USD")
+	}
+	if path, ok = findPath(markets, "ETH", "USD", true); !ok || len(path) != 3 || path[2].market != "" {
+		t.Errorf("expected ETHBTC -> BTCUSDT -> peg, got %v", path)
+	}
+}
+
+// fakeMarketData is a data-only exchange that knows nothing but its tickers
+type fakeMarketData struct {
+	MarketData
+	tickers map[string]float64
+}
+
+func (fake *fakeMarketData) GetTicker(ctx context.Context, client Client, market string) (float64, error) {
+	return fake.tickers[market], nil
+}
+
+func TestPricesConvert(t *testing.T) {
+	markets := []Market{
+		{Name: "ETHBTC", Base: "ETH", Quote: "BTC"},
+		{Name: "BTCUSDT", Base: "BTC", Quote: "USDT"},
+	}
+	exchange := &fakeMarketData{tickers: map[string]float64{"ETHBTC": 0.05, "BTCUSDT": 40000}}
+
+	prices := NewPrices(context.Background(), exchange, nil, markets)
+	value, err := prices.Convert(2, "ETH", "USDT")
+	if err != nil || value != 4000 {
+		t.Errorf("expected 4000, got %v (%v)", value, err)
+	}
+	value, err = prices.Convert(4000, "USDT", "BTC")
+	if err != nil || value != 0.1 {
+		t.Errorf("expected 0.1, got %v (%v)", value, err)
+	}
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/svanas/nefertiti/errors"
+)
+
+// Weights are the target weights (in %) per asset, for example: BTC:40,ETH:30,USDT:30
+type Weights map[string]float64
+
+func ParseWeights(data []string) (Weights, error) {
+	out := make(Weights)
+	var total float64
+	for _, elem := range data {
+		pair := strings.Split(elem, ":")
+		if len(pair) != 2 {
+			return nil, errors.Errorf("weight %s is invalid", elem)
+		}
+		weight, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil || weight < 0 {
+			return nil, errors.Errorf("weight %s is invalid", elem)
+		}
+		out[strings.ToUpper(pair[0])] = weight
+		total += weight
+	}
+	if total <= 0 {
+		return nil, errors.New("weights do not add up")
+	}
+	// normalize the weights, so they add up to 100%
+	for asset := range out {
+		out[asset] = out[asset] / total * 100
+	}
+	return out, nil
+}
+
+// Drift is the difference between what we hold and what we want to hold of an asset
+type Drift struct {
+	Asset  string  `json:"asset"`
+	Value  float64 `json:"value"`  // what we hold, in reference currency
+	Target float64 `json:"target"` // what we want to hold, in reference currency
+	Actual float64 `json:"actual"` // what we hold, in %
+	Weight float64 `json:"weight"` // what we want to hold, in %
+}
+
+// Delta returns what we need to buy (if positive) or sell (if negative), in reference currency
+func (drift *Drift) Delta() float64 {
+	return drift.Target - drift.Value
+}
+
+// Exceeds returns true if the actual weight is more than band (in %) away from the target weight
+func (drift *Drift) Exceeds(band float64) bool {
+	return math.Abs(drift.Actual-drift.Weight) > band
+}
+
+// GetDrifts compares the value (in reference currency) per asset with the target weights. Assets without a weight are ignored.
+// Returns the drifts, sells first, then buys.
+func GetDrifts(values map[string]float64, weights Weights) []Drift {
+	var total float64
+	for asset := range weights {
+		total += values[asset]
+	}
+	var out []Drift
+	for asset, weight := range weights {
+		drift := Drift{
+			Asset:  asset,
+			Value:  values[asset],
+			Target: total * weight / 100,
+			Weight: weight,
+		}
+		if total > 0 {
+			drift.Actual = drift.Value / total * 100
+		}
+		out = append(out, drift)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Delta() < out[j].Delta()
+	})
+	return out
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestGetDrifts(t *testing.T) {
+	weights, err := ParseWeights([]string{"BTC:2", "ETH:1", "USDT:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weights["BTC"] != 50 {
+		t.Errorf("expected BTC to weigh 50%%, got %v", weights["BTC"])
+	}
+
+	values := map[string]float64{"BTC": 300, "ETH": 100, "USDT": 0, "BNB": 1000}
+	drifts := GetDrifts(values, weights)
+	if len(drifts) != 3 {
+		t.Fatalf("expected 3 drifts, got %d", len(drifts))
+	}
+	// sells first
+	if drifts[0].Asset != "BTC" || drifts[0].Delta() != -100 {
+		t.Errorf("expected to sell BTC worth 100, got %s worth %v", drifts[0].Asset, drifts[0].Delta())
+	}
+	if drifts[2].Asset != "USDT" || drifts[2].Delta() != 100 {
+		t.Errorf("expected to buy USDT worth 100, got %s worth %v", drifts[2].Asset, drifts[2].Delta())
+	}
+	if drifts[1].Exceeds(5) {
+		t.Errorf("expected ETH to be within the band")
+	}
+}
@@ -1,6 +1,10 @@
 package model
 
-import "github.com/svanas/nefertiti/precision"
+import (
+	"context"
+
+	"github.com/svanas/nefertiti/precision"
+)
 
 type Stats struct {
 	Market    string
@@ -9,12 +13,12 @@ type Stats struct {
 	BtcVolume float64
 }
 
-func (s *Stats) Avg(exchange Exchange, sandbox bool) (float64, error) {
+func (s *Stats) Avg(ctx context.Context, exchange MarketData, sandbox bool) (float64, error) {
 	client, err := exchange.GetClient(PUBLIC, sandbox)
 	if err != nil {
 		return 0, err
 	}
-	prec, err := exchange.GetPricePrec(client, s.Market)
+	prec, err := exchange.GetPricePrec(ctx, client, s.Market)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"strings"
+)
+
+// some exchanges have their own name for an asset. for example: Kraken calls bitcoin XBT.
+var assetAliases = map[string]string{
+	"XBT": BTC,
+	"BCC": "BCH",
+	"XDG": "DOGE",
+}
+
+// NormalizeAsset returns the name of an asset that is the same on every exchange.
+func NormalizeAsset(asset string) string {
+	out := strings.ToUpper(asset)
+	if alias, ok := assetAliases[out]; ok {
+		return alias
+	}
+	return out
+}
+
+// Symbol returns an exchange-independent name for a base/quote pair, for example: BTC/USDT
+func Symbol(base, quote string) string {
+	return NormalizeAsset(base) + "/" + NormalizeAsset(quote)
+}
+
+// Symbol returns an exchange-independent name for this market, for example: BTC/USDT
+func (market *Market) Symbol() string {
+	return Symbol(market.Base, market.Quote)
+}
+
+// Symbols maps an exchange-independent symbol to the exchange-specific market name
+type Symbols map[string]string
+
+func NewSymbols(markets []Market) Symbols {
+	out := make(Symbols)
+	for _, market := range markets {
+		if market.Base != "" && market.Quote != "" {
+			out[market.Symbol()] = market.Name
+		}
+	}
+	return out
+}
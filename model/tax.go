@@ -0,0 +1,170 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+)
+
+type LotMethod int
+
+const (
+	FIFO LotMethod = iota // first in, first out
+	LIFO                  // last in, first out
+	HIFO                  // highest in, first out
+)
+
+var LotMethodString = map[LotMethod]string{
+	FIFO: "fifo",
+	LIFO: "lifo",
+	HIFO: "hifo",
+}
+
+func (method *LotMethod) String() string {
+	return LotMethodString[*method]
+}
+
+func NewLotMethod(data string) (LotMethod, error) {
+	for method := range LotMethodString {
+		if method.String() == strings.ToLower(data) {
+			return method, nil
+		}
+	}
+	return FIFO, errors.Errorf("%s does not exist", data)
+}
+
+// Disposal is a (part of a) lot that got sold. Proceeds and cost basis are in quote currency, after fees.
+type Disposal struct {
+	Market     string    `json:"market"`
+	Size       float64   `json:"size"`
+	AcquiredAt time.Time `json:"acquiredAt"` // zero if we don't know when (or for what) we acquired this lot
+	SoldAt     time.Time `json:"soldAt"`
+	Proceeds   float64   `json:"proceeds"`
+	CostBasis  float64   `json:"costBasis"`
+}
+
+func (disposal *Disposal) Gain() float64 {
+	return disposal.Proceeds - disposal.CostBasis
+}
+
+type Disposals []Disposal
+
+// returns the index of the lot that we sell next, or -1 if we don't have anything to sell
+func (lots Lots) next(method LotMethod) int {
+	out := -1
+	for i, lot := range lots {
+		if lot.Size <= 0 {
+			continue
+		}
+		if out == -1 {
+			out = i
+			continue
+		}
+		switch method {
+		case LIFO:
+			if !lot.BoughtAt.Before(lots[out].BoughtAt) {
+				out = i
+			}
+		case HIFO:
+			if lot.Price > lots[out].Price {
+				out = i
+			}
+		}
+	}
+	return out
+}
+
+// Dispose matches the sell fills with the buy fills of one market, using the lot method.
+// Fees paid in the base or the quote currency are included in the cost basis and the proceeds.
+// Returns the disposals, and the lots that haven't been sold (yet).
+func Dispose(fills Fills, base, quote string, method LotMethod) (Disposals, Lots) {
+	sorted := make(Fills, len(fills))
+	copy(sorted, fills)
+	sorted.Sort()
+
+	var (
+		lots Lots
+		out  Disposals
+	)
+
+	for _, fill := range sorted {
+		size := fill.Size
+		value := fill.Size * fill.Price
+		if strings.EqualFold(fill.FeeAsset, base) {
+			if fill.Side == BUY {
+				size = size - fill.Fee
+			} else {
+				value = value - (fill.Fee * fill.Price)
+			}
+		} else if strings.EqualFold(fill.FeeAsset, quote) {
+			if fill.Side == BUY {
+				value = value + fill.Fee
+			} else {
+				value = value - fill.Fee
+			}
+		}
+		if size <= 0 {
+			continue
+		}
+
+		if fill.Side == BUY {
+			lots = append(lots, Lot{
+				Market:   fill.Market,
+				Size:     size,
+				Price:    value / size, // cost per unit, including fees
+				BoughtAt: fill.CreatedAt,
+			})
+			continue
+		}
+
+		if fill.Side == SELL {
+			proceeds := value / size // proceeds per unit, after fees
+			remaining := size
+			for remaining > dust {
+				i := lots.next(method)
+				if i == -1 {
+					// we sold something that we don't know we acquired
+					out = append(out, Disposal{
+						Market:   fill.Market,
+						Size:     remaining,
+						SoldAt:   fill.CreatedAt,
+						Proceeds: remaining * proceeds,
+					})
+					break
+				}
+				lot := &lots[i]
+				take := remaining
+				if take > lot.Size {
+					take = lot.Size
+				}
+				out = append(out, Disposal{
+					Market:     fill.Market,
+					Size:       take,
+					AcquiredAt: lot.BoughtAt,
+					SoldAt:     fill.CreatedAt,
+					Proceeds:   take * proceeds,
+					CostBasis:  take * lot.Price,
+				})
+				lot.Size = lot.Size - take
+				if lot.Size < dust {
+					lot.Size = 0
+				}
+				remaining = remaining - take
+			}
+		}
+	}
+
+	var open Lots
+	for _, lot := range lots {
+		if lot.Size > 0 {
+			open = append(open, lot)
+		}
+	}
+	sort.SliceStable(open, func(i, j int) bool {
+		return open[i].BoughtAt.Before(open[j].BoughtAt)
+	})
+
+	return out, open
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDispose(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	fills := Fills{
+		{Side: BUY, Market: "BTC-USD", Size: 1, Price: 100, CreatedAt: start},
+		{Side: BUY, Market: "BTC-USD", Size: 1, Price: 120, CreatedAt: start.Add(time.Hour)},
+		{Side: BUY, Market: "BTC-USD", Size: 1, Price: 110, CreatedAt: start.Add(2 * time.Hour)},
+		{Side: SELL, Market: "BTC-USD", Size: 1, Price: 130, Fee: 1, FeeAsset: "USD", CreatedAt: start.Add(3 * time.Hour)},
+	}
+
+	for method, basis := range map[LotMethod]float64{FIFO: 100, LIFO: 110, HIFO: 120} {
+		disposals, open := Dispose(fills, "BTC", "USD", method)
+		if len(disposals) != 1 {
+			t.Fatalf("%s: expected 1 disposal, got %d", method.String(), len(disposals))
+		}
+		if disposals[0].CostBasis != basis {
+			t.Errorf("%s: expected cost basis %v, got %v", method.String(), basis, disposals[0].CostBasis)
+		}
+		if disposals[0].Proceeds != 129 {
+			t.Errorf("%s: expected proceeds 129, got %v", method.String(), disposals[0].Proceeds)
+		}
+		if len(open) != 2 {
+			t.Errorf("%s: expected 2 open lots, got %d", method.String(), len(open))
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// Trade is a (part of a) filled buy order that got sold
+type Trade struct {
+	Market   string    `json:"market"`
+	Size     float64   `json:"size"`
+	Bought   float64   `json:"bought"`
+	Sold     float64   `json:"sold"`
+	BoughtAt time.Time `json:"boughtAt"`
+	SoldAt   time.Time `json:"soldAt"`
+}
+
+// PnL returns the profit (or loss) in quote currency after fees
+func (trade *Trade) PnL(fees Fees) float64 {
+	return trade.Size * ((trade.Sold * (1 - fees.Maker)) - (trade.Bought * (1 + fees.Taker)))
+}
+
+type Trades []Trade
+
+// Lot is a (part of a) filled buy order that hasn't been sold (yet)
+type Lot struct {
+	Market   string    `json:"market"`
+	Size     float64   `json:"size"`
+	Price    float64   `json:"price"`
+	BoughtAt time.Time `json:"boughtAt"`
+}
+
+// PnL returns the profit (or loss) in quote currency after fees if we were to sell this lot at the ticker price
+func (lot *Lot) PnL(ticker float64, fees Fees) float64 {
+	return lot.Size * ((ticker * (1 - fees.Maker)) - (lot.Price * (1 + fees.Taker)))
+}
+
+type Lots []Lot
+
+// anything smaller than this is a rounding error
+const dust = 1e-12
+
+func (lots Lots) indexByPrice(price float64) int {
+	for i, lot := range lots {
+		if lot.Price == price && lot.Size > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// sell takes size from lot i. returns the size we took.
+func (lots Lots) sell(i int, size float64, sell *Order, out *Trades) float64 {
+	lot := &lots[i]
+	take := size
+	if take > lot.Size {
+		take = lot.Size
+	}
+	lot.Size = lot.Size - take
+	if lot.Size < dust {
+		lot.Size = 0
+	}
+	*out = append(*out, Trade{
+		Market:   lot.Market,
+		Size:     take,
+		Bought:   lot.Price,
+		Sold:     sell.fillPrice(),
+		BoughtAt: lot.BoughtAt,
+		SoldAt:   sell.CreatedAt,
+	})
+	return take
+}
+
+// returns the average price of the fills, or the limit price if the exchange doesn't tell us
+func (order *Order) fillPrice() float64 {
+	if order.AvgPrice > 0 {
+		return order.AvgPrice
+	}
+	return order.Price
+}
+
+// PairOrders pairs the filled sell orders with the filled buy orders they have sold.
+// When the exchange knows what we paid for a sell order, we pair that sell with that buy. Otherwise, first in first out.
+// Orders that didn't get filled at all (eg. cancelled) are ignored, and we only count the part of an order that got filled.
+// Returns the trades, and the lots that haven't been sold (yet).
+func PairOrders(orders Orders) (Trades, Lots) {
+	sorted := make(Orders, len(orders))
+	copy(sorted, orders)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var (
+		lots   Lots
+		trades Trades
+	)
+	for i := range sorted {
+		order := &sorted[i]
+		if order.Filled <= 0 {
+			continue
+		}
+		if order.Side == BUY {
+			lots = append(lots, Lot{
+				Market:   order.Market,
+				Size:     order.Filled,
+				Price:    order.fillPrice(),
+				BoughtAt: order.CreatedAt,
+			})
+		} else if order.Side == SELL {
+			size := order.Filled
+			if order.Bought > 0 {
+				if n := lots.indexByPrice(order.Bought); n > -1 {
+					size = size - lots.sell(n, size, order, &trades)
+				}
+			}
+			for n := range lots {
+				if size < dust {
+					break
+				}
+				if lots[n].Size > 0 {
+					size = size - lots.sell(n, size, order, &trades)
+				}
+			}
+		}
+	}
+
+	// remove the lots we have sold
+	var open Lots
+	for _, lot := range lots {
+		if lot.Size > 0 {
+			open = append(open, lot)
+		}
+	}
+
+	return trades, open
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPairOrders(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	orders := Orders{
+		{Side: BUY, Market: "BTCUSDT", Size: 1, Price: 100, Filled: 1, CreatedAt: start},
+		{Side: BUY, Market: "BTCUSDT", Size: 1, Price: 90, Filled: 1, CreatedAt: start.Add(time.Hour)},
+		// this sell knows it sold the second buy
+		{Side: SELL, Market: "BTCUSDT", Size: 1, Price: 95, Filled: 1, CreatedAt: start.Add(2 * time.Hour), Bought: 90},
+		// this sell doesn't know, so first in first out
+		{Side: SELL, Market: "BTCUSDT", Size: 0.5, Price: 110, Filled: 0.5, CreatedAt: start.Add(3 * time.Hour)},
+	}
+
+	trades, open := PairOrders(orders)
+
+	if len(trades) != 2 {
+		t.Fatalf("expected 2 trades, got %d", len(trades))
+	}
+	if trades[0].Bought != 90 || trades[0].Sold != 95 {
+		t.Errorf("expected 90 -> 95, got %v -> %v", trades[0].Bought, trades[0].Sold)
+	}
+	if trades[1].Bought != 100 || trades[1].Size != 0.5 {
+		t.Errorf("expected 0.5 @ 100, got %v @ %v", trades[1].Size, trades[1].Bought)
+	}
+	if len(open) != 1 || open[0].Size != 0.5 || open[0].Price != 100 {
+		t.Errorf("expected 0.5 @ 100 to be open, got %v", open)
+	}
+	if pnl := trades[0].PnL(Fees{}); pnl != 5 {
+		t.Errorf("expected 5, got %v", pnl)
+	}
+}
+
+func TestPairOrdersFilled(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	orders := Orders{
+		// a buy that got cancelled before it was filled
+		{Side: BUY, Market: "BTCUSDT", Size: 1, Price: 80, Status: CANCELLED, CreatedAt: start},
+		// a buy that got half filled, at a better price than the limit price
+		{Side: BUY, Market: "BTCUSDT", Size: 2, Price: 100, Filled: 1, AvgPrice: 99, Status: CANCELLED, CreatedAt: start.Add(time.Hour)},
+		// a sell that got cancelled before it was filled
+		{Side: SELL, Market: "BTCUSDT", Size: 1, Price: 150, Status: CANCELLED, CreatedAt: start.Add(2 * time.Hour)},
+		// a sell that got partially filled
+		{Side: SELL, Market: "BTCUSDT", Size: 1, Price: 110, Filled: 0.25, AvgPrice: 111, Status: CANCELLED, CreatedAt: start.Add(3 * time.Hour)},
+	}
+
+	trades, open := PairOrders(orders)
+
+	if len(trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(trades))
+	}
+	if trades[0].Size != 0.25 || trades[0].Bought != 99 || trades[0].Sold != 111 {
+		t.Errorf("expected 0.25 @ 99 -> 111, got %v @ %v -> %v", trades[0].Size, trades[0].Bought, trades[0].Sold)
+	}
+	if len(open) != 1 || open[0].Size != 0.75 || open[0].Price != 99 {
+		t.Errorf("expected 0.75 @ 99 to be open, got %v", open)
+	}
+}
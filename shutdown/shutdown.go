@@ -0,0 +1,68 @@
+package shutdown
+
+import (
+	"sync"
+	"time"
+)
+
+// EXIT_STOPPED is the exit code of a process that stopped because we asked it to (rather than because of an error)
+const EXIT_STOPPED = 3
+
+var (
+	mutex    sync.Mutex
+	running  sync.Mutex // held while the hooks run, so a second Run waits for the first one
+	once     sync.Once
+	stopping = make(chan struct{})
+	hooks    []func()
+)
+
+// Stop asks for an orderly stop. Every loop finishes its current iteration, and then returns.
+func Stop() {
+	once.Do(func() {
+		close(stopping)
+	})
+}
+
+// Stopping returns true if we have been asked to stop
+func Stopping() bool {
+	select {
+	case <-stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sleep waits for the duration to elapse. Returns false (without waiting any further) if we have been asked to stop.
+func Sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-stopping:
+		return false
+	}
+}
+
+// OnStop registers a func that runs after an orderly stop, for example: flush our state, or send a notification.
+// The funcs run in reverse order of registration.
+func OnStop(hook func()) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	hooks = append(hooks, hook)
+}
+
+// Run runs (and then forgets) the funcs that were registered with OnStop. If another Run is busy running them,
+// then Run waits for that one to finish.
+func Run() {
+	running.Lock()
+	defer running.Unlock()
+	mutex.Lock()
+	todo := hooks
+	hooks = nil
+	mutex.Unlock()
+	for i := len(todo) - 1; i >= 0; i-- {
+		todo[i]()
+	}
+}
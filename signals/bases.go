@@ -0,0 +1,364 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package signals
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/model"
+)
+
+// the parameters of the base-cracking algorithms
+type baseAlgo struct {
+	interval time.Duration // candle interval
+	bounce   float64       // percentage the price must bounce off a low before that low is a base
+}
+
+var baseAlgos = map[CryptoBaseScannerAlgo]baseAlgo{
+	CBS_ALGO_ORIGINAL:     {interval: model.CANDLE_1H, bounce: 3},
+	CBS_ALGO_DAY_TRADE:    {interval: model.CANDLE_15M, bounce: 2},
+	CBS_ALGO_CONSERVATIVE: {interval: model.CANDLE_1H, bounce: 5},
+	CBS_ALGO_POSITION:     {interval: model.CANDLE_4H, bounce: 10},
+}
+
+// the number of candles on either side of a low that must be higher than that low
+const baseWindow = 3
+
+// returns the index of the first candle (after start) that trades below price, or -1
+func crackedAt(candles model.Candles, price float64, start int) int {
+	for i := start; i < len(candles); i++ {
+		if candles[i].Low < price {
+			return i
+		}
+	}
+	return -1
+}
+
+// returns the index of the first candle (after start) that trades at or above price, or -1
+func recoveredAt(candles model.Candles, price float64, start int) int {
+	for i := start; i < len(candles); i++ {
+		if candles[i].High >= price {
+			return i
+		}
+	}
+	return -1
+}
+
+// returns the indices of the bases in candles: local lows the price has bounced off by at least bounce percent
+func findBases(candles model.Candles, bounce float64) []int {
+	var out []int
+	for i := range candles {
+		low := candles[i].Low
+		if low <= 0 {
+			continue
+		}
+		// is this a local low?
+		local := true
+		for n := i - baseWindow; n <= i+baseWindow && local; n++ {
+			if n >= 0 && n < len(candles) && n != i && candles[n].Low < low {
+				local = false
+			}
+		}
+		if !local {
+			continue
+		}
+		// did the price bounce off this low before it got cracked?
+		end := crackedAt(candles, low, i+1)
+		if end == -1 {
+			end = len(candles)
+		}
+		var high float64
+		for n := i + 1; n < end; n++ {
+			if candles[n].High > high {
+				high = candles[n].High
+			}
+		}
+		if ((high - low) / low * 100) >= bounce {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// analyzeBases finds the bases in candles, and then computes the same statistics as api.cryptobasescanner.com does:
+// 1) medianDrop is the median percentage the price dropped below a base after it got cracked, before it recovered,
+// 2) ratio is the percentage of the cracked bases that recovered,
+// 3) currentDrop is the percentage the current price is below (or above) the latest base.
+func analyzeBases(candles model.Candles, algo CryptoBaseScannerAlgo) *CryptoBase {
+	params := baseAlgos[algo]
+
+	last := candles.Last()
+	if last == nil {
+		return nil
+	}
+
+	bases := findBases(candles, params.bounce)
+	if len(bases) == 0 {
+		return nil
+	}
+
+	var (
+		drops     []float64
+		cracked   int
+		recovered int
+	)
+	for _, i := range bases[:len(bases)-1] {
+		price := candles[i].Low
+		crack := crackedAt(candles, price, i+1)
+		if crack == -1 {
+			continue
+		}
+		cracked++
+		recovery := recoveredAt(candles, price, crack+1)
+		if recovery == -1 {
+			continue
+		}
+		recovered++
+		low := price
+		for n := crack; n < recovery; n++ {
+			if candles[n].Low < low {
+				low = candles[n].Low
+			}
+		}
+		drops = append(drops, (low-price)/price*100)
+	}
+
+	out := &CryptoBase{
+		CurrentPrice: last.Close,
+	}
+
+	stat := MarketStat{Algorithm: algo.String()}
+	if len(drops) > 0 {
+		sort.Float64s(drops)
+		n := len(drops) / 2
+		if len(drops)%2 == 0 {
+			stat.MedianDrop = (drops[n-1] + drops[n]) / 2
+		} else {
+			stat.MedianDrop = drops[n]
+		}
+	}
+	if cracked > 0 {
+		stat.Ratio = float64(recovered) / float64(cracked) * 100
+	}
+	out.MarketStats = MarketStats{stat}
+
+	// the latest base, and the last time it got cracked (if it did)
+	i := bases[len(bases)-1]
+	price := candles[i].Low
+	out.LatestBase.CurrentDrop = (last.Close - price) / price * 100
+	for start := i + 1; start < len(candles); {
+		crack := crackedAt(candles, price, start)
+		if crack == -1 {
+			break
+		}
+		recovery := recoveredAt(candles, price, crack+1)
+		if recovery == -1 {
+			out.LatestBase.CrackedAt = candles[crack].Time.Format(time.RFC3339)
+			break
+		}
+		start = recovery + 1
+	}
+
+	return out
+}
+
+// Bases is a self-contained alternative to the cryptobasescanner.com channel. It computes the bases from the exchange candles.
+type Bases struct {
+	client       model.Client
+	cache        CryptoBases
+	algo         CryptoBaseScannerAlgo
+	dip          float64
+	successRatio float64
+}
+
+// Init reads the --algo, --dip and --success args
+func (self *Bases) Init() error {
+	var err error
+	self.algo, self.dip, self.successRatio, err = cryptoBaseScannerArgs()
+	return err
+}
+
+// Check returns an error if the exchange cannot give us the candles that our algorithm needs
+func (self *Bases) Check(exchange model.Exchange) error {
+	interval := baseAlgos[self.algo].interval
+	if !exchange.Capabilities().HasCandles(interval) {
+		return errors.Errorf("%s does not support %v candles. Channel: %s", exchange.GetInfo().Name, interval, self.GetName())
+	}
+	return nil
+}
+
+func (self *Bases) GetName() string {
+	return "bases"
+}
+
+func (self *Bases) GetValidity() (time.Duration, error) {
+	return 12 * time.Hour, nil
+}
+
+func (self *Bases) GetRateLimit() time.Duration {
+	return 15 * time.Minute
+}
+
+func (self *Bases) GetOrderType() model.OrderType {
+	return model.MARKET
+}
+
+func (self *Bases) getClient(exchange model.Exchange, sandbox bool) (model.Client, error) {
+	if self.client == nil {
+		var err error
+		self.client, err = exchange.GetClient(model.PUBLIC, sandbox)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return self.client, nil
+}
+
+func (self *Bases) get(
+	ctx context.Context,
+	exchange model.Exchange,
+	quote model.Assets,
+	algorithm CryptoBaseScannerAlgo,
+	btcVolumeMin,
+	dip,
+	successRatio float64,
+	validity time.Duration,
+	sandbox, debug bool,
+	ignore []string,
+) error {
+	var err error
+
+	var client model.Client
+	if client, err = self.getClient(exchange, sandbox); err != nil {
+		return err
+	}
+
+	var markets []model.Market
+	if markets, err = exchange.GetMarkets(ctx, true, sandbox, ignore); err != nil {
+		return err
+	}
+
+	// add new bases to the cache
+	for _, market := range markets {
+		if !quote.HasAsset(market.Quote) {
+			continue
+		}
+
+		var candles model.Candles
+		if candles, err = exchange.GetCandles(ctx, client, market.Name, baseAlgos[algorithm].interval); err != nil {
+			log.Printf("[ERROR] %v. Market: %s\n", err, market.Name)
+			continue
+		}
+
+		base := analyzeBases(candles, algorithm)
+		if base == nil {
+			continue
+		}
+		base.ExchangeName = exchange.GetInfo().Name
+		base.BaseCurrency = market.Base
+		base.QuoteCurrency = market.Quote
+
+		// if the exchange doesn't know the volume, then don't let the volume stop us
+		volumeMin := btcVolumeMin
+		if btcVolumeMin > 0 {
+			var stats *model.Stats
+			if stats, err = exchange.Get24h(ctx, client, market.Name); err == nil && stats.BtcVolume > 0 {
+				base.BtcVolume = stats.BtcVolume
+			} else {
+				volumeMin = 0
+			}
+		}
+
+		var buy bool
+		if buy, err = base.Buy(exchange, algorithm, volumeMin, dip, successRatio, sandbox, debug); err != nil {
+			log.Printf("[ERROR] %v\n", err)
+		} else {
+			if buy {
+				if self.cache.IndexByMarket(base) == -1 {
+					self.cache = append(self.cache, *base)
+				}
+			}
+		}
+	}
+
+	// remove bases from the cache that are older than 12 hours
+	if validity > 0 {
+		i := 0
+		for i < len(self.cache) {
+			var date *time.Time
+			if date, err = self.cache[i].GetCrackedAt(); err != nil {
+				return err
+			}
+			if time.Since(*date) > validity {
+				self.cache = append(self.cache[:i], self.cache[i+1:]...)
+			} else {
+				i++
+			}
+		}
+	}
+
+	return nil
+}
+
+func (self *Bases) GetMarkets(
+	ctx context.Context,
+	exchange model.Exchange,
+	quote model.Assets,
+	btcVolumeMin float64,
+	valid time.Duration,
+	sandbox, debug bool,
+	ignore []string,
+) (model.Markets, error) {
+	var (
+		err error
+		out model.Markets
+	)
+
+	if err = self.get(ctx, exchange, quote, self.algo, btcVolumeMin, self.dip, self.successRatio, valid, sandbox, debug, ignore); err != nil {
+		return nil, err
+	}
+
+	for _, base := range self.cache {
+		if debug {
+			msg, err := json.Marshal(base)
+			if err == nil {
+				log.Printf("[DEBUG] %s", string(msg))
+			}
+		}
+		market := base.Market(exchange)
+		if out == nil || out.IndexOf(market) == -1 {
+			out = append(out, market)
+		}
+	}
+
+	return out, nil
+}
+
+func (self *Bases) GetCalls(ctx context.Context, exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
+	var (
+		out model.Calls
+	)
+	for _, base := range self.cache {
+		if strings.EqualFold(base.Market(exchange), market) {
+			price := base.CurrentPrice
+			if out == nil || out.IndexByPrice(price) == -1 {
+				out = append(out, model.Call{
+					Buy: &model.Buy{
+						Market: market,
+						Price:  price,
+					},
+				})
+			}
+		}
+	}
+	return out, nil
+}
+
+func NewBases() model.Channel {
+	return &Bases{}
+}
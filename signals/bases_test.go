@@ -0,0 +1,46 @@
+package signals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/svanas/nefertiti/model"
+)
+
+func candles(lows ...float64) model.Candles {
+	var out model.Candles
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, low := range lows {
+		out = append(out, model.Candle{
+			Time:  start.Add(time.Duration(i) * time.Hour),
+			Open:  low,
+			High:  low * 1.01,
+			Low:   low,
+			Close: low,
+		})
+	}
+	return out
+}
+
+func TestAnalyzeBases(t *testing.T) {
+	// a base at 100 that gets cracked (down to 90) and recovers, followed by a base at 100 that gets cracked (down to 95) and doesn't recover
+	base := analyzeBases(candles(
+		120, 115, 110, 100, 110, 115, 120, 110, 90, 95, 110, 120,
+		115, 110, 100, 110, 115, 120, 100, 98, 96, 95, 95, 95,
+	), CBS_ALGO_DAY_TRADE)
+	if base == nil {
+		t.Fatal("expected a base, got nil")
+	}
+	if base.MarketStats[0].MedianDrop >= 0 {
+		t.Errorf("expected a negative median drop, got %f", base.MarketStats[0].MedianDrop)
+	}
+	if base.MarketStats[0].Ratio <= 0 {
+		t.Errorf("expected a positive ratio, got %f", base.MarketStats[0].Ratio)
+	}
+	if base.LatestBase.CurrentDrop >= 0 {
+		t.Errorf("expected a negative current drop, got %f", base.LatestBase.CurrentDrop)
+	}
+	if base.LatestBase.CrackedAt == "" {
+		t.Error("expected the latest base to be cracked")
+	}
+}
@@ -0,0 +1,351 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package signals
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/flag"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/precision"
+)
+
+type ConsensusMerge int
+
+const (
+	CONSENSUS_MERGE_MIN ConsensusMerge = iota
+	CONSENSUS_MERGE_MEDIAN
+)
+
+var ConsensusMergeString = map[ConsensusMerge]string{
+	CONSENSUS_MERGE_MIN:    "min",
+	CONSENSUS_MERGE_MEDIAN: "median",
+}
+
+func (merge *ConsensusMerge) String() string {
+	return ConsensusMergeString[*merge]
+}
+
+func NewConsensusMerge(data string) (ConsensusMerge, error) {
+	for merge := range ConsensusMergeString {
+		if merge.String() == data {
+			return merge, nil
+		}
+	}
+	return CONSENSUS_MERGE_MIN, errors.Errorf("%s does not exist", data)
+}
+
+// a vote is the most recent time a channel has called a market, together with the call(s) it made.
+type vote struct {
+	Channel string
+	Market  string
+	Calls   model.Calls
+	Seen    time.Time
+}
+
+type votes []vote
+
+func (v votes) indexOf(channel, market string) int {
+	for i, e := range v {
+		if e.Channel == channel && e.Market == market {
+			return i
+		}
+	}
+	return -1
+}
+
+// returns the votes for a market that are younger than the window
+func (v votes) market(market string, window time.Duration) votes {
+	var out votes
+	for _, e := range v {
+		if e.Market == market && (window == 0 || time.Since(e.Seen) <= window) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// merges the calls of the agreeing channels into one call.
+// the price is the min or median of the prices, the stop is the tightest (eg. highest) stop.
+func (v votes) merge(market string, merge ConsensusMerge, prec int) model.Call {
+	var (
+		prices []float64
+		stop   string
+		high   float64
+	)
+	for _, e := range v {
+		for _, call := range e.Calls {
+			if call.Price > 0 {
+				prices = append(prices, call.Price)
+			}
+			if call.HasStop() && call.ParseStop() > high {
+				high = call.ParseStop()
+				stop = call.Stop
+			}
+		}
+	}
+
+	out := model.Call{
+		Buy: &model.Buy{
+			Market: market,
+		},
+		Stop: stop,
+	}
+
+	if len(prices) > 0 {
+		sort.Float64s(prices)
+		if merge == CONSENSUS_MERGE_MEDIAN {
+			n := len(prices) / 2
+			if len(prices)%2 == 0 {
+				out.Price = (prices[n-1] + prices[n]) / 2
+			} else {
+				out.Price = prices[n]
+			}
+		} else {
+			out.Price = prices[0]
+		}
+		out.Price = precision.Round(out.Price, prec)
+	}
+
+	return out
+}
+
+// Consensus is a meta-channel. It wraps two or more channels, and only calls a market when N of M channels agree on that market within a time window.
+type Consensus struct {
+	channels []model.Channel
+	agree    int
+	window   time.Duration
+	merge    ConsensusMerge
+	client   model.Client
+	votes    votes
+	cache    model.Calls
+}
+
+func (self *Consensus) Init() error {
+	var err error
+
+	// --channels=X,Y,Z
+	arg := flag.Get("channels")
+	if !arg.Exists {
+		return errors.New("missing argument: channels")
+	}
+	self.channels = nil
+	for _, name := range arg.Split() {
+		if strings.EqualFold(name, self.GetName()) {
+			return errors.Errorf("channels %v is invalid", arg)
+		}
+		var channel model.Channel
+		if channel, err = New().FindByName(name); err != nil {
+			return err
+		}
+		if channel == nil {
+			return errors.Errorf("signals %s does not exist", name)
+		}
+		self.channels = append(self.channels, channel)
+	}
+	if len(self.channels) < 2 {
+		return errors.Errorf("channels %v is invalid. please specify at least two channels", arg)
+	}
+
+	// --agree=N
+	self.agree = len(self.channels)
+	arg = flag.Get("agree")
+	if arg.Exists {
+		var agree int64
+		if agree, err = arg.Int64(); err != nil {
+			return errors.Errorf("agree %v is invalid", arg)
+		}
+		if agree < 1 || int(agree) > len(self.channels) {
+			return errors.Errorf("agree %v is not in the 1..%d range", arg, len(self.channels))
+		}
+		self.agree = int(agree)
+	}
+
+	// --window=X (in hours)
+	self.window = 1 * time.Hour
+	arg = flag.Get("window")
+	if arg.Exists {
+		var window float64
+		if window, err = arg.Float64(); err != nil || window < 0 {
+			return errors.Errorf("window %v is invalid", arg)
+		}
+		self.window = time.Duration(window * float64(time.Hour))
+	}
+
+	// --merge=[min|median]
+	self.merge = CONSENSUS_MERGE_MIN
+	arg = flag.Get("merge")
+	if arg.Exists {
+		if self.merge, err = NewConsensusMerge(arg.String()); err != nil {
+			return errors.Errorf("merge %v is invalid", arg)
+		}
+	}
+
+	return nil
+}
+
+// Check returns an error if one of our channels cannot run on the exchange
+func (self *Consensus) Check(exchange model.Exchange) error {
+	for _, channel := range self.channels {
+		if check, ok := channel.(model.ChannelCheck); ok {
+			if err := check.Check(exchange); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (self *Consensus) GetName() string {
+	return "consensus"
+}
+
+// returns the longest validity of the wrapped channels
+func (self *Consensus) GetValidity() (time.Duration, error) {
+	var out time.Duration
+	for _, channel := range self.channels {
+		valid, err := channel.GetValidity()
+		if err != nil {
+			return out, err
+		}
+		if valid > out {
+			out = valid
+		}
+	}
+	return out, nil
+}
+
+// returns the strictest rate limit of the wrapped channels
+func (self *Consensus) GetRateLimit() time.Duration {
+	var out time.Duration
+	for _, channel := range self.channels {
+		if channel.GetRateLimit() > out {
+			out = channel.GetRateLimit()
+		}
+	}
+	return out
+}
+
+// returns LIMIT if at least one of the wrapped channels is a LIMIT channel, otherwise MARKET
+func (self *Consensus) GetOrderType() model.OrderType {
+	for _, channel := range self.channels {
+		if channel.GetOrderType() == model.LIMIT {
+			return model.LIMIT
+		}
+	}
+	return model.MARKET
+}
+
+func (self *Consensus) getClient(exchange model.Exchange, sandbox bool) (model.Client, error) {
+	if self.client == nil {
+		var err error
+		self.client, err = exchange.GetClient(model.PUBLIC, sandbox)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return self.client, nil
+}
+
+func (self *Consensus) GetMarkets(
+	ctx context.Context,
+	exchange model.Exchange,
+	quote model.Assets,
+	btcVolumeMin float64,
+	valid time.Duration,
+	sandbox, debug bool,
+	ignore []string,
+) (model.Markets, error) {
+	var err error
+
+	// step #1: collect the votes
+	for _, channel := range self.channels {
+		var markets model.Markets
+		if markets, err = channel.GetMarkets(ctx, exchange, quote, btcVolumeMin, valid, sandbox, debug, ignore); err != nil {
+			return nil, errors.Errorf("%v. Channel: %s", err, channel.GetName())
+		}
+		for _, market := range markets {
+			var calls model.Calls
+			if calls, err = channel.GetCalls(ctx, exchange, market, sandbox, debug); err != nil {
+				return nil, errors.Errorf("%v. Channel: %s", err, channel.GetName())
+			}
+			i := self.votes.indexOf(channel.GetName(), market)
+			if i == -1 {
+				self.votes = append(self.votes, vote{
+					Channel: channel.GetName(),
+					Market:  market,
+					Calls:   calls,
+					Seen:    time.Now(),
+				})
+			} else {
+				self.votes[i].Calls = calls
+				self.votes[i].Seen = time.Now()
+			}
+		}
+	}
+
+	// step #2: remove the votes that are outside of the window
+	if self.window > 0 {
+		i := 0
+		for i < len(self.votes) {
+			if time.Since(self.votes[i].Seen) > self.window {
+				self.votes = append(self.votes[:i], self.votes[i+1:]...)
+			} else {
+				i++
+			}
+		}
+	}
+
+	// step #3: count the votes, merge the calls we agree on
+	var client model.Client
+	if client, err = self.getClient(exchange, sandbox); err != nil {
+		return nil, err
+	}
+
+	var out model.Markets
+	self.cache = nil
+	for _, v := range self.votes {
+		if out.IndexOf(v.Market) > -1 {
+			continue
+		}
+		agreed := self.votes.market(v.Market, self.window)
+		if len(agreed) < self.agree {
+			if debug {
+				log.Printf("[DEBUG] Ignoring %s because %d out of %d channels agree (need %d)\n", v.Market, len(agreed), len(self.channels), self.agree)
+			}
+			continue
+		}
+		var prec int
+		if prec, err = exchange.GetPricePrec(ctx, client, v.Market); err != nil {
+			return nil, err
+		}
+		self.cache = append(self.cache, agreed.merge(v.Market, self.merge, prec))
+		out = append(out, v.Market)
+	}
+
+	return out, nil
+}
+
+func (self *Consensus) GetCalls(ctx context.Context, exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
+	var out model.Calls
+	for _, call := range self.cache {
+		if strings.EqualFold(call.Market, market) {
+			out = append(out, model.Call{
+				Buy: &model.Buy{
+					Market: call.Market,
+					Price:  call.Price,
+				},
+				Stop: call.Stop,
+			})
+		}
+	}
+	return out, nil
+}
+
+func NewConsensus() model.Channel {
+	return &Consensus{}
+}
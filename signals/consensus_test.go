@@ -0,0 +1,60 @@
+package signals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/svanas/nefertiti/model"
+)
+
+func newVote(channel string, price float64, stop string) vote {
+	return vote{
+		Channel: channel,
+		Market:  "BTC-EUR",
+		Calls: model.Calls{model.Call{
+			Buy: &model.Buy{
+				Market: "BTC-EUR",
+				Price:  price,
+			},
+			Stop: stop,
+		}},
+		Seen: time.Now(),
+	}
+}
+
+func TestMerge(t *testing.T) {
+	v := votes{
+		newVote("listings", 100, "90"),
+		newVote("volume", 120, "95"),
+		newVote("MiningHamster", 110, ""),
+		newVote("quality-signals", 130, "80"),
+	}
+
+	call := v.merge("BTC-EUR", CONSENSUS_MERGE_MIN, 2)
+	if call.Price != 100 {
+		t.Errorf("TestMerge failed, got: %v, want: %v.", call.Price, 100)
+	}
+	if call.Stop != "95" {
+		t.Errorf("TestMerge failed, got: %v, want: %v.", call.Stop, "95")
+	}
+
+	call = v.merge("BTC-EUR", CONSENSUS_MERGE_MEDIAN, 2)
+	if call.Price != 115 {
+		t.Errorf("TestMerge failed, got: %v, want: %v.", call.Price, 115)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	v := votes{
+		newVote("listings", 100, ""),
+		newVote("volume", 120, ""),
+	}
+	v[0].Seen = time.Now().Add(-2 * time.Hour)
+
+	if n := len(v.market("BTC-EUR", time.Hour)); n != 1 {
+		t.Errorf("TestWindow failed, got: %v, want: %v.", n, 1)
+	}
+	if n := len(v.market("BTC-EUR", 3*time.Hour)); n != 2 {
+		t.Errorf("TestWindow failed, got: %v, want: %v.", n, 2)
+	}
+}
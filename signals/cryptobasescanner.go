@@ -2,6 +2,7 @@
 package signals
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -15,6 +16,7 @@ import (
 	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
 	"github.com/svanas/nefertiti/passphrase"
+	"github.com/svanas/nefertiti/transport"
 )
 
 type CryptoBaseScannerAlgo int
@@ -180,6 +182,41 @@ type CryptoBaseScannerResponse struct {
 	Bases CryptoBases `json:"bases"`
 }
 
+// reads the --algo, --dip and --success args
+func cryptoBaseScannerArgs() (algo CryptoBaseScannerAlgo, dip, successRatio float64, err error) {
+	algo = CBS_ALGO_DAY_TRADE
+
+	if dip, err = flag.Dip(0); err != nil {
+		return algo, dip, successRatio, err
+	}
+
+	successRatio = func() float64 {
+		if dip == 0 {
+			return 0
+		} else {
+			return 60
+		}
+	}()
+
+	arg := flag.Get("success")
+	if arg.Exists {
+		successRatio, err = arg.Float64()
+		if err != nil {
+			return algo, dip, successRatio, errors.Errorf("success %v is invalid", arg)
+		}
+	}
+
+	arg = flag.Get("algo")
+	if arg.Exists {
+		algo, err = NewCryptoBaseScannerAlgo(arg.String())
+		if err != nil {
+			return algo, dip, successRatio, errors.Errorf("algo %v does not exist", arg)
+		}
+	}
+
+	return algo, dip, successRatio, nil
+}
+
 type CryptoBaseScanner struct {
 	apiKey string
 	cache  CryptoBases
@@ -187,6 +224,7 @@ type CryptoBaseScanner struct {
 
 // https://cryptobasescanner.docs.apiary.io/#
 func (self *CryptoBaseScanner) get(
+	ctx context.Context,
 	exchange model.Exchange,
 	quote model.Assets,
 	algorithm CryptoBaseScannerAlgo,
@@ -200,7 +238,7 @@ func (self *CryptoBaseScanner) get(
 
 	// create the request
 	var req *http.Request
-	if req, err = http.NewRequest("GET", "https://api.cryptobasescanner.com/v1/bases", nil); err != nil {
+	if req, err = http.NewRequestWithContext(ctx, "GET", "https://api.cryptobasescanner.com/v1/bases", nil); err != nil {
 		return err
 	}
 
@@ -218,7 +256,7 @@ func (self *CryptoBaseScanner) get(
 
 	// submit the http request
 	var resp *http.Response
-	if resp, err = http.DefaultClient.Do(req); err != nil {
+	if resp, err = transport.New().Do(req); err != nil {
 		return err
 	}
 
@@ -309,6 +347,7 @@ func (self *CryptoBaseScanner) GetOrderType() model.OrderType {
 }
 
 func (self *CryptoBaseScanner) GetMarkets(
+	ctx context.Context,
 	exchange model.Exchange,
 	quote model.Assets,
 	btcVolumeMin float64,
@@ -317,42 +356,18 @@ func (self *CryptoBaseScanner) GetMarkets(
 	ignore []string,
 ) (model.Markets, error) {
 	var (
-		err  error
-		dip  float64
-		arg  *flag.Flag
-		out  model.Markets
-		algo CryptoBaseScannerAlgo = CBS_ALGO_DAY_TRADE
+		err          error
+		dip          float64
+		out          model.Markets
+		algo         CryptoBaseScannerAlgo
+		successRatio float64
 	)
 
-	if dip, err = flag.Dip(0); err != nil {
+	if algo, dip, successRatio, err = cryptoBaseScannerArgs(); err != nil {
 		return nil, err
 	}
 
-	successRatio := func() float64 {
-		if dip == 0 {
-			return 0
-		} else {
-			return 60
-		}
-	}()
-
-	arg = flag.Get("success")
-	if arg.Exists {
-		successRatio, err = arg.Float64()
-		if err != nil {
-			return nil, errors.Errorf("success %v is invalid", arg)
-		}
-	}
-
-	arg = flag.Get("algo")
-	if arg.Exists {
-		algo, err = NewCryptoBaseScannerAlgo(arg.String())
-		if err != nil {
-			return nil, errors.Errorf("algo %v does not exist", arg)
-		}
-	}
-
-	if err = self.get(exchange, quote, algo, btcVolumeMin, dip, successRatio, valid, sandbox, debug); err != nil {
+	if err = self.get(ctx, exchange, quote, algo, btcVolumeMin, dip, successRatio, valid, sandbox, debug); err != nil {
 		return nil, err
 	}
 
@@ -372,7 +387,7 @@ func (self *CryptoBaseScanner) GetMarkets(
 	return out, nil
 }
 
-func (self *CryptoBaseScanner) GetCalls(exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
+func (self *CryptoBaseScanner) GetCalls(ctx context.Context, exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
 	var (
 		out model.Calls
 	)
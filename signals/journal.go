@@ -0,0 +1,375 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package signals
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/session"
+)
+
+const (
+	journalFile = "signals.journal.json"
+	journalTTL  = 30 * 24 * time.Hour // we stop tracking a call that hasn't resolved after this period
+	journalPoll = 15 * time.Minute    // time between two attempts to resolve the pending calls
+)
+
+type Outcome int
+
+const (
+	OUTCOME_OPEN    Outcome = iota // we opened a buy order; it hasn't been filled (yet)
+	OUTCOME_SKIPPED                // we ignored the call, see Reason
+	OUTCOME_FILLED                 // our buy order got filled; it hasn't been sold (yet)
+	OUTCOME_TARGET                 // we sold at (or above) our buy price
+	OUTCOME_STOPPED                // we sold below our buy price, or at the stop
+	OUTCOME_EXPIRED                // our buy order got cancelled before it was filled
+)
+
+var OutcomeString = map[Outcome]string{
+	OUTCOME_OPEN:    "open",
+	OUTCOME_SKIPPED: "skipped",
+	OUTCOME_FILLED:  "filled",
+	OUTCOME_TARGET:  "target",
+	OUTCOME_STOPPED: "stopped",
+	OUTCOME_EXPIRED: "expired",
+}
+
+func (outcome *Outcome) String() string {
+	return OutcomeString[*outcome]
+}
+
+func (outcome Outcome) MarshalJSON() ([]byte, error) {
+	return json.Marshal(outcome.String())
+}
+
+func (outcome *Outcome) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	for o := range OutcomeString {
+		if o.String() == str {
+			*outcome = o
+			return nil
+		}
+	}
+	return errors.Errorf("outcome %s does not exist", str)
+}
+
+// JournalEntry is a call we got from a channel, together with its eventual outcome.
+type JournalEntry struct {
+	Channel  string      `json:"channel"`
+	Exchange string      `json:"exchange"`
+	Market   string      `json:"market"`
+	Price    float64     `json:"price"`
+	Size     float64     `json:"size"`
+	Stop     string      `json:"stop,omitempty"`
+	Target   string      `json:"target,omitempty"`
+	Reason   string      `json:"reason,omitempty"`
+	BuyID    string      `json:"buyId,omitempty"`  // the ID of the buy order we opened for this call
+	SellID   string      `json:"sellId,omitempty"` // the ID of the sell order that closed this call
+	Outcome  Outcome     `json:"outcome"`
+	Called   time.Time   `json:"called"`
+	Filled   time.Time   `json:"filled"`
+	Bought   float64     `json:"bought,omitempty"`
+	Closed   time.Time   `json:"closed"`
+	Sold     float64     `json:"sold,omitempty"`
+	Fees     *model.Fees `json:"fees,omitempty"`
+}
+
+// pending returns true if we haven't resolved the outcome of this call yet, and we are still tracking it
+func (entry *JournalEntry) pending() bool {
+	return (entry.Outcome == OUTCOME_OPEN || entry.Outcome == OUTCOME_FILLED) && time.Since(entry.Called) < journalTTL
+}
+
+// Return is the (sold - bought) / bought ratio after fees, for example: 0.05 is +5%
+func (entry *JournalEntry) Return() float64 {
+	if entry.Fees != nil {
+		return entry.Fees.Return(entry.Bought, entry.Sold)
+	}
+	if entry.Bought > 0 && entry.Sold > 0 {
+		return (entry.Sold - entry.Bought) / entry.Bought
+	}
+	return 0
+}
+
+// TimeToTarget is the time between the call and the moment we sold
+func (entry *JournalEntry) TimeToTarget() time.Duration {
+	if entry.Closed.IsZero() {
+		return 0
+	}
+	return entry.Closed.Sub(entry.Called)
+}
+
+type Journal []JournalEntry
+
+var journalUpdated time.Time
+
+func LoadJournal() (*Journal, error) {
+	var (
+		err error
+		raw []byte
+		out Journal
+	)
+	if raw, err = ioutil.ReadFile(session.GetSessionFile(journalFile)); err != nil {
+		if os.IsNotExist(err) {
+			return &out, nil
+		}
+		return nil, errors.Wrap(err, 1)
+	}
+	if err = json.Unmarshal(raw, &out); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	return &out, nil
+}
+
+func (self *Journal) Save() error {
+	raw, err := json.Marshal(self)
+	if err != nil {
+		return errors.Wrap(err, 1)
+	}
+	if err = ioutil.WriteFile(session.GetSessionFile(journalFile), raw, 0600); err != nil {
+		return errors.Wrap(err, 1)
+	}
+	return nil
+}
+
+func (self *Journal) indexOf(channel, exchange, market string, price float64, since time.Time) int {
+	for i, entry := range *self {
+		if entry.Channel == channel && entry.Exchange == exchange && entry.Market == market && entry.Price == price && entry.Called.After(since) {
+			return i
+		}
+	}
+	return -1
+}
+
+// claimed returns true if one of the entries in the journal has been matched with this order
+func (self *Journal) claimed(exchange string, id string) bool {
+	for _, entry := range *self {
+		if entry.Exchange == exchange && (entry.BuyID == id || entry.SellID == id) {
+			return true
+		}
+	}
+	return false
+}
+
+// buyOrder returns the ID of the youngest buy order on a market (that was opened since a point in time, and isn't part of the journal yet)
+func (self *Journal) buyOrder(ctx context.Context, exchange model.Exchange, client model.Client, market string, since time.Time) (string, error) {
+	for _, get := range []func(ctx context.Context, client model.Client, market string) (model.Orders, error){exchange.GetOpened, exchange.GetClosed} {
+		orders, err := get(ctx, client, market)
+		if err != nil {
+			return "", err
+		}
+		var out *model.Order
+		for i, order := range orders {
+			if order.Side == model.BUY && order.ID != "" && !order.CreatedAt.Before(since) && !self.claimed(exchange.GetInfo().Name, order.ID) {
+				if out == nil || order.CreatedAt.After(out.CreatedAt) {
+					out = &orders[i]
+				}
+			}
+		}
+		if out != nil {
+			return out.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// Record adds a call to the journal. Calls that are skipped without a reason (because we already know about them) are not recorded.
+// A skipped call is recorded once per validity period, so we don't flood the journal with the same call over and over.
+// A call we have acted on gets matched with the buy order we opened for it, so we can follow that order from now on.
+func (self *Journal) Record(ctx context.Context, channel model.Channel, exchange model.Exchange, client model.Client, call *model.Call, valid time.Duration) error {
+	if call.Skip && call.Reason == "" {
+		return nil
+	}
+	if call.Skip && self.indexOf(channel.GetName(), exchange.GetInfo().Name, call.Market, call.Price, time.Now().Add(-valid)) > -1 {
+		return nil
+	}
+	var (
+		err   error
+		buyID string
+	)
+	if !call.Skip {
+		if buyID, err = self.buyOrder(ctx, exchange, client, call.Market, time.Now().Add(-time.Minute)); err != nil {
+			return err
+		}
+	}
+	*self = append(*self, JournalEntry{
+		Channel:  channel.GetName(),
+		Exchange: exchange.GetInfo().Name,
+		Market:   call.Market,
+		Price:    call.Price,
+		Size:     call.Size,
+		Stop:     call.Stop,
+		Target:   call.Target,
+		Reason:   call.Reason,
+		BuyID:    buyID,
+		Outcome: func() Outcome {
+			if call.Skip {
+				return OUTCOME_SKIPPED
+			}
+			return OUTCOME_OPEN
+		}(),
+		Called: time.Now(),
+	})
+	return nil
+}
+
+// Update resolves the outcome of the pending calls: it follows the buy order we opened for a call, and then looks for
+// the sell order that closed it. We do so once every 15 minutes, and stop tracking a call after 30 days. A call whose
+// orders we cannot look up is logged and skipped, so that it does not hold up the other calls.
+func (self *Journal) Update(ctx context.Context, exchange model.Exchange, client model.Client) error {
+	if time.Since(journalUpdated) < journalPoll {
+		return nil
+	}
+	journalUpdated = time.Now()
+
+	closed := make(map[string]model.Orders)
+	for i := range *self {
+		entry := &(*self)[i]
+		if entry.Exchange != exchange.GetInfo().Name {
+			continue
+		}
+
+		if !entry.pending() {
+			// a buy order that hasn't been filled for this long won't be filled anymore
+			if entry.Outcome == OUTCOME_OPEN {
+				entry.Outcome = OUTCOME_EXPIRED
+				entry.Closed = time.Now()
+			}
+			continue
+		}
+
+		if entry.Outcome == OUTCOME_OPEN && entry.BuyID != "" {
+			order, err := exchange.GetOrder(ctx, client, entry.Market, entry.BuyID)
+			if err != nil {
+				log.Printf("[ERROR] %v. Market: %s, order: %s\n", err, entry.Market, entry.BuyID)
+				continue
+			}
+			if order.Filled > 0 && order.Status.Closed() {
+				entry.Outcome = OUTCOME_FILLED
+				entry.Filled = order.CreatedAt
+				entry.Bought = order.Price
+				if order.AvgPrice > 0 {
+					entry.Bought = order.AvgPrice
+				}
+				fees := model.GetFees(ctx, exchange, client, entry.Market)
+				entry.Fees = &fees
+			} else if order.Status.Closed() {
+				entry.Outcome = OUTCOME_EXPIRED
+				entry.Closed = time.Now()
+			}
+		}
+
+		if entry.Outcome == OUTCOME_FILLED {
+			orders, ok := closed[entry.Market]
+			if !ok {
+				var err error
+				if orders, err = exchange.GetClosed(ctx, client, entry.Market); err != nil {
+					log.Printf("[ERROR] %v. Market: %s\n", err, entry.Market)
+					continue
+				}
+				closed[entry.Market] = orders
+			}
+			for _, order := range orders {
+				if order.Side == model.SELL && order.Filled > 0 && order.CreatedAt.After(entry.Filled) && !self.claimed(entry.Exchange, order.ID) {
+					entry.SellID = order.ID
+					entry.Closed = order.CreatedAt
+					entry.Sold = order.Price
+					if order.AvgPrice > 0 {
+						entry.Sold = order.AvgPrice
+					}
+					call := model.Call{Stop: entry.Stop}
+					if (call.HasStop() && entry.Sold <= call.ParseStop()) || entry.Sold < entry.Bought {
+						entry.Outcome = OUTCOME_STOPPED
+					} else {
+						entry.Outcome = OUTCOME_TARGET
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ChannelScore summarizes the performance of one channel.
+type ChannelScore struct {
+	Channel      string
+	Calls        int
+	Skipped      int
+	Open         int
+	Filled       int
+	Target       int
+	Stopped      int
+	Expired      int
+	HitRate      float64 // the ratio of sold-at-target versus resolved calls
+	AvgReturn    float64 // the average return of the calls we sold
+	TimeToTarget time.Duration
+}
+
+// Score returns the performance of every channel in the journal.
+func (self *Journal) Score() []ChannelScore {
+	var out []ChannelScore
+
+	indexOf := func(channel string) int {
+		for i, score := range out {
+			if score.Channel == channel {
+				return i
+			}
+		}
+		return -1
+	}
+
+	sold := make(map[string]float64)
+	took := make(map[string]time.Duration)
+
+	for _, entry := range *self {
+		i := indexOf(entry.Channel)
+		if i == -1 {
+			out = append(out, ChannelScore{Channel: entry.Channel})
+			i = len(out) - 1
+		}
+		score := &out[i]
+		score.Calls++
+		switch entry.Outcome {
+		case OUTCOME_SKIPPED:
+			score.Skipped++
+		case OUTCOME_OPEN:
+			score.Open++
+		case OUTCOME_FILLED:
+			score.Filled++
+		case OUTCOME_TARGET:
+			score.Target++
+			sold[entry.Channel] += entry.Return()
+			took[entry.Channel] += entry.TimeToTarget()
+		case OUTCOME_STOPPED:
+			score.Stopped++
+			sold[entry.Channel] += entry.Return()
+		case OUTCOME_EXPIRED:
+			score.Expired++
+		}
+	}
+
+	for i := range out {
+		score := &out[i]
+		if resolved := score.Target + score.Stopped + score.Expired; resolved > 0 {
+			score.HitRate = float64(score.Target) / float64(resolved)
+		}
+		if n := score.Target + score.Stopped; n > 0 {
+			score.AvgReturn = sold[score.Channel] / float64(n)
+		}
+		if score.Target > 0 {
+			score.TimeToTarget = took[score.Channel] / time.Duration(score.Target)
+		}
+	}
+
+	return out
+}
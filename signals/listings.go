@@ -2,10 +2,20 @@
 package signals
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/flag"
 	"github.com/svanas/nefertiti/model"
+	"github.com/svanas/nefertiti/notify"
+	"github.com/svanas/nefertiti/session"
 )
 
 type Listing struct {
@@ -14,13 +24,86 @@ type Listing struct {
 	Created time.Time
 }
 
+// known is the set of markets we are aware of on an exchange, persisted across restarts.
+type known struct {
+	Updated time.Time `json:"updated"`
+	Markets []string  `json:"markets"`
+}
+
+func knownFile(exchange model.Exchange, sandbox bool) string {
+	name := "listings." + strings.ToLower(exchange.GetInfo().Name)
+	if sandbox {
+		name = name + ".sandbox"
+	}
+	return session.GetSessionFile(name + ".json")
+}
+
+func loadKnown(exchange model.Exchange, sandbox bool) (*known, error) {
+	raw, err := ioutil.ReadFile(knownFile(exchange, sandbox))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, 1)
+	}
+	var out known
+	if err = json.Unmarshal(raw, &out); err != nil {
+		return nil, errors.Wrap(err, 1)
+	}
+	return &out, nil
+}
+
+func (self *known) save(exchange model.Exchange, sandbox bool) error {
+	self.Updated = time.Now()
+	raw, err := json.Marshal(self)
+	if err != nil {
+		return errors.Wrap(err, 1)
+	}
+	if err = ioutil.WriteFile(knownFile(exchange, sandbox), raw, 0600); err != nil {
+		return errors.Wrap(err, 1)
+	}
+	return nil
+}
+
+func (self *known) indexOf(market string) int {
+	for i, name := range self.Markets {
+		if name == market {
+			return i
+		}
+	}
+	return -1
+}
+
 type Listings struct {
-	old    []model.Market
-	client interface{}
-	cache  []Listing
+	old     *known
+	age     time.Duration
+	client  model.Client
+	service model.Notify
+	level   int64
+	cache   []Listing
 }
 
 func (self *Listings) Init() error {
+	var err error
+
+	// --age=X (in hours)
+	self.age = 1 * time.Hour
+	arg := flag.Get("age")
+	if arg.Exists {
+		var age float64
+		if age, err = arg.Float64(); err != nil || age < 0 {
+			return errors.Errorf("age %v is invalid", arg)
+		}
+		self.age = time.Duration(age * float64(time.Hour))
+	}
+
+	if self.service, err = notify.New().Init(flag.Interactive(), false); err != nil {
+		return err
+	}
+	if self.level, err = notify.Level(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -40,7 +123,7 @@ func (self *Listings) GetOrderType() model.OrderType {
 	return model.MARKET
 }
 
-func (self *Listings) getClient(exchange model.Exchange, sandbox bool) (interface{}, error) {
+func (self *Listings) getClient(exchange model.Exchange, sandbox bool) (model.Client, error) {
 	if self.client == nil {
 		var err error
 		self.client, err = exchange.GetClient(model.PUBLIC, sandbox)
@@ -51,7 +134,27 @@ func (self *Listings) getClient(exchange model.Exchange, sandbox bool) (interfac
 	return self.client, nil
 }
 
+func (self *Listings) notify(exchange model.Exchange, msg string) {
+	log.Println("[INFO] " + msg)
+	if self.service != nil && notify.CanSend(self.level, notify.INFO) {
+		if err := self.service.SendMessage(msg, (exchange.GetInfo().Name + " - INFO"), model.ALWAYS); err != nil {
+			log.Printf("[ERROR] %v", err)
+		}
+	}
+}
+
+// listingIgnored returns true if a market is part of the --ignore arg
+func listingIgnored(market string, ignore []string) bool {
+	for _, name := range ignore {
+		if strings.EqualFold(market, name) {
+			return true
+		}
+	}
+	return false
+}
+
 func (self *Listings) GetMarkets(
+	ctx context.Context,
 	exchange model.Exchange,
 	quote model.Assets,
 	btcVolumeMin float64,
@@ -59,45 +162,87 @@ func (self *Listings) GetMarkets(
 	sandbox, debug bool,
 	ignore []string,
 ) (model.Markets, error) {
-	// get the (non-cached) markets
+	// get the (non-cached) markets. we keep track of every market, and then apply --ignore to the calls we emit, so that
+	// changing --ignore between runs doesn't look like (de)listings to us
 	var (
 		err error
 		new []model.Market
 	)
-	if new, err = exchange.GetMarkets(false, sandbox, ignore); err != nil {
+	if new, err = exchange.GetMarkets(ctx, false, sandbox, nil); err != nil {
 		return nil, err
 	}
 
-	// if we're unaware of any markets, set our starting point and wait for the next iteration
+	// load the markets we knew about before we got (re)started
 	if self.old == nil {
-		self.old = new
-		return nil, nil
+		if self.old, err = loadKnown(exchange, sandbox); err != nil {
+			return nil, err
+		}
+		// if we're unaware of any markets, set our starting point and wait for the next iteration
+		if self.old == nil {
+			self.old = &known{}
+			for _, market := range new {
+				self.old.Markets = append(self.old.Markets, market.Name)
+			}
+			return nil, self.old.save(exchange, sandbox)
+		}
+	}
+
+	// we don't know when exactly a market got listed while we were down, so assume the worst: right after we got stopped
+	since := time.Now()
+	if self.old.Updated.Before(since) {
+		since = self.old.Updated
 	}
 
+	changed := false
 	for _, market := range new {
 		// is this a new listing?
-		if model.IndexByMarket(self.old, market.Name) == -1 {
-			var client interface{}
+		if self.old.indexOf(market.Name) == -1 {
+			var client model.Client
 			client, err = self.getClient(exchange, sandbox)
 			if err == nil {
 				// do we actually have a ticker price yet?
 				var ticker float64
-				ticker, err = exchange.GetTicker(client, market.Name)
+				ticker, err = exchange.GetTicker(ctx, client, market.Name)
 				if err == nil && ticker > 0 {
-					if quote.HasAsset(market.Quote) {
-						self.cache = append(self.cache, Listing{
-							Market:  market.Name,
-							Price:   ticker,
-							Created: time.Now(),
-						})
+					self.notify(exchange, fmt.Sprintf("New listing: %s", market.Name))
+					if quote.HasAsset(market.Quote) && !listingIgnored(market.Name, ignore) {
+						if self.age > 0 && time.Since(since) > self.age {
+							log.Printf("[INFO] Ignoring %s because it got listed more than %v ago\n", market.Name, self.age)
+						} else {
+							self.cache = append(self.cache, Listing{
+								Market:  market.Name,
+								Price:   ticker,
+								Created: since,
+							})
+						}
 					}
 					// add this new market to the markets we're aware about
-					self.old = append(self.old, market)
+					self.old.Markets = append(self.old.Markets, market.Name)
+					changed = true
 				}
 			}
 		}
 	}
 
+	// is this a delisting?
+	i := 0
+	for i < len(self.old.Markets) {
+		if model.IndexByMarket(new, self.old.Markets[i]) == -1 {
+			self.notify(exchange, fmt.Sprintf("Delisted: %s", self.old.Markets[i]))
+			self.old.Markets = append(self.old.Markets[:i], self.old.Markets[i+1:]...)
+			changed = true
+		} else {
+			i++
+		}
+	}
+
+	// remember the markets we're aware about, so we can pick up where we left off after a restart
+	if changed || time.Since(self.old.Updated) > time.Minute {
+		if err = self.old.save(exchange, sandbox); err != nil {
+			return nil, err
+		}
+	}
+
 	// remove listings that are older than 1 hour
 	if valid > 0 {
 		i := 0
@@ -118,7 +263,7 @@ func (self *Listings) GetMarkets(
 	return out, nil
 }
 
-func (self *Listings) GetCalls(exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
+func (self *Listings) GetCalls(ctx context.Context, exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
 	var (
 		out model.Calls
 	)
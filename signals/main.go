@@ -20,6 +20,8 @@ func (signals *Signals) FindByName(name string) (model.Channel, error) {
 
 func New() *Signals {
 	var out Signals
+	out = append(out, NewBases())
+	out = append(out, NewConsensus())
 	out = append(out, NewCryptoBaseScanner())
 	out = append(out, NewListings())
 	out = append(out, NewMiningHamster())
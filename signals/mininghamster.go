@@ -2,6 +2,7 @@
 package signals
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"strings"
@@ -91,6 +92,7 @@ func (self *MiningHamster) GetOrderType() model.OrderType {
 }
 
 func (self *MiningHamster) GetMarkets(
+	ctx context.Context,
 	exchange model.Exchange,
 	quote model.Assets,
 	btcVolumeMin float64,
@@ -130,7 +132,7 @@ func (self *MiningHamster) GetMarkets(
 	return out, nil
 }
 
-func (self *MiningHamster) GetCalls(exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
+func (self *MiningHamster) GetCalls(ctx context.Context, exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
 	var (
 		out model.Calls
 	)
@@ -2,6 +2,7 @@
 package signals
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -16,6 +17,7 @@ import (
 	"github.com/svanas/nefertiti/model"
 	"github.com/svanas/nefertiti/passphrase"
 	"github.com/svanas/nefertiti/precision"
+	"github.com/svanas/nefertiti/transport"
 )
 
 // Possible values for risk_level are:
@@ -105,12 +107,12 @@ type CryptoQualitySignals struct {
 	cache  QualitySignals
 }
 
-func (self *CryptoQualitySignals) get(exchange model.Exchange, validity time.Duration, sandbox bool) error {
+func (self *CryptoQualitySignals) get(ctx context.Context, exchange model.Exchange, validity time.Duration, sandbox bool) error {
 	var err error
 
 	// create the request
 	var req *http.Request
-	if req, err = http.NewRequest("GET", "https://api.cryptoqualitysignals.com/v1/getSignal", nil); err != nil {
+	if req, err = http.NewRequestWithContext(ctx, "GET", "https://api.cryptoqualitysignals.com/v1/getSignal", nil); err != nil {
 		return err
 	}
 
@@ -128,7 +130,7 @@ func (self *CryptoQualitySignals) get(exchange model.Exchange, validity time.Dur
 
 	// submit the http request
 	var resp *http.Response
-	if resp, err = http.DefaultClient.Do(req); err != nil {
+	if resp, err = transport.New().Do(req); err != nil {
 		return err
 	}
 
@@ -168,7 +170,7 @@ func (self *CryptoQualitySignals) get(exchange model.Exchange, validity time.Dur
 	// mark the signals in the cache that have already pumped 5% -- we should be ignoring those
 	if len(self.cache) > 0 {
 		cache := make(map[string]float64)
-		var client interface{}
+		var client model.Client
 		if client, err = exchange.GetClient(model.PUBLIC, sandbox); err != nil {
 			return err
 		}
@@ -178,7 +180,7 @@ func (self *CryptoQualitySignals) get(exchange model.Exchange, validity time.Dur
 				market := self.cache[idx].Market(exchange)
 				ticker, ok := cache[market]
 				if !ok {
-					if ticker, err = exchange.GetTicker(client, market); err == nil {
+					if ticker, err = exchange.GetTicker(ctx, client, market); err == nil {
 						cache[market] = ticker
 					}
 				}
@@ -210,7 +212,7 @@ func (self *CryptoQualitySignals) Init() error {
 	}
 	if self.apiKey != API_KEY_FREE {
 		// validate the api key
-		resp, err := http.Get(fmt.Sprintf("https://premium.cryptoqualitysignals.com/api/validate/subscription/%s/5BB1713C16223", self.apiKey))
+		resp, err := transport.New().Get(fmt.Sprintf("https://premium.cryptoqualitysignals.com/api/validate/subscription/%s/5BB1713C16223", self.apiKey))
 		if err != nil {
 			return err
 		}
@@ -239,6 +241,7 @@ func (self *CryptoQualitySignals) GetOrderType() model.OrderType {
 }
 
 func (self *CryptoQualitySignals) GetMarkets(
+	ctx context.Context,
 	exchange model.Exchange,
 	quote model.Assets,
 	btcVolumeMin float64,
@@ -248,7 +251,7 @@ func (self *CryptoQualitySignals) GetMarkets(
 ) (model.Markets, error) {
 	var err error
 
-	if err = self.get(exchange, valid, sandbox); err != nil {
+	if err = self.get(ctx, exchange, valid, sandbox); err != nil {
 		return nil, err
 	}
 
@@ -285,19 +288,19 @@ func (self *CryptoQualitySignals) GetMarkets(
 	return out, nil
 }
 
-func (self *CryptoQualitySignals) GetCalls(exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
+func (self *CryptoQualitySignals) GetCalls(ctx context.Context, exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
 	var (
 		err error
 		out model.Calls
 	)
 
-	var client interface{}
+	var client model.Client
 	if client, err = exchange.GetClient(model.PUBLIC, sandbox); err != nil {
 		return nil, err
 	}
 
 	var prec int
-	if prec, err = exchange.GetPricePrec(client, market); err != nil {
+	if prec, err = exchange.GetPricePrec(ctx, client, market); err != nil {
 		return nil, err
 	}
 
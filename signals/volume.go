@@ -2,6 +2,7 @@
 package signals
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sort"
@@ -64,6 +65,7 @@ func (self *Volume) GetOrderType() model.OrderType {
 }
 
 func (self *Volume) get(
+	ctx context.Context,
 	exchange model.Exchange,
 	quote model.Assets,
 	btcVolumeMin,
@@ -89,7 +91,7 @@ func (self *Volume) get(
 	for _, token := range top {
 		if token.Buy(diff) {
 			var markets []model.Market
-			if markets, err = exchange.GetMarkets(true, sandbox, ignore); err != nil {
+			if markets, err = exchange.GetMarkets(ctx, true, sandbox, ignore); err != nil {
 				return err
 			}
 			for _, asset := range quote {
@@ -125,6 +127,7 @@ func (self *Volume) get(
 }
 
 func (self *Volume) GetMarkets(
+	ctx context.Context,
 	exchange model.Exchange,
 	quote model.Assets,
 	btcVolumeMin float64,
@@ -149,7 +152,7 @@ func (self *Volume) GetMarkets(
 		}
 	}
 
-	if err = self.get(exchange, quote, btcVolumeMin, diff, valid, sandbox, debug, ignore); err != nil {
+	if err = self.get(ctx, exchange, quote, btcVolumeMin, diff, valid, sandbox, debug, ignore); err != nil {
 		return nil, err
 	}
 
@@ -162,7 +165,7 @@ func (self *Volume) GetMarkets(
 	return out, nil
 }
 
-func (self *Volume) GetCalls(exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
+func (self *Volume) GetCalls(ctx context.Context, exchange model.Exchange, market string, sandbox, debug bool) (model.Calls, error) {
 	var out model.Calls
 	out = append(out, model.Call{
 		Buy: &model.Buy{
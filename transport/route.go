@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var (
+	routes     = make(map[string]*url.URL) // host -> the server we send its requests to instead
+	proxies    = make(map[string]*url.URL) // domain -> the proxy we send its requests through
+	routeMutex sync.RWMutex
+
+	proxied     http.RoundTripper
+	proxiedOnce sync.Once
+)
+
+// Redirect sends every request for a host to another server, for example: a stand-in server for integration testing.
+// The scheme and host of the request are replaced with those of the server, and its path (if any) is prepended.
+func Redirect(host string, to *url.URL) {
+	routeMutex.Lock()
+	defer routeMutex.Unlock()
+	routes[strings.ToLower(host)] = to
+}
+
+// SetProxy sends every request for a host (and the other hosts in its domain) through an http://, https:// or socks5:// proxy
+func SetProxy(host string, proxy *url.URL) {
+	routeMutex.Lock()
+	defer routeMutex.Unlock()
+	proxies[domain(host)] = proxy
+}
+
+// Proxy returns the proxy for a request: the one we got from SetProxy, or else the one in the HTTP_PROXY or HTTPS_PROXY
+// environment variable. Suitable for http.Transport.Proxy and websocket.Dialer.Proxy alike.
+func Proxy(req *http.Request) (*url.URL, error) {
+	routeMutex.RLock()
+	out, ok := proxies[domain(req.URL.Host)]
+	routeMutex.RUnlock()
+	if ok {
+		return out, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// route returns a copy of the request that has been redirected to another server, or the request itself if it hasn't
+func route(req *http.Request) *http.Request {
+	routeMutex.RLock()
+	to, ok := routes[strings.ToLower(req.URL.Host)]
+	routeMutex.RUnlock()
+	if !ok {
+		return req
+	}
+	out := req.Clone(req.Context())
+	out.URL.Scheme = to.Scheme
+	out.URL.Host = to.Host
+	out.URL.Path = strings.TrimSuffix(to.Path, "/") + req.URL.Path
+	if req.URL.RawPath != "" {
+		out.URL.RawPath = strings.TrimSuffix(to.Path, "/") + req.URL.RawPath
+	}
+	out.Host = ""
+	return out
+}
+
+// defaultTransport returns the round tripper we use if the caller did not give us one: a copy of http.DefaultTransport that knows about our proxies
+func defaultTransport() http.RoundTripper {
+	proxiedOnce.Do(func() {
+		if def, ok := http.DefaultTransport.(*http.Transport); ok {
+			clone := def.Clone()
+			clone.Proxy = Proxy
+			proxied = clone
+		} else {
+			proxied = http.DefaultTransport
+		}
+	})
+	return proxied
+}
+
+// domain returns the last two labels of a host name, for example: api1.binance.com -> binance.com
+func domain(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		return host
+	}
+	return strings.Join(labels[len(labels)-2:], ".")
+}
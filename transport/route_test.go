@@ -0,0 +1,36 @@
+package transport
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestRoute(t *testing.T) {
+	to, _ := url.Parse("http://localhost:8080/stand-in")
+	Redirect("www.bitstamp.net", to)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://www.bitstamp.net/api/v2/ticker/btceur/", nil)
+	if got, want := route(req).URL.String(), "http://localhost:8080/stand-in/api/v2/ticker/btceur/"; got != want {
+		t.Errorf("TestRoute failed, got: %s, want: %s.", got, want)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://api.kucoin.com/api/v1/timestamp", nil)
+	if got := route(req); got != req {
+		t.Errorf("TestRoute failed, got: %s, want: %s.", got.URL, req.URL)
+	}
+}
+
+func TestDomain(t *testing.T) {
+	for host, want := range map[string]string{
+		"api1.binance.com":        "binance.com",
+		"api.binance.com:443":     "binance.com",
+		"localhost:8080":          "localhost",
+		"127.0.0.1:8080":          "127.0.0.1",
+		"api.staging.woo.network": "woo.network",
+	} {
+		if got := domain(host); got != want {
+			t.Errorf("TestDomain(%s) failed, got: %s, want: %s.", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+//lint:file-ignore ST1006 receiver name should be a reflection of its identity; don't use generic names such as "this" or "self"
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+	"github.com/svanas/nefertiti/flag"
+)
+
+const DEFAULT_TIMEOUT = 30 * time.Second
+
+var (
+	root   context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+)
+
+func initialize() {
+	once.Do(func() {
+		root, cancel = context.WithCancel(context.Background())
+	})
+}
+
+// Context returns the root context: the one that every outgoing request runs in, and that the contexts we give to
+// the model.Exchange methods derive from. It is done once we are shutting down.
+func Context() context.Context {
+	initialize()
+	return root
+}
+
+// Cancel cancels the context that every outgoing request runs in, and with it every request in flight.
+func Cancel() {
+	initialize()
+	cancel()
+}
+
+// Done returns true if we are shutting down
+func Done() bool {
+	return Context().Err() != nil
+}
+
+// Timeout returns the deadline of every outgoing request, in the --timeout=X arg (in seconds). Defaults to 30 seconds.
+func Timeout() time.Duration {
+	if arg := flag.Get("timeout"); arg.Exists {
+		if secs, err := arg.Float64(); err == nil && secs > 0 {
+			return time.Duration(secs * float64(time.Second))
+		}
+	}
+	return DEFAULT_TIMEOUT
+}
+
+// Transport binds every request to our Context(), with a per-request deadline
+type Transport struct {
+	Base    http.RoundTripper
+	Context context.Context // if not nil, the requests are cancelled once this context is done, too
+}
+
+// WithContext returns a copy of an HTTP client whose requests are cancelled once ctx is done (or when we are shutting
+// down, or when they time out, whatever comes first).
+func WithContext(client *http.Client, ctx context.Context) *http.Client {
+	out := *client
+	if self, ok := client.Transport.(*Transport); ok {
+		clone := *self
+		clone.Context = ctx
+		out.Transport = &clone
+	} else {
+		out.Transport = &Transport{Base: client.Transport, Context: ctx}
+	}
+	return &out
+}
+
+// done returns the error of the context that ends our requests, or nil if we can keep going
+func (self *Transport) done() error {
+	if err := Context().Err(); err != nil {
+		return err
+	}
+	if self.Context != nil {
+		return self.Context.Err()
+	}
+	return nil
+}
+
+// body cancels the per-request context once the response body has been read (and closed)
+type body struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (self *body) Close() error {
+	defer self.cancel()
+	return self.ReadCloser.Close()
+}
+
+var (
+	holds      = make(map[string]time.Time) // per host: the time the server asked us to wait until (if it did)
+	holdsMutex sync.Mutex
+)
+
+// hold returns how long the server asked us to wait before we send another request to this host
+func hold(host string) time.Duration {
+	holdsMutex.Lock()
+	defer holdsMutex.Unlock()
+	if until, ok := holds[host]; ok {
+		if wait := time.Until(until); wait > 0 {
+			return wait
+		}
+		delete(holds, host)
+	}
+	return 0
+}
+
+// RoundTrip sends one request. We never try again here: the exchanges sign their requests with a timestamp that goes
+// stale, so the exchange package tries again with a request that is signed again. If the server sent us a Retry-After
+// header, then we hold on to the next request to that host until then (or fail if that takes longer than Timeout).
+func (self *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if wait := hold(req.URL.Host); wait > 0 {
+		if wait > Timeout() {
+			return nil, errors.Classify(errors.Errorf("%s %s: %s asked us to wait %v", req.Method, req.URL.Path, req.URL.Host, wait.Round(time.Second)), errors.RATE_LIMITED)
+		}
+		if err := self.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := self.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if after := retryAfter(resp); after > 0 && errors.ClassOfStatus(resp.StatusCode).Retryable() {
+		holdsMutex.Lock()
+		holds[req.URL.Host] = time.Now().Add(after)
+		holdsMutex.Unlock()
+	}
+	return resp, nil
+}
+
+// sleep waits, unless we are shutting down (or our own context is done, or the request is cancelled) before then
+func (self *Transport) sleep(ctx context.Context, wait time.Duration) error {
+	var bound <-chan struct{}
+	if self.Context != nil {
+		bound = self.Context.Done()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-Context().Done():
+	case <-bound:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return self.done()
+}
+
+// retryAfter returns the delay in the Retry-After header (if any), in seconds or as an HTTP date
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+func (self *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), Timeout())
+
+	// cancel this request when we are shutting down (or when our own context is done), even if the caller gave us a context of their own
+	var bound <-chan struct{}
+	if self.Context != nil {
+		bound = self.Context.Done()
+	}
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-Context().Done():
+			cancel()
+		case <-bound:
+			cancel()
+		case <-ctx.Done():
+		case <-stop:
+		}
+	}()
+	var done sync.Once
+	release := func() {
+		done.Do(func() {
+			close(stop)
+			cancel()
+		})
+	}
+
+	base := self.Base
+	if base == nil {
+		base = defaultTransport()
+	}
+
+	resp, err := base.RoundTrip(route(req).WithContext(ctx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &body{resp.Body, release}
+
+	return resp, nil
+}
+
+// New returns an HTTP client whose requests run in our Context(), and time out after Timeout()
+func New() *http.Client {
+	return &http.Client{
+		Transport: &Transport{},
+	}
+}
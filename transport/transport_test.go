@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/svanas/nefertiti/errors"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusTooManyRequests, http.StatusBadRequest} {
+		hits := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			hits++
+		}))
+		resp, err := New().Get(server.URL)
+		server.Close()
+		if err != nil {
+			t.Errorf("TestRoundTrip(%d) failed: %v", status, err)
+			continue
+		}
+		resp.Body.Close()
+		// we never try again: the exchanges do that, with a request that is signed again
+		if resp.StatusCode != status || hits != 1 {
+			t.Errorf("TestRoundTrip(%d) failed, got: %d after %d requests, want: %d after 1 request.", status, resp.StatusCode, hits, status)
+		}
+	}
+}
+
+func TestHold(t *testing.T) {
+	for _, test := range []struct {
+		after string // the Retry-After header of the first response
+		hits  int    // the number of requests the server gets
+		err   bool   // whether the second request fails without being sent
+	}{
+		{"1", 2, false},
+		{"3600", 1, true},
+	} {
+		hits := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hits == 0 {
+				w.Header().Set("Retry-After", test.after)
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+			hits++
+		}))
+		resp, err := New().Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+			start := time.Now()
+			resp, err = New().Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+				if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+					t.Errorf("TestHold(%s) failed, got: a response after %v, want: a response after 1s.", test.after, elapsed)
+				}
+			} else if errors.ClassOf(err) != errors.RATE_LIMITED {
+				t.Errorf("TestHold(%s) failed, got: %v, want: %v.", test.after, errors.ClassOf(err), errors.RATE_LIMITED)
+			}
+		}
+		server.Close()
+		if (err != nil) != test.err || hits != test.hits {
+			t.Errorf("TestHold(%s) failed, got: %v after %d requests, want: %d requests.", test.after, err, hits, test.hits)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"":    0,
+		"5":   5 * time.Second,
+		"abc": 0,
+	} {
+		resp := &http.Response{Header: http.Header{}}
+		if value != "" {
+			resp.Header.Set("Retry-After", value)
+		}
+		if got := retryAfter(resp); got != want {
+			t.Errorf("TestRetryAfter(%s) failed, got: %v, want: %v.", value, got, want)
+		}
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := WithContext(New(), ctx).Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("TestWithContext failed, got: %d, want: an error.", resp.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("TestWithContext failed, got: an error after %v, want: an error after 50ms.", elapsed)
+	}
+}
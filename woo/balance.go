@@ -0,0 +1,30 @@
+package woo
+
+import (
+	"encoding/json"
+)
+
+type Holding struct {
+	Token   string  `json:"token"`
+	Holding float64 `json:"holding"` // total, including frozen
+	Frozen  float64 `json:"frozen"`  // reserved for our open orders
+}
+
+// Holding returns the balances of every token we hold
+func (client *Client) Holding() ([]Holding, error) {
+	type Response struct {
+		Holding []Holding `json:"holding"`
+	}
+	var (
+		err  error
+		body []byte
+		resp Response
+	)
+	if body, err = client.get("/v2/client/holding", nil, true, 10); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Holding, nil
+}
@@ -1,6 +1,7 @@
 package woo
 
 import (
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -9,6 +10,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/svanas/nefertiti/transport"
 )
 
 var (
@@ -37,14 +40,24 @@ type Client struct {
 	httpClient *http.Client
 }
 
+// Exchange returns the name of the exchange this client talks to. It makes the client a model.Client.
+func (client *Client) Exchange() string {
+	return "Woo"
+}
+
+// WithContext returns a copy of the client whose requests are cancelled once ctx is done
+func (client *Client) WithContext(ctx context.Context) *Client {
+	out := *client
+	out.httpClient = transport.WithContext(client.httpClient, ctx)
+	return &out
+}
+
 func New(URL, apiKey, apiSecret string) *Client {
 	return &Client{
 		URL,
 		apiKey,
 		apiSecret,
-		&http.Client{
-			Timeout: 30 * time.Second,
-		},
+		transport.New(),
 	}
 }
 
@@ -64,8 +77,11 @@ func (client *Client) do(req *http.Request) ([]byte, error) {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
-		if err, msg := IsError(body); err {
-			return body, errors.New(msg)
+		if err, ok := IsError(body); ok {
+			if isTimestampError(err) {
+				Clock.Invalidate()
+			}
+			return body, err
 		}
 		return body, errors.New(resp.Status)
 	}
@@ -74,6 +90,11 @@ func (client *Client) do(req *http.Request) ([]byte, error) {
 }
 
 func (client *Client) get(path string, query url.Values, auth bool, rps float64) ([]byte, error) {
+	// sign our request with a timestamp that Woo agrees with
+	if auth {
+		client.syncTime()
+	}
+
 	// respect the rate limit
 	err := BeforeRequest(http.MethodGet, path, rps)
 	if err != nil {
@@ -101,7 +122,7 @@ func (client *Client) get(path string, query url.Values, auth bool, rps float64)
 
 	// add autentication headers
 	if auth {
-		timestamp := time.Now().UnixNano() / 1000000
+		timestamp := Clock.Now().UnixNano() / 1000000
 		req.Header.Add("x-api-key", client.apiKey)
 		req.Header.Add("x-api-signature", signature(client.apiSecret, query, timestamp))
 		req.Header.Add("x-api-timestamp", strconv.FormatInt(timestamp, 10))
@@ -112,6 +133,9 @@ func (client *Client) get(path string, query url.Values, auth bool, rps float64)
 }
 
 func (client *Client) call(method, path string, params url.Values, rps float64) ([]byte, error) {
+	// sign our request with a timestamp that Woo agrees with
+	client.syncTime()
+
 	// respect the rate limit
 	err := BeforeRequest(method, path, rps)
 	if err != nil {
@@ -146,7 +170,7 @@ func (client *Client) call(method, path string, params url.Values, rps float64)
 	}
 
 	// add autentication headers
-	timestamp := time.Now().UnixNano() / 1000000
+	timestamp := Clock.Now().UnixNano() / 1000000
 	req.Header.Add("x-api-key", client.apiKey)
 	req.Header.Add("x-api-signature", signature(client.apiSecret, params, timestamp))
 	req.Header.Add("x-api-timestamp", strconv.FormatInt(timestamp, 10))
@@ -6,17 +6,22 @@ import (
 
 type Error struct {
 	Success bool   `json:"success"`
+	Code    int    `json:"code"`
 	Message string `json:"message"`
 }
 
+func (err *Error) Error() string {
+	return err.Message
+}
+
 func (err *Error) Failure() bool {
 	return !err.Success && err.Message != ""
 }
 
-func IsError(response []byte) (bool, string) {
+func IsError(response []byte) (*Error, bool) {
 	var err Error
-	if json.Unmarshal(response, &err) == nil {
-		return err.Failure(), err.Message
+	if json.Unmarshal(response, &err) == nil && err.Failure() {
+		return &err, true
 	}
-	return false, ""
+	return nil, false
 }
@@ -20,8 +20,11 @@ const (
 )
 
 const (
-	OrderTypeLimit  OrderType = "LIMIT"
-	OrderTypeMarket OrderType = "MARKET"
+	OrderTypeLimit    OrderType = "LIMIT"
+	OrderTypeMarket   OrderType = "MARKET"
+	OrderTypeIOC      OrderType = "IOC"       // a limit order that is immediate or cancel
+	OrderTypeFOK      OrderType = "FOK"       // a limit order that is fill or kill
+	OrderTypePostOnly OrderType = "POST_ONLY" // a limit order that gets cancelled if it would be filled immediately
 )
 
 const (
@@ -48,7 +51,7 @@ func (client *Client) Order(symbol string, side OrderSide, orderType OrderType,
 	params.Add("order_type", string(orderType))
 	params.Add("order_quantity", strconv.FormatFloat(quantity, 'f', -1, 64))
 	params.Add("side", string(side))
-	if orderType == OrderTypeLimit {
+	if orderType != OrderTypeMarket {
 		params.Add("order_price", strconv.FormatFloat(price, 'f', -1, 64))
 	}
 	if tag != "" {
@@ -132,6 +135,21 @@ func (order *Order) QuantityMinusFee() float64 {
 	return executed
 }
 
+func (client *Client) GetOrder(orderID int64) (*Order, error) {
+	var (
+		err  error
+		body []byte
+		out  Order
+	)
+	if body, err = client.get("/v1/order/"+strconv.FormatInt(orderID, 10), url.Values{}, true, 10); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 type Orders struct {
 	Meta struct {
 		RecordsPerPage int64 `json:"records_per_page"`
@@ -0,0 +1,53 @@
+package woo
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/svanas/nefertiti/clock"
+)
+
+// Clock keeps track of the offset between our clock and the Woo clock
+var Clock = clock.New("Woo")
+
+// ServerTime returns the API server time, as per the Date header of a public endpoint. Because the Date header is
+// rounded down to the second, we add half a second.
+func (client *Client) ServerTime() (time.Time, error) {
+	const path = "/v1/public/system_info"
+
+	// respect the rate limit
+	err := BeforeRequest(http.MethodGet, path, 10)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() {
+		AfterRequest()
+	}()
+
+	resp, err := client.httpClient.Get(client.URL + path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	out, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return out.Add(500 * time.Millisecond), nil
+}
+
+// syncTime measures the offset between our clock and the Woo clock, unless we did so lately.
+func (client *Client) syncTime() {
+	if err := Clock.Sync(client.ServerTime); err != nil {
+		log.Printf("[WARNING] %v\n", err)
+	}
+}
+
+// isTimestampError returns true if Woo rejected our request because of its timestamp
+func isTimestampError(err *Error) bool {
+	return strings.Contains(strings.ToLower(err.Message), "timestamp")
+}